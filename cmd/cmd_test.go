@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
+	"asset-manager/core/reconcile"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +50,34 @@ func TestFlags(t *testing.T) {
 	jsonFlag := furnitureCmd.Flags().Lookup("json")
 	assert.NotNil(t, jsonFlag)
 }
+
+func TestProgressBarRender(t *testing.T) {
+	var out strings.Builder
+	bar := &progressBar{out: &out, counts: make(map[reconcile.Phase][2]int)}
+
+	bar.update(reconcile.PhaseLoadStorage, 2000, 0)
+	bar.update(reconcile.PhaseLoadGamedata, 10, 10)
+	bar.update(reconcile.PhaseApply, 15, 30)
+
+	assert.Equal(t, "load_gamedata 10/10 | load_storage 2000 | apply [###############---------------] 15/30", bar.render())
+	assert.True(t, strings.HasPrefix(out.String(), "\r"))
+
+	bar.Finish()
+	assert.True(t, strings.HasSuffix(out.String(), "\n"))
+
+	var nilBar *progressBar
+	assert.Nil(t, nilBar.Observer())
+}
+
+func TestFurnitureSearchCmd(t *testing.T) {
+	// "furniture search <query>" resolves to the subcommand, other arguments to the detail view
+	cmd, args, err := RootCmd.Find([]string{"furniture", "search", "chair"})
+	assert.NoError(t, err)
+	assert.Equal(t, furnitureSearchCmd, cmd)
+	assert.Equal(t, []string{"chair"}, args)
+	assert.NotNil(t, furnitureSearchCmd.Flags().Lookup("limit"))
+
+	cmd, _, err = RootCmd.Find([]string{"furniture", "chair_polyfon"})
+	assert.NoError(t, err)
+	assert.Equal(t, furnitureDetailCmd, cmd)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"text/tabwriter"
 
 	"asset-manager/core/config"
 	"asset-manager/core/database"
@@ -27,11 +28,27 @@ var furnitureDetailCmd = &cobra.Command{
 	},
 }
 
+// furnitureSearchCmd searches furniture by ID, classname or name
+var furnitureSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search furniture by ID, classname or name",
+	Long:  `Finds furniture whose ID, classname or name matches the query exactly, by prefix, by substring or within a small edit distance (typos).`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		runFurnitureSearch(cmd.Context(), args[0], limit)
+	},
+}
+
 func init() {
+	furnitureSearchCmd.Flags().Int("limit", 20, "Maximum number of results")
+	furnitureDetailCmd.AddCommand(furnitureSearchCmd)
 	RootCmd.AddCommand(furnitureDetailCmd)
 }
 
-func runFurnitureDetailCheck(ctx context.Context, identifier string) {
+// newFurnitureService builds the furniture service from the configuration.
+// The database is optional; exits on any other failure.
+func newFurnitureService() (*furniture.Service, *zap.Logger) {
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
@@ -58,7 +75,11 @@ func runFurnitureDetailCheck(ctx context.Context, identifier string) {
 		logg = logg.With(zap.String("server", cfg.Server.Emulator))
 	}
 
-	svc := furniture.NewService(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator)
+	return furniture.NewService(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator), logg
+}
+
+func runFurnitureDetailCheck(ctx context.Context, identifier string) {
+	svc, logg := newFurnitureService()
 
 	logg.Info("Checking furniture item...", zap.String("identifier", identifier))
 	report, err := svc.GetFurnitureDetail(ctx, identifier)
@@ -96,3 +117,31 @@ func runFurnitureDetailCheck(ctx context.Context, identifier string) {
 	}
 	fmt.Println("-----------------------------")
 }
+
+func runFurnitureSearch(ctx context.Context, query string, limit int) {
+	svc, logg := newFurnitureService()
+
+	hits, err := svc.SearchFurniture(ctx, query, limit)
+	if err != nil {
+		logg.Fatal("Furniture search failed", zap.Error(err))
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("No furniture matches %q\n", query)
+		return
+	}
+
+	fmt.Printf("\n--- Furniture matching %q ---\n", query)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCLASSNAME\tNAME\tMATCH\tDB\tGAMEDATA\tSTORAGE")
+	for _, hit := range hits {
+		match := string(hit.Match)
+		if hit.Distance > 0 {
+			match = fmt.Sprintf("%s (%d)", match, hit.Distance)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%v\t%v\n",
+			hit.ID, hit.Metadata["classname"], hit.Name, match,
+			hit.DBPresent, hit.GamedataPresent, hit.StoragePresent)
+	}
+	w.Flush()
+}
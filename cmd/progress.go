@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/reconcile"
+)
+
+// progressBarWidth is the number of cells of the apply progress bar.
+const progressBarWidth = 30
+
+// progressPhases is the display order of reconcile phases.
+var progressPhases = []reconcile.Phase{
+	reconcile.PhaseLoadDB,
+	reconcile.PhaseLoadGamedata,
+	reconcile.PhaseLoadStorage,
+	reconcile.PhaseApply,
+}
+
+// progressBar renders reconcile progress on a single terminal line.
+type progressBar struct {
+	mu       sync.Mutex
+	out      io.Writer
+	counts   map[reconcile.Phase][2]int
+	last     time.Time
+	interval time.Duration
+	width    int
+}
+
+// newProgressBar returns an observer drawing progress to stderr, or nil when stderr
+// is not a terminal so logs piped to files stay clean.
+func newProgressBar() *progressBar {
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return &progressBar{
+		out:      os.Stderr,
+		counts:   make(map[reconcile.Phase][2]int),
+		interval: 100 * time.Millisecond,
+	}
+}
+
+// Observer returns the reconcile.ProgressObserver updating the bar, or nil for a nil bar.
+func (p *progressBar) Observer() reconcile.ProgressObserver {
+	if p == nil {
+		return nil
+	}
+	return p.update
+}
+
+// update records a progress report and redraws the line, throttled to the refresh interval.
+func (p *progressBar) update(phase reconcile.Phase, processed, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.counts[phase] = [2]int{processed, total}
+
+	done := total > 0 && processed >= total
+	if !done && time.Since(p.last) < p.interval {
+		return
+	}
+	p.last = time.Now()
+
+	line := p.render()
+	// Pad to erase leftovers of a longer previous line
+	pad := ""
+	if len(line) < p.width {
+		pad = strings.Repeat(" ", p.width-len(line))
+	}
+	p.width = len(line)
+	fmt.Fprintf(p.out, "\r%s%s", line, pad)
+}
+
+// render formats the current state of every reported phase.
+func (p *progressBar) render() string {
+	var parts []string
+	for _, phase := range progressPhases {
+		c, ok := p.counts[phase]
+		if !ok {
+			continue
+		}
+		processed, total := c[0], c[1]
+
+		switch {
+		case phase == reconcile.PhaseApply && total > 0:
+			filled := processed * progressBarWidth / total
+			parts = append(parts, fmt.Sprintf("%s [%s%s] %d/%d", phase,
+				strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), processed, total))
+		case total > 0:
+			parts = append(parts, fmt.Sprintf("%s %d/%d", phase, processed, total))
+		default:
+			parts = append(parts, fmt.Sprintf("%s %d", phase, processed))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Finish ends the progress line so following output starts on a new line.
+func (p *progressBar) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.width > 0 {
+		fmt.Fprintln(p.out)
+		p.width = 0
+	}
+	p.counts = make(map[reconcile.Phase][2]int)
+}
@@ -24,6 +24,14 @@ var (
 	syncFurniture   bool
 	dryRunFurniture bool
 	yesConfirm      bool
+	noJournal       bool
+	forceFurniture  bool
+	createMissingDB bool
+	createMissingGD bool
+	enrichBundles   bool
+	purgeFrom       []string
+	purgePresence   []string
+	fullFurniture   bool
 )
 
 // reconcileCmd is the parent command for all reconcile operations.
@@ -57,19 +65,57 @@ Examples:
   reconcile furniture --sync --yes
 
   # Both purge and sync
-  reconcile furniture --purge --sync --yes`,
+  reconcile furniture --purge --sync --yes
+
+  # Only remove orphaned storage files (present in storage only)
+  reconcile furniture --purge --purge-from=storage --purge-presence=storage
+
+  # Create DB rows for items present in gamedata and storage
+  reconcile furniture --create-missing-db --yes
+
+  # Create gamedata entries for items present in the DB and storage
+  reconcile furniture --create-missing-gamedata --yes
+
+  # Only drop DB rows without a bundle
+  reconcile furniture --purge --purge-from=db --purge-presence=db+gamedata,db
+
+Every applied run records a journal that can be reverted with 'reconcile undo'.
+
+Purges exceeding the RECONCILE_* safety thresholds (maximum deletions, or an
+empty source) are refused unless --force is given.`,
 	RunE: runFurnitureReconcile,
 }
 
+// reconcileUndoCmd reverts the mutations recorded in a reconcile journal.
+var reconcileUndoCmd = &cobra.Command{
+	Use:   "undo [journal-id]",
+	Short: "Undo the mutations recorded in a reconcile journal",
+	Long: `Restores deleted database rows, gamedata entries and storage objects, and reverts
+synced database rows, as recorded in the journal of a previous 'reconcile furniture' run.
+
+Example:
+  reconcile undo 3f2b8c1e-6a0d-4f3b-9c55-1d2e3f4a5b6c`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReconcileUndo,
+}
+
 func init() {
-	// Add furniture command to reconcile
-	reconcileCmd.AddCommand(furnitureReconcileCmd)
+	// Add subcommands to reconcile
+	reconcileCmd.AddCommand(furnitureReconcileCmd, reconcileUndoCmd)
 
 	// Add flags
 	furnitureReconcileCmd.Flags().BoolVar(&purgeFurniture, "purge", false, "Enable purge (delete items missing in any store)")
 	furnitureReconcileCmd.Flags().BoolVar(&syncFurniture, "sync", false, "Enable sync (update DB fields from gamedata)")
 	furnitureReconcileCmd.Flags().BoolVar(&dryRunFurniture, "dry-run", false, "Force dry-run (no mutations even with --yes)")
 	furnitureReconcileCmd.Flags().BoolVar(&yesConfirm, "yes", false, "Auto-confirm destructive actions (non-interactive)")
+	furnitureReconcileCmd.Flags().BoolVar(&noJournal, "no-journal", false, "Do not record an undo journal (storage deletions are not quarantined)")
+	furnitureReconcileCmd.Flags().BoolVar(&forceFurniture, "force", false, "Override the safety thresholds for destructive runs")
+	furnitureReconcileCmd.Flags().BoolVar(&createMissingDB, "create-missing-db", false, "Create DB rows from gamedata for items missing only in the database")
+	furnitureReconcileCmd.Flags().BoolVar(&createMissingGD, "create-missing-gamedata", false, "Create gamedata entries from DB rows for items missing only in gamedata")
+	furnitureReconcileCmd.Flags().BoolVar(&enrichBundles, "enrich-from-bundles", false, "Download the bundles of created gamedata entries to take their model dimensions")
+	furnitureReconcileCmd.Flags().StringSliceVar(&purgeFrom, "purge-from", nil, "Only purge from these stores (db, gamedata, storage)")
+	furnitureReconcileCmd.Flags().BoolVar(&fullFurniture, "full", false, "Reload every source instead of only what changed since the last snapshot")
+	furnitureReconcileCmd.Flags().StringSliceVar(&purgePresence, "purge-presence", nil, "Only purge items present in exactly these stores, joined by '+' (e.g. storage, db+gamedata)")
 
 	// Add reconcile to root
 	RootCmd.AddCommand(reconcileCmd)
@@ -107,8 +153,8 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 	// Create furniture adapter
 	adapter := furnitureReconcile.NewAdapter()
 
-	// Set mutation context for purge/sync
-	if purgeFurniture || syncFurniture {
+	// Set mutation context for purge/sync/create
+	if purgeFurniture || syncFurniture || createMissingDB || createMissingGD {
 		adapter.SetMutationContext(
 			db,
 			client,
@@ -119,23 +165,56 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	// Only download bundles when asked to: every created entry would fetch one
+	adapter.SetBundleEnrichment(enrichBundles)
+
+	// Soft-delete purged storage objects into the trash
+	if purgeFurniture && cfg.Storage.TrashEnabled {
+		adapter.SetTrash(storage.NewTrash(client, cfg.Storage.Bucket, cfg.Storage.TrashRetention()))
+	}
+
 	// Build spec
-	spec := &reconcile.Spec{
-		Adapter:            adapter,
-		CacheTTL:           0, // No caching to prevent stale data after DB changes
-		StoragePrefix:      "bundled/furniture",
-		StorageExtension:   ".nitro",
-		GamedataPaths:      []string{}, // Not used, loads full JSON
-		GamedataObjectName: "gamedata/FurnitureData.json",
-		ServerProfile:      cfg.Server.Emulator,
+	spec := newFurnitureSpec(adapter, cfg.Server.Emulator)
+
+	// Creating gamedata needs the bundles of DB rows missing in gamedata linked to them
+	spec.LinkStorage = createMissingGD
+
+	// Only reload what changed since the previous run, unless asked not to
+	if !fullFurniture {
+		spec.Snapshots = cfg.ReconcileSnapshot.Store()
+	}
+
+	// Live progress on terminals
+	progress := newProgressBar()
+	spec.Progress = progress.Observer()
+
+	// Parse purge scopes
+	sources, err := reconcile.ParseSources(purgeFrom)
+	if err != nil {
+		return fmt.Errorf("invalid --purge-from: %w", err)
+	}
+
+	presence := make([]reconcile.Presence, 0, len(purgePresence))
+	for _, pattern := range purgePresence {
+		p, err := reconcile.ParsePresence(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid --purge-presence: %w", err)
+		}
+		presence = append(presence, p)
 	}
 
 	// Build reconcile options
 	opts := reconcile.ReconcileOptions{
-		DoPurge:   purgeFurniture,
-		DoSync:    syncFurniture,
-		DryRun:    dryRunFurniture,
-		Confirmed: false, // Will be set after confirmation prompt
+		DoPurge:          purgeFurniture,
+		PurgeFrom:        sources,
+		PurgePresence:    presence,
+		DoSync:           syncFurniture,
+		DoCreateDB:       createMissingDB,
+		DoCreateGamedata: createMissingGD,
+		DryRun:           dryRunFurniture,
+		Confirmed:        false, // Will be set after confirmation prompt
+		Safety:           cfg.Reconcile,
+		Force:            forceFurniture,
 	}
 
 	// Step 0: Prepare Schema (Auto-fix limits)
@@ -147,16 +226,23 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 	// Step 1: Plan (always runs)
 	l.Info("Planning reconciliation...")
 	plan, err := reconcile.ReconcileWithPlan(ctx, spec, db, client, cfg.Storage.Bucket, opts)
+	progress.Finish()
 	if err != nil {
 		return fmt.Errorf("failed to plan reconciliation: %w", err)
 	}
+	if plan.Delta != nil {
+		l.Info("Indices loaded", zap.Stringer("delta", plan.Delta))
+		if plan.Delta.SnapshotErr != nil {
+			l.Warn("Failed to save reconcile snapshot; the next run reloads every source", zap.Error(plan.Delta.SnapshotErr))
+		}
+	}
 
 	// Step 2: Print report
 	printReconcileReport(l, plan)
 
 	// Step 3: Check if actions are requested
-	if !purgeFurniture && !syncFurniture {
-		l.Info("No actions requested. Use --purge to delete incomplete items or --sync to repair mismatches.")
+	if !purgeFurniture && !syncFurniture && !createMissingDB && !createMissingGD {
+		l.Info("No actions requested. Use --purge to delete incomplete items, --sync to repair mismatches or --create-missing-db/--create-missing-gamedata to create missing entries.")
 		return nil
 	}
 
@@ -168,18 +254,33 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		// Refuse before prompting if the guard rails are exceeded
+		if plan.Summary.BlockedReason != "" && !forceFurniture {
+			return fmt.Errorf("refusing to apply plan: %s (use --force to override)", plan.Summary.BlockedReason)
+		}
+
 		// Check confirmation
-		confirmed := confirmDestructiveAction()
+		confirmed := confirmDestructiveAction(yesConfirm)
 		if !confirmed {
 			l.Warn("Operation cancelled by user. No changes were made.")
 			return nil
 		}
 
 		opts.Confirmed = true
+		if !noJournal {
+			opts.Journal = reconcile.NewJournal(spec)
+		}
 
 		// Execute actions
 		l.Info("Applying actions...")
 		executed, err := reconcile.ApplyPlan(ctx, spec, db, client, cfg.Storage.Bucket, plan, opts)
+		progress.Finish()
+		if opts.Journal != nil && len(opts.Journal.Entries) > 0 {
+			l.Info("Journal recorded, run 'reconcile undo' with this ID to revert",
+				zap.String("journal_id", opts.Journal.ID),
+				zap.Int("entries", len(opts.Journal.Entries)),
+			)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to apply plan: %w", err)
 		}
@@ -192,6 +293,70 @@ func runFurnitureReconcile(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runReconcileUndo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	journalID := args[0]
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+
+	adapter := furnitureReconcile.NewAdapter()
+	adapter.SetMutationContext(
+		db,
+		client,
+		cfg.Storage.Bucket,
+		"bundled/furniture",
+		cfg.Server.Emulator,
+		"gamedata/FurnitureData.json",
+	)
+	spec := newFurnitureSpec(adapter, cfg.Server.Emulator)
+
+	l.Info("Undoing reconcile journal", zap.String("journal_id", journalID))
+
+	journal, restored, err := reconcile.UndoJournal(ctx, spec, client, cfg.Storage.Bucket, journalID)
+	if err != nil {
+		return fmt.Errorf("failed to undo journal: %w", err)
+	}
+
+	l.Info("Journal undone",
+		zap.String("journal_id", journal.ID),
+		zap.Time("created_at", journal.CreatedAt),
+		zap.Int("restored", restored),
+	)
+
+	return nil
+}
+
+// newFurnitureSpec builds the reconcile spec used by the furniture CLI commands.
+func newFurnitureSpec(adapter *furnitureReconcile.FurnitureAdapter, emulator string) *reconcile.Spec {
+	return &reconcile.Spec{
+		Adapter:            adapter,
+		CacheTTL:           0, // No caching to prevent stale data after DB changes
+		StoragePrefix:      "bundled/furniture",
+		StorageExtension:   ".nitro",
+		GamedataPaths:      []string{}, // Not used, loads full JSON
+		GamedataObjectName: "gamedata/FurnitureData.json",
+		ServerProfile:      emulator,
+	}
+}
+
 // printReconcileReport prints a formatted reconciliation report using logger.
 func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 	s := plan.Summary
@@ -204,10 +369,24 @@ func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 		zap.Int("mismatches", s.Mismatches),
 	)
 
+	if s.BlockedReason != "" {
+		l.Warn("Plan exceeds safety thresholds",
+			zap.String("reason", s.BlockedReason),
+			zap.Bool("forced", s.Forced),
+		)
+	}
+
+	if s.SharedStorageKept > 0 {
+		l.Info("Storage objects still used by other entities are kept",
+			zap.Int("shared_storage_kept", s.SharedStorageKept),
+		)
+	}
+
 	if len(plan.Actions) > 0 {
 		l.Info("Planned actions",
 			zap.Int("purge_actions", s.PurgeActions),
 			zap.Int("sync_actions", s.SyncActions),
+			zap.Int("create_actions", s.CreateActions),
 			zap.Int("total_actions", len(plan.Actions)),
 		)
 
@@ -232,9 +411,10 @@ func printReconcileReport(l *zap.Logger, plan *reconcile.ReconcilePlan) {
 	}
 }
 
-// confirmDestructiveAction prompts the user for confirmation or uses --yes flag.
-func confirmDestructiveAction() bool {
-	if yesConfirm {
+// confirmDestructiveAction prompts the user for confirmation, unless yes is set by the
+// command's --yes flag.
+func confirmDestructiveAction(yes bool) bool {
+	if yes {
 		fmt.Println("\n✓ Auto-confirmed via --yes flag")
 		return true
 	}
@@ -1,21 +1,32 @@
 package cmd
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"asset-manager/core/config"
 	"asset-manager/core/database"
+	"asset-manager/core/events"
+	"asset-manager/core/jobs"
 	"asset-manager/core/loader"
 	"asset-manager/core/logger"
+	"asset-manager/core/metrics"
 	"asset-manager/core/middleware/auth"
 	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
+	"asset-manager/core/tracing"
 
 	"asset-manager/feature/furniture"
+	"asset-manager/feature/health"
 	"asset-manager/feature/integrity"
+	jobsFeature "asset-manager/feature/jobs"
+	"asset-manager/feature/notify"
+	"asset-manager/feature/stream"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
@@ -52,21 +63,61 @@ var startCmd = &cobra.Command{
 		defer logg.Sync()
 		zap.ReplaceGlobals(logg)
 
+		// 2.5 Prometheus Metrics (Optional)
+		var m *metrics.Metrics
+		if cfg.Metrics.Enabled {
+			m = metrics.New()
+			reconcile.SetRunObserver(m)
+		}
+
+		// 2.6 OpenTelemetry Tracing (Optional)
+		var tr *tracing.Tracing
+		if cfg.Tracing.Enabled {
+			tr, err = tracing.New(context.Background(), cfg.Tracing)
+			if err != nil {
+				logg.Fatal("Failed to create tracer", zap.Error(err))
+			}
+			reconcile.SetTracerProvider(tr.Provider())
+		}
+
 		// 3. Connect to Database (Optional)
-		// We use the emulator name as the "server" field.
-		var db *gorm.DB
-		if conn, err := database.Connect(cfg.Database); err != nil {
+		// While unreachable, it is reconnected in the background (DATABASE_RECONNECT_*) and
+		// swapped into the features; DB-dependent routes answer 503 until then.
+		dbm := database.NewManager(cfg.Database)
+		dbm.OnConnect(func(db *gorm.DB) {
+			logg.Info("Connected to emulator database")
+			if m != nil {
+				if err := m.InstrumentDB(db); err != nil {
+					logg.Warn("Failed to instrument database", zap.Error(err))
+				}
+			}
+			if tr != nil {
+				if err := tr.InstrumentDB(db); err != nil {
+					logg.Warn("Failed to trace database", zap.Error(err))
+				}
+			}
+		})
+		if err := dbm.Connect(); err != nil {
 			logg.Warn("Optional database connection failed", zap.Error(err))
 		} else {
-			db = conn
 			// If succeeded, inject "server" field into logger
+			// We use the emulator name as the "server" field.
 			logg = logg.With(zap.String("server", cfg.Server.Emulator))
-			logg.Info("Connected to emulator database")
 		}
+		db := dbm.DB()
 
 		// 3. Initialize Fiber App
+		// Behind a reverse proxy, the client IP comes from its header
+		proxies, err := cfg.Server.TrustedProxyList()
+		if err != nil {
+			logg.Fatal("Invalid proxy configuration", zap.Error(err))
+		}
 		app := fiber.New(fiber.Config{
-			DisableStartupMessage: true, // We will log our own startup message
+			DisableStartupMessage:   true, // We will log our own startup message
+			ProxyHeader:             cfg.Server.ProxyHeader,
+			EnableTrustedProxyCheck: len(proxies) > 0,
+			TrustedProxies:          proxies,
+			EnableIPValidation:      true,
 		})
 
 		// 3. Initialize Storage
@@ -74,18 +125,96 @@ var startCmd = &cobra.Command{
 		if err != nil {
 			logg.Fatal("Failed to create storage client", zap.Error(err))
 		}
+		storageObservers := []storage.CallObserver{storage.SlowCallLogger(logg, cfg.Storage.SlowCallThreshold(), rayid.FromContext)}
+		if m != nil {
+			storageObservers = append(storageObservers, m)
+		}
+		if tr != nil {
+			storageObservers = append(storageObservers, tr)
+		}
+		store = storage.Wrap(store, storage.Instrument(storageObservers...))
 
 		// 4. Initialize Feature Loader
 		mgr := loader.NewManager()
 
 		// Register Features
-		mgr.Register(integrity.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator))
-		mgr.Register(furniture.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator))
+		integrityFeature := integrity.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator)
+		furnitureFeature := furniture.NewFeature(store, cfg.Storage.Bucket, logg, db, cfg.Server.Emulator)
+		mgr.Register(integrityFeature)
+		mgr.Register(furnitureFeature)
+
+		// Reconcile over HTTP uses the same guard rails and trash as the CLI
+		var trash *storage.Trash
+		if cfg.Storage.TrashEnabled {
+			trash = storage.NewTrash(store, cfg.Storage.Bucket, cfg.Storage.TrashRetention())
+		}
+		furnitureFeature.SetReconcileConfig(cfg.Reconcile, cfg.ReconcilePlan, trash)
+		furnitureFeature.SetCacheConfig(cfg.ReconcileCache, cfg.ReconcileSnapshot)
+
+		// Warm restarts: reuse the indices built before the restart while within their TTL
+		cachePersister, err := reconcile.NewCachePersister(cfg.ReconcileCache)
+		if err != nil {
+			logg.Fatal("Failed to create reconcile cache store", zap.Error(err))
+		}
+		if cachePersister != nil {
+			reconcile.SetCachePersister(cachePersister, func(cacheKey string, err error) {
+				logg.Warn("Failed to persist reconcile cache", zap.String("cache_key", cacheKey), zap.Error(err))
+			})
+			restored, err := reconcile.RestoreCaches()
+			if err != nil {
+				logg.Warn("Failed to restore reconcile caches", zap.Error(err))
+			} else {
+				logg.Info("Restored reconcile caches", zap.Int("count", restored), zap.String("store", cfg.ReconcileCache.Store))
+			}
+		}
+
+		// Background work (cache refresh, database reconnection) stops on shutdown
+		bgCtx, stopBackground := context.WithCancel(context.Background())
+		defer stopBackground()
+
+		// Swap the database into the features once connected, and keep the listing
+		// indices warm in the background (RECONCILE_CACHE_REFRESH_SECONDS)
+		dbm.OnConnect(func(db *gorm.DB) {
+			integrityFeature.SetDB(db)
+			furnitureFeature.SetDB(db)
+			furnitureFeature.StartCacheRefresher(bgCtx)
+		})
+		dbm.Start(bgCtx, func(attempt int, next time.Duration, err error) {
+			logg.Warn("Database reconnection failed",
+				zap.Int("attempt", attempt), zap.Duration("retry_in", next), zap.Error(err))
+		})
+
+		// Live progress for the admin panel (SSE)
+		hub := events.NewHub(256)
+		integrityFeature.SetEvents(hub)
+		furnitureFeature.SetEvents(hub)
+		mgr.Register(stream.NewFeature(hub, logg))
+
+		// 4.5 Initialize Background Jobs
+		jobStore, err := jobs.NewStore(cfg.Jobs)
+		if err != nil {
+			logg.Fatal("Failed to create job store", zap.Error(err))
+		}
+		jobManager := jobs.NewManager(cfg.Jobs, jobStore, logg)
+		integrityFeature.RegisterJobs(jobManager)
+		furnitureFeature.RegisterJobs(jobManager)
+		jobManager.Start()
+		mgr.Register(jobsFeature.NewFeature(jobManager, logg))
 
 		// Middleware Registration
 		// 1. RayID (Must be first to trace everything)
 		app.Use(rayid.New())
 
+		// 1.2 Tracing (continues the traceparent header, or a trace named after the Ray ID)
+		if tr != nil {
+			app.Use(tr.Middleware())
+		}
+
+		// 1.5 Metrics (times every request, including rejected ones)
+		if m != nil {
+			app.Use(m.Middleware())
+		}
+
 		// 2. Logging Middleware (Custom to use Zap + RayID)
 		app.Use(func(c *fiber.Ctx) error {
 			// Attach logger to locals? or just log request here?
@@ -97,9 +226,12 @@ var startCmd = &cobra.Command{
 				zap.String("ip", c.IP()),
 			)
 			err := c.Next()
-			// Log error if happened
+			// Log error if happened; the logger now names the API key, if authenticated
+			l = logger.WithRayID(logg, c)
 			if err != nil {
 				l.Error("Request error", zap.Error(err))
+			} else {
+				l.Info("Request completed", zap.Int("status", c.Response().StatusCode()))
 			}
 			return err
 		})
@@ -107,9 +239,46 @@ var startCmd = &cobra.Command{
 		// 2.5 Swagger Documentation (Public)
 		app.Get("/swagger/*", swagger.HandlerDefault)
 
+		// 2.6 Bucket notifications (own token, MinIO cannot send the API key header)
+		// and health probes (public, report the features loaded behind auth)
+		publicMgr := loader.NewManager()
+		publicMgr.Register(notify.NewFeature(cfg.Storage, logg, furnitureFeature))
+		publicMgr.Register(health.NewFeature(store, cfg.Storage.Bucket, dbm.Get, cfg.Server.Emulator, mgr, logg))
+		if err := publicMgr.LoadAll(app); err != nil {
+			logg.Fatal("Failed to load public features", zap.Error(err))
+		}
+
+		// 2.7 Metrics endpoint, public if scrapers cannot send the API key
+		if m != nil && !cfg.Metrics.RequireAuth {
+			app.Get("/metrics", m.Handler())
+		}
+
 		// 3. Auth (Protect API)
 		// We protect everything for now as requested ("protect every request")
-		app.Use(auth.New(auth.Config{ApiKey: cfg.Server.ApiKey}))
+		// Every key is named in the request logs and limited to its scopes per route
+		apiKeys, err := auth.LoadKeys(cfg.Server.ApiKey, cfg.Server.ApiKeys, cfg.Server.ApiKeysFile)
+		if err != nil {
+			logg.Fatal("Failed to load API keys", zap.Error(err))
+		}
+		// Repeated invalid credentials lock out the client IP (AUTH_LOCKOUT_*), and
+		// rejected requests are audit logged
+		authCfg := auth.Config{
+			Keys:    apiKeys,
+			Lockout: auth.NewLockout(cfg.AuthLockout),
+			Logger:  logg.Named("audit"),
+		}
+		// Staff tools can send CMS-issued JWTs instead (JWT_SECRET or JWT_JWKS_FILE)
+		if cfg.JWT.Enabled() {
+			authCfg.JWT, err = auth.NewJWTVerifier(cfg.JWT)
+			if err != nil {
+				logg.Fatal("Failed to configure JWT authentication", zap.Error(err))
+			}
+		}
+		app.Use(auth.New(authCfg))
+
+		if m != nil && cfg.Metrics.RequireAuth {
+			app.Get("/metrics", auth.Require(auth.ScopeRead), m.Handler())
+		}
 
 		// 5. Load Features
 		if err := mgr.LoadAll(app); err != nil {
@@ -128,8 +297,50 @@ var startCmd = &cobra.Command{
 		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 		<-c
-		logg.Info("Shutting down server...")
-		_ = app.Shutdown()
+		timeout := cfg.Server.ShutdownTimeout()
+		logg.Info("Shutting down server...", zap.Duration("timeout", timeout))
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// Close event streams first, they never end on their own
+		hub.Close()
+		stopBackground()
+
+		// Stop accepting requests; in-flight ones may finish. HTTP applies run detached from
+		// the request context, so only reconcile.Drain stops them
+		httpDone := make(chan error, 1)
+		go func() { httpDone <- app.ShutdownWithContext(ctx) }()
+
+		// Refuse new applies and wait for running ones; past the deadline they stop
+		// between actions, journaled for 'reconcile undo'
+		if err := reconcile.Drain(ctx); err != nil {
+			logg.Warn("Reconcile applies were interrupted on shutdown", zap.Error(err))
+		}
+
+		// Cancel running jobs if they do not finish in time
+		if err := jobManager.Shutdown(ctx); err != nil {
+			logg.Warn("Running jobs were cancelled on shutdown", zap.Error(err))
+		}
+		if err := <-httpDone; err != nil {
+			logg.Warn("In-flight requests were closed on shutdown", zap.Error(err))
+		}
+
+		// Flush spans and close connections, even if the deadline passed
+		flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFlush()
+		if tr != nil {
+			if err := tr.Shutdown(flushCtx); err != nil {
+				logg.Warn("Failed to export remaining spans", zap.Error(err))
+			}
+		}
+		if err := dbm.Close(); err != nil {
+			logg.Warn("Failed to close database", zap.Error(err))
+		}
+		if err := storage.Close(store); err != nil {
+			logg.Warn("Failed to close storage client", zap.Error(err))
+		}
+		// Logs are flushed by the deferred Sync
+		logg.Info("Server stopped")
 	},
 }
 
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"asset-manager/core/config"
+	"asset-manager/core/logger"
+	"asset-manager/core/storage"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	// Flags for trash commands
+	trashRestorePrefix string
+	trashEmptyAll      bool
+	trashEmptyYes      bool
+)
+
+// trashCmd is the parent command for soft-deleted storage objects.
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage soft-deleted storage objects",
+	Long: `Manage objects moved to the trash by purges when STORAGE_TRASH_ENABLED is set.
+
+Trashed objects are kept under .trash/<timestamp>-<suffix>/<original key> until they are
+restored or emptied after the retention period (STORAGE_TRASH_RETENTION_HOURS).`,
+}
+
+// trashListCmd lists trashed objects.
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed objects grouped by purge batch",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withTrash(func(ctx context.Context, l *zap.Logger, trash *storage.Trash) error {
+			objects, err := trash.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(objects) == 0 {
+				l.Info("Trash is empty")
+				return nil
+			}
+
+			fmt.Println("\n--- Trash ---")
+			batch := ""
+			for _, obj := range objects {
+				if obj.Batch != batch {
+					batch = obj.Batch
+					fmt.Printf("\nBatch %s (expires %s)\n", obj.Batch, obj.ExpiresAt.Format("2006-01-02 15:04 MST"))
+				}
+				fmt.Printf("  %-60s %10d bytes\n", obj.Key, obj.Size)
+			}
+			fmt.Println("-------------")
+
+			l.Info("Trash listed", zap.Int("objects", len(objects)))
+			return nil
+		})
+	},
+}
+
+// trashRestoreCmd restores a trash batch to its original keys.
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore [batch]",
+	Short: "Restore the objects of a trash batch to their original keys",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withTrash(func(ctx context.Context, l *zap.Logger, trash *storage.Trash) error {
+			restored, err := trash.Restore(ctx, args[0], trashRestorePrefix)
+			if err != nil {
+				return fmt.Errorf("failed to restore batch %s (%d restored): %w", args[0], restored, err)
+			}
+
+			l.Info("Trash batch restored", zap.String("batch", args[0]), zap.Int("restored", restored))
+			return nil
+		})
+	},
+}
+
+// trashEmptyCmd permanently removes expired trashed objects.
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently remove trashed objects past their retention period",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if trashEmptyAll && !confirmDestructiveAction(trashEmptyYes) {
+			return nil
+		}
+
+		return withTrash(func(ctx context.Context, l *zap.Logger, trash *storage.Trash) error {
+			removed, err := trash.Empty(ctx, trashEmptyAll)
+			if err != nil {
+				return fmt.Errorf("failed to empty trash (%d objects removed): %w", removed, err)
+			}
+
+			l.Info("Trash emptied", zap.Int("removed", removed), zap.Bool("all", trashEmptyAll))
+			return nil
+		})
+	},
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashEmptyCmd)
+
+	trashRestoreCmd.Flags().StringVar(&trashRestorePrefix, "prefix", "", "Only restore objects whose original key starts with this prefix")
+	trashEmptyCmd.Flags().BoolVar(&trashEmptyAll, "all", false, "Remove every trashed object, ignoring the retention period")
+	trashEmptyCmd.Flags().BoolVar(&trashEmptyYes, "yes", false, "Remove every trashed object without confirmation (non-interactive)")
+
+	RootCmd.AddCommand(trashCmd)
+}
+
+// withTrash loads configuration and runs fn with a trash bound to the configured bucket.
+func withTrash(fn func(ctx context.Context, l *zap.Logger, trash *storage.Trash) error) error {
+	ctx := context.Background()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	l, err := logger.New(&cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	client, err := storage.NewClient(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to connect to storage: %w", err)
+	}
+
+	return fn(ctx, l, storage.NewTrash(client, cfg.Storage.Bucket, cfg.Storage.TrashRetention()))
+}
@@ -5,9 +5,14 @@ import (
 	"strings"
 
 	"asset-manager/core/database"
+	"asset-manager/core/jobs"
 	"asset-manager/core/logger"
+	"asset-manager/core/metrics"
+	"asset-manager/core/middleware/auth"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/server"
 	"asset-manager/core/storage"
+	"asset-manager/core/tracing"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -24,6 +29,24 @@ type Config struct {
 	Log logger.Config `mapstructure:"log"`
 	// Database holds configuration for the database connection.
 	Database database.Config `mapstructure:"database"`
+	// Reconcile holds the safety thresholds for destructive reconcile runs.
+	Reconcile reconcile.Config `mapstructure:"reconcile"`
+	// ReconcileCache holds configuration for the reconcile index caches.
+	ReconcileCache reconcile.CacheConfig `mapstructure:"reconcile_cache"`
+	// ReconcileSnapshot holds configuration for the index snapshots of incremental reconciles.
+	ReconcileSnapshot reconcile.SnapshotConfig `mapstructure:"reconcile_snapshot"`
+	// ReconcilePlan holds configuration for plans stored by the plan/apply endpoints.
+	ReconcilePlan reconcile.PlanConfig `mapstructure:"reconcile_plan"`
+	// Jobs holds configuration for the background job system.
+	Jobs jobs.Config `mapstructure:"jobs"`
+	// Metrics holds configuration for the Prometheus metrics endpoint.
+	Metrics metrics.Config `mapstructure:"metrics"`
+	// Tracing holds configuration for OpenTelemetry tracing.
+	Tracing tracing.Config `mapstructure:"tracing"`
+	// JWT holds configuration for bearer token authentication alongside API keys.
+	JWT auth.JWTConfig `mapstructure:"jwt"`
+	// AuthLockout holds the brute-force protection of API keys and bearer tokens.
+	AuthLockout auth.LockoutConfig `mapstructure:"auth_lockout"`
 }
 
 // LoadConfig loads configuration from environment variables and .env file.
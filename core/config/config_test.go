@@ -83,3 +83,20 @@ func TestEnvOverridesDefaults(t *testing.T) {
 	// Expect storage.endpoint to be overridden
 	assert.Equal(t, val, config.Storage.Endpoint, "Environment variable should override default value")
 }
+
+func TestReconcileEnv(t *testing.T) {
+	t.Setenv("RECONCILE_MAX_DELETES", "5")
+	t.Setenv("RECONCILE_PLAN_TTL_MINUTES", "30")
+	t.Setenv("RECONCILE_CACHE_TTL_SECONDS", "120")
+	t.Setenv("RECONCILE_CACHE_STORE", "sqlite")
+	t.Setenv("RECONCILE_SNAPSHOT_DIR", "/tmp/snapshots")
+
+	config, err := LoadConfig(".")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, config.Reconcile.MaxDeletes)
+	assert.Equal(t, 30, config.ReconcilePlan.TTLMinutes)
+	assert.Equal(t, 120, config.ReconcileCache.TTLSeconds)
+	assert.Equal(t, "sqlite", config.ReconcileCache.Store)
+	assert.Equal(t, 300, config.ReconcileCache.MaxStaleSeconds)
+	assert.Equal(t, "/tmp/snapshots", config.ReconcileSnapshot.Dir)
+}
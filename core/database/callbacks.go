@@ -0,0 +1,41 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CallbackFunc is a GORM callback that also receives the operation of the statement
+// (create, query, update, delete, row or raw).
+type CallbackFunc func(operation string, tx *gorm.DB)
+
+// RegisterCallbacks registers before and after around every GORM operation (create,
+// query, update, delete, row, raw) under "<name>:before_<operation>" and
+// "<name>:after_<operation>". Instrumentation uses it to time and trace queries.
+func RegisterCallbacks(db *gorm.DB, name string, before, after CallbackFunc) error {
+	cb := db.Callback()
+	register := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		operation := r.operation
+		if err := r.before(name+":before_"+operation, func(tx *gorm.DB) { before(operation, tx) }); err != nil {
+			return fmt.Errorf("failed to register %s %s callback: %w", operation, name, err)
+		}
+		if err := r.after(name+":after_"+operation, func(tx *gorm.DB) { after(operation, tx) }); err != nil {
+			return fmt.Errorf("failed to register %s %s callback: %w", operation, name, err)
+		}
+	}
+	return nil
+}
@@ -14,4 +14,10 @@ type Config struct {
 	Name string `mapstructure:"name" default:"emulator"`
 	// TimeoutSeconds is the connection timeout in seconds.
 	TimeoutSeconds int `mapstructure:"timeout_seconds" default:"30"`
+	// ReconnectMinSeconds is the first delay between background reconnection attempts
+	// while the database is unreachable. It doubles after every failed attempt.
+	ReconnectMinSeconds int `mapstructure:"reconnect_min_seconds" default:"1"`
+	// ReconnectMaxSeconds caps the delay between reconnection attempts.
+	// If 0, the database is not reconnected after a failed startup.
+	ReconnectMaxSeconds int `mapstructure:"reconnect_max_seconds" default:"60"`
 }
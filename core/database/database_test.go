@@ -74,3 +74,17 @@ func TestGetTableColumns(t *testing.T) {
 		assert.Nil(t, columns)
 	})
 }
+
+func TestRegisterCallbacks(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var calls []string
+	before := func(operation string, tx *gorm.DB) { calls = append(calls, "before_"+operation) }
+	after := func(operation string, tx *gorm.DB) { calls = append(calls, "after_"+operation) }
+	assert.NoError(t, RegisterCallbacks(db, "test", before, after))
+
+	var ids []int
+	assert.NoError(t, db.Table("items_base").Pluck("id", &ids).Error)
+	assert.Equal(t, []string{"before_query", "after_query"}, calls)
+}
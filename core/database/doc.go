@@ -9,12 +9,26 @@
 // to the specific emulator schema (Arcturus, Comet, Plus) regarding connection establishment,
 // but the Schema Inspector relies on knowing the expected schema.
 //
+// # Connection Manager
+//
+// The database is optional. Manager makes the first connection attempt at startup and,
+// while the database is unreachable, retries in the background with exponential backoff
+// (DATABASE_RECONNECT_MIN_SECONDS doubling up to DATABASE_RECONNECT_MAX_SECONDS). Once
+// connected, it passes the live *gorm.DB to the OnConnect callbacks, so features swap it
+// in. Until then, DB-dependent operations return ErrUnavailable, reported as 503.
+//
 // # Schema Inspection
 //
 // The package includes tools to inspect the database schema, which is crucial for
 // the Server Integrity Check. It allows retrieving table columns and verifying matches
 // against expected models defined in feature packages.
 //
+// # Instrumentation
+//
+// RegisterCallbacks registers GORM callbacks around every operation (create, query,
+// update, delete, row, raw). The metrics and tracing packages use it to time and
+// trace queries.
+//
 // # Usage
 //
 //	db, err := database.Connect(cfg.Database)
@@ -23,4 +37,10 @@
 //	}
 //
 //	columns, err := database.GetTableColumns(db, "items_base")
+//
+//	dbm := database.NewManager(cfg.Database)
+//	dbm.OnConnect(func(db *gorm.DB) { service.SetDB(db) })
+//	if err := dbm.Connect(); err != nil {
+//	    dbm.Start(ctx, nil) // reconnect in the background
+//	}
 package database
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned by DB-dependent operations while the database is not connected.
+var ErrUnavailable = errors.New("database unavailable")
+
+// Manager holds the optional database connection. While the database is unreachable,
+// it reconnects in the background and hands the live connection to the OnConnect callbacks.
+type Manager struct {
+	cfg      Config
+	connect  func(Config) (*gorm.DB, error)
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	db        atomic.Pointer[gorm.DB]
+	connectMu sync.Mutex
+
+	mu        sync.Mutex
+	lastErr   error
+	callbacks []func(*gorm.DB)
+	started   bool
+}
+
+// NewManager creates a manager connecting with Connect. It does not connect yet.
+func NewManager(cfg Config) *Manager {
+	return &Manager{
+		cfg:      cfg,
+		connect:  Connect,
+		minDelay: time.Duration(cfg.ReconnectMinSeconds) * time.Second,
+		maxDelay: time.Duration(cfg.ReconnectMaxSeconds) * time.Second,
+	}
+}
+
+// DB returns the live connection, or nil while the database is not connected.
+func (m *Manager) DB() *gorm.DB {
+	return m.db.Load()
+}
+
+// Get returns the live connection, or ErrUnavailable wrapping the last connection error.
+func (m *Manager) Get() (*gorm.DB, error) {
+	if db := m.db.Load(); db != nil {
+		return db, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lastErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, m.lastErr)
+	}
+	return nil, ErrUnavailable
+}
+
+// OnConnect registers a callback receiving the connection once established.
+// It is called right away if the database is already connected.
+func (m *Manager) OnConnect(fn func(*gorm.DB)) {
+	m.mu.Lock()
+	m.callbacks = append(m.callbacks, fn)
+	db := m.db.Load()
+	m.mu.Unlock()
+
+	if db != nil {
+		fn(db)
+	}
+}
+
+// Connect makes one connection attempt, unless already connected.
+// On success, the OnConnect callbacks are called with the connection.
+func (m *Manager) Connect() error {
+	m.connectMu.Lock()
+	defer m.connectMu.Unlock()
+
+	if m.db.Load() != nil {
+		return nil
+	}
+
+	db, err := m.connect(m.cfg)
+
+	m.mu.Lock()
+	if err != nil {
+		m.lastErr = err
+		m.mu.Unlock()
+		return err
+	}
+	m.lastErr = nil
+	m.db.Store(db)
+	callbacks := append([](func(*gorm.DB))(nil), m.callbacks...)
+	m.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(db)
+	}
+	return nil
+}
+
+// Start reconnects in the background until connected or ctx is cancelled, waiting
+// ReconnectMinSeconds before the first attempt and doubling the delay after every
+// failure up to ReconnectMaxSeconds. onError, if not nil, receives every failure.
+// Does nothing if already connected, already started or reconnection is disabled.
+func (m *Manager) Start(ctx context.Context, onError func(attempt int, next time.Duration, err error)) {
+	if m.db.Load() != nil || m.maxDelay <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return
+	}
+	m.started = true
+	m.mu.Unlock()
+
+	go func() {
+		delay := min(max(m.minDelay, time.Millisecond), m.maxDelay)
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		for attempt := 1; ; attempt++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			err := m.Connect()
+			if err == nil {
+				return
+			}
+
+			delay = min(delay*2, m.maxDelay)
+			if onError != nil {
+				onError(attempt, delay, err)
+			}
+			timer.Reset(delay)
+		}
+	}()
+}
+
+// Close closes the live connection, if any. Start must have been cancelled first, or a
+// later reconnection would open a new one.
+func (m *Manager) Close() error {
+	db := m.db.Swap(nil)
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return nil
+}
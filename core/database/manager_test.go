@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// flakyManager returns a manager whose first failures connection attempts fail.
+func flakyManager(t *testing.T, failures int32) (*Manager, *atomic.Int32) {
+	db, _ := setupMockDB(t)
+	var attempts atomic.Int32
+
+	m := NewManager(Config{})
+	m.minDelay, m.maxDelay = time.Millisecond, 4*time.Millisecond
+	m.connect = func(Config) (*gorm.DB, error) {
+		if attempts.Add(1) <= failures {
+			return nil, errors.New("connection refused")
+		}
+		return db, nil
+	}
+	return m, &attempts
+}
+
+func TestManager_Reconnect(t *testing.T) {
+	m, attempts := flakyManager(t, 3)
+
+	var connected atomic.Pointer[gorm.DB]
+	m.OnConnect(func(db *gorm.DB) { connected.Store(db) })
+
+	// Down at startup: unavailable with the cause
+	require.Error(t, m.Connect())
+	assert.Nil(t, m.DB())
+	_, err := m.Get()
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.EqualError(t, err, "database unavailable: connection refused")
+
+	var failures atomic.Int32
+	m.Start(context.Background(), func(int, time.Duration, error) { failures.Add(1) })
+	m.Start(context.Background(), nil) // Started once
+
+	require.Eventually(t, func() bool { return connected.Load() != nil }, time.Second, time.Millisecond)
+	db, err := m.Get()
+	require.NoError(t, err)
+	assert.Same(t, db, connected.Load())
+	assert.Equal(t, int32(4), attempts.Load())
+	assert.Equal(t, int32(2), failures.Load())
+
+	// Late callbacks get the live connection right away
+	var late *gorm.DB
+	m.OnConnect(func(db *gorm.DB) { late = db })
+	assert.Same(t, db, late)
+}
+
+func TestManager_StartCancelled(t *testing.T) {
+	m, attempts := flakyManager(t, 1000)
+	require.Error(t, m.Connect())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.Start(ctx, nil)
+	require.Eventually(t, func() bool { return attempts.Load() > 2 }, time.Second, time.Millisecond)
+	cancel()
+
+	// No more attempts once cancelled
+	time.Sleep(20 * time.Millisecond)
+	stopped := attempts.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, attempts.Load())
+	assert.Nil(t, m.DB())
+}
+
+func TestManager_ReconnectDisabled(t *testing.T) {
+	m, attempts := flakyManager(t, 1)
+	m.maxDelay = 0
+
+	require.Error(t, m.Connect())
+	m.Start(context.Background(), nil)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestManager_Close(t *testing.T) {
+	db, sqlMock := setupMockDB(t)
+	sqlMock.ExpectClose()
+
+	m := NewManager(Config{})
+	m.connect = func(Config) (*gorm.DB, error) { return db, nil }
+	require.NoError(t, m.Connect())
+
+	require.NoError(t, m.Close())
+	assert.Nil(t, m.DB())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+	// Closing again is a no-op
+	assert.NoError(t, m.Close())
+}
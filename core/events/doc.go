@@ -0,0 +1,19 @@
+// Package events fans out live progress events to subscribers such as the
+// Server-Sent Events stream of the admin panel.
+//
+// A Hub publishes events without blocking: subscribers that fall behind miss events
+// rather than slowing down the reconcile run producing them.
+//
+// # Reconcile Events
+//
+// Hub.Observe attaches reconcile observers to a context, so every phase change,
+// progress count and applied action of a reconcile run using that context is
+// published with the Ray ID of the request that started it.
+//
+// # Usage
+//
+//	hub := events.NewHub(64)
+//	ctx = hub.Observe(rayid.Context(c), "reconcile_furniture")
+//	plan, err := reconcile.ReconcileWithPlan(ctx, spec, db, client, bucket, opts)
+//	hub.Done(ctx, "reconcile_furniture", err)
+package events
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+const (
+	// TypePhase is emitted the first time a run reports a phase.
+	TypePhase Type = "phase"
+	// TypeProgress carries processed/total counts of the current phase.
+	TypeProgress Type = "progress"
+	// TypeAction carries the outcome of a single applied action.
+	TypeAction Type = "action"
+	// TypeDone is emitted when a run finishes, with the error if it failed.
+	TypeDone Type = "done"
+)
+
+// ActionResult is the outcome of an applied reconcile action.
+type ActionResult struct {
+	// Type is the action type (e.g. "delete_db").
+	Type string `json:"type"`
+	// Key is the entity key.
+	Key string `json:"key"`
+	// Error is the failure message, empty on success.
+	Error string `json:"error,omitempty"`
+}
+
+// Event is a single live update.
+type Event struct {
+	// Type is the kind of event.
+	Type Type `json:"type"`
+	// Source names the operation producing the event (e.g. "reconcile_furniture").
+	Source string `json:"source"`
+	// RayID is the Ray ID of the request that started the operation.
+	RayID string `json:"ray_id,omitempty"`
+	// Phase is the reconcile phase for phase and progress events.
+	Phase string `json:"phase,omitempty"`
+	// Processed is the number of processed items in the phase.
+	Processed int `json:"processed,omitempty"`
+	// Total is the number of items in the phase, or zero if unknown.
+	Total int `json:"total,omitempty"`
+	// Action is the action outcome for action events.
+	Action *ActionResult `json:"action,omitempty"`
+	// Error is the failure message for done events.
+	Error string `json:"error,omitempty"`
+	// Time is the time the event was published.
+	Time time.Time `json:"time"`
+}
+
+// Hub fans out events to subscribers.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[chan Event]struct{}
+	buffer int
+	closed bool
+}
+
+// NewHub creates a hub giving each subscriber a buffer of the given size.
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	return &Hub{subs: make(map[chan Event]struct{}), buffer: buffer}
+}
+
+// Subscribe registers a subscriber. The returned function unsubscribes it.
+// The channel is closed when the subscriber unsubscribes or the hub is closed.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan Event, h.buffer)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subs[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := h.subs[ch]; ok {
+				delete(h.subs, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// Subscribers returns the number of active subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
+
+// Publish sends an event to every subscriber, dropping it for subscribers whose buffer is full.
+// Publishing on a nil hub is a no-op.
+func (h *Hub) Publish(e Event) {
+	if h == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Close disconnects every subscriber and rejects new ones.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for ch := range h.subs {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// Observe returns a context whose reconcile progress and action results are published
+// under the given source, tagged with the Ray ID carried by ctx.
+// On a nil hub, ctx is returned unchanged.
+func (h *Hub) Observe(ctx context.Context, source string) context.Context {
+	if h == nil {
+		return ctx
+	}
+
+	rid := rayid.FromContext(ctx)
+
+	var mu sync.Mutex
+	seen := make(map[reconcile.Phase]bool)
+
+	ctx = reconcile.WithProgress(ctx, func(phase reconcile.Phase, processed, total int) {
+		mu.Lock()
+		first := !seen[phase]
+		seen[phase] = true
+		mu.Unlock()
+
+		if first {
+			h.Publish(Event{Type: TypePhase, Source: source, RayID: rid, Phase: string(phase), Total: total})
+		}
+		h.Publish(Event{Type: TypeProgress, Source: source, RayID: rid, Phase: string(phase), Processed: processed, Total: total})
+	})
+
+	return reconcile.WithActionObserver(ctx, func(action reconcile.Action, err error) {
+		result := &ActionResult{Type: string(action.Type), Key: action.Key}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		h.Publish(Event{Type: TypeAction, Source: source, RayID: rid, Phase: string(reconcile.PhaseApply), Action: result})
+	})
+}
+
+// Done publishes the end of a run started with Observe.
+func (h *Hub) Done(ctx context.Context, source string, err error) {
+	if h == nil {
+		return
+	}
+	e := Event{Type: TypeDone, Source: source, RayID: rayid.FromContext(ctx)}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	h.Publish(e)
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drain returns the events buffered in the channel.
+func drain(ch <-chan Event) []Event {
+	var out []Event
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return out
+			}
+			out = append(out, e)
+		default:
+			return out
+		}
+	}
+}
+
+// TestHub_PublishSubscribe tests fan-out, dropping for full buffers and closing.
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := NewHub(2)
+	first, unsubscribe := hub.Subscribe()
+	second, _ := hub.Subscribe()
+	assert.Equal(t, 2, hub.Subscribers())
+
+	hub.Publish(Event{Type: TypeProgress, Processed: 1})
+	hub.Publish(Event{Type: TypeProgress, Processed: 2})
+	hub.Publish(Event{Type: TypeProgress, Processed: 3}) // Dropped, buffers are full
+
+	events := drain(first)
+	require.Len(t, events, 2)
+	assert.Equal(t, 2, events[1].Processed)
+	assert.False(t, events[0].Time.IsZero())
+
+	unsubscribe()
+	unsubscribe()
+	assert.Equal(t, 1, hub.Subscribers())
+
+	hub.Close()
+	drain(second)
+	_, ok := <-second
+	assert.False(t, ok, "Close disconnects subscribers")
+
+	var nilHub *Hub
+	nilHub.Publish(Event{})
+	assert.Equal(t, context.Background(), nilHub.Observe(context.Background(), "x"))
+}
+
+// TestHub_Observe tests that reconcile progress and actions are published with the Ray ID.
+func TestHub_Observe(t *testing.T) {
+	hub := NewHub(16)
+	ch, _ := hub.Subscribe()
+
+	ctx := hub.Observe(rayid.WithContext(context.Background(), "ray-1"), "reconcile_furniture")
+	reconcile.ReportProgress(ctx, reconcile.PhaseApply, 0, 2)
+	reconcile.ReportProgress(ctx, reconcile.PhaseApply, 1, 2)
+	reconcile.ReportAction(ctx, reconcile.Action{Type: reconcile.ActionDeleteDB, Key: "7"}, errors.New("boom"))
+	hub.Done(ctx, "reconcile_furniture", nil)
+
+	events := drain(ch)
+	require.Len(t, events, 5)
+
+	types := make([]Type, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+		assert.Equal(t, "ray-1", e.RayID)
+		assert.Equal(t, "reconcile_furniture", e.Source)
+	}
+	assert.Equal(t, []Type{TypePhase, TypeProgress, TypeProgress, TypeAction, TypeDone}, types)
+	assert.Equal(t, &ActionResult{Type: "delete_db", Key: "7", Error: "boom"}, events[3].Action)
+}
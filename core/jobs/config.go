@@ -0,0 +1,15 @@
+package jobs
+
+// Config holds configuration for the background job system.
+type Config struct {
+	// Workers is the number of jobs executed concurrently.
+	Workers int `mapstructure:"workers" default:"2"`
+	// QueueSize is the maximum number of pending jobs.
+	QueueSize int `mapstructure:"queue_size" default:"100"`
+	// Store selects the job history store ("memory" or "sqlite").
+	Store string `mapstructure:"store" default:"memory"`
+	// SQLitePath is the SQLite database file used when Store is "sqlite".
+	SQLitePath string `mapstructure:"sqlite_path" default:"jobs.db"`
+	// HistoryLimit is the maximum number of jobs kept in history.
+	HistoryLimit int `mapstructure:"history_limit" default:"500"`
+}
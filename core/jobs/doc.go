@@ -0,0 +1,30 @@
+// Package jobs runs long-running operations (integrity checks, reconciles) in the background.
+//
+// HTTP requests that would otherwise time out behind a proxy submit a job instead and
+// poll its status, progress and result.
+//
+// # Manager
+//
+// The Manager owns a bounded worker pool fed by a queue. Features register a RunFunc
+// per job type; Submit enqueues a job, Get returns its live state and Cancel stops it
+// through its context.
+//
+// # Stores
+//
+// Job history is kept in a Store:
+//   - MemoryStore: in-process history, lost on restart (default).
+//   - SQLiteStore: history persisted to a SQLite file (JOBS_STORE=sqlite).
+//
+// Both keep at most JOBS_HISTORY_LIMIT jobs, dropping the oldest first.
+//
+// # Usage
+//
+//	store, err := jobs.NewStore(cfg.Jobs)
+//	mgr := jobs.NewManager(cfg.Jobs, store, logger)
+//	mgr.Register("integrity", func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+//	    return svc.RunAll(ctx), nil
+//	})
+//	mgr.Start()
+//
+//	job, err := mgr.Submit("integrity", nil)
+package jobs
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a job does not exist.
+	ErrNotFound = errors.New("job not found")
+	// ErrUnknownType is returned when submitting a job type without a registered RunFunc.
+	ErrUnknownType = errors.New("unknown job type")
+	// ErrQueueFull is returned when the pending queue is at capacity.
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrFinished is returned when cancelling a job that already finished.
+	ErrFinished = errors.New("job already finished")
+	// ErrShuttingDown is returned when submitting a job while the manager is stopping.
+	ErrShuttingDown = errors.New("job manager is shutting down")
+)
+
+// Status represents the lifecycle state of a job.
+type Status string
+
+const (
+	// StatusPending means the job is queued and waiting for a worker.
+	StatusPending Status = "pending"
+	// StatusRunning means a worker is executing the job.
+	StatusRunning Status = "running"
+	// StatusSucceeded means the job finished without error.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed means the job returned an error.
+	StatusFailed Status = "failed"
+	// StatusCancelled means the job was cancelled before finishing.
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished reports whether the status is terminal.
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Progress describes how far a running job has advanced.
+type Progress struct {
+	// Done is the number of completed units of work.
+	Done int `json:"done"`
+	// Total is the total number of units of work, or zero if unknown.
+	Total int `json:"total"`
+	// Message describes the current step.
+	Message string `json:"message,omitempty"`
+}
+
+// Job is a background operation and its outcome.
+type Job struct {
+	// ID is the unique identifier of the job.
+	ID string `json:"id"`
+	// Type is the registered job type (e.g. "integrity").
+	Type string `json:"type"`
+	// RayID is the Ray ID of the request that submitted the job.
+	RayID string `json:"ray_id,omitempty"`
+	// Params are the options the job was submitted with.
+	Params map[string]string `json:"params,omitempty"`
+	// Status is the lifecycle state of the job.
+	Status Status `json:"status"`
+	// Progress is the last reported progress.
+	Progress Progress `json:"progress"`
+	// Result is the JSON-encoded value returned by the job.
+	Result json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	// Error is the error message if the job failed.
+	Error string `json:"error,omitempty"`
+	// CreatedAt is the time the job was submitted.
+	CreatedAt time.Time `json:"created_at"`
+	// StartedAt is the time a worker picked up the job.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// FinishedAt is the time the job reached a terminal status.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// clone returns a deep copy of the job safe to hand out to callers.
+func (j *Job) clone() *Job {
+	c := *j
+	if j.Params != nil {
+		c.Params = make(map[string]string, len(j.Params))
+		for k, v := range j.Params {
+			c.Params[k] = v
+		}
+	}
+	if j.Result != nil {
+		c.Result = append(json.RawMessage(nil), j.Result...)
+	}
+	return &c
+}
+
+// ProgressFunc reports the progress of a running job.
+type ProgressFunc func(done, total int, message string)
+
+// RunFunc executes a job of a registered type. It must stop when ctx is cancelled.
+// The returned value is JSON-encoded into the job result.
+type RunFunc func(ctx context.Context, params map[string]string, report ProgressFunc) (any, error)
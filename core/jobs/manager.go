@@ -0,0 +1,324 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"asset-manager/core/middleware/rayid"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// entry is a job tracked by the manager until it finishes.
+type entry struct {
+	job    *Job
+	cancel context.CancelFunc
+}
+
+// Manager runs submitted jobs on a bounded worker pool.
+type Manager struct {
+	cfg    Config
+	store  Store
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	runners map[string]RunFunc
+	live    map[string]*entry
+	queue   chan string
+	closed  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a job manager. Call Start to launch the workers.
+func NewManager(cfg Config, store Store, logger *zap.Logger) *Manager {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:     cfg,
+		store:   store,
+		logger:  logger,
+		runners: make(map[string]RunFunc),
+		live:    make(map[string]*entry),
+		queue:   make(chan string, cfg.QueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register adds a runner for a job type, replacing any previous one.
+func (m *Manager) Register(jobType string, run RunFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runners[jobType] = run
+}
+
+// Types returns the registered job types.
+func (m *Manager) Types() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	types := make([]string, 0, len(m.runners))
+	for t := range m.runners {
+		types = append(types, t)
+	}
+	return types
+}
+
+// Start marks jobs left unfinished by a previous process as failed and launches the workers.
+func (m *Manager) Start() {
+	if stale, err := m.store.List(0); err != nil {
+		m.logger.Warn("Failed to load job history", zap.Error(err))
+	} else {
+		for _, job := range stale {
+			if job.Status.Finished() {
+				continue
+			}
+			now := time.Now().UTC()
+			job.Status = StatusFailed
+			job.Error = "interrupted by restart"
+			job.FinishedAt = &now
+			if err := m.store.Save(job); err != nil {
+				m.logger.Warn("Failed to update interrupted job", zap.String("job_id", job.ID), zap.Error(err))
+			}
+		}
+	}
+
+	for i := 0; i < m.cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+}
+
+// Submit queues a job of a registered type. ctx is only used to carry the Ray ID of the
+// submitting request into the job; the job outlives it.
+// Returns ErrUnknownType, ErrQueueFull or ErrShuttingDown if the job cannot be queued.
+func (m *Manager) Submit(ctx context.Context, jobType string, params map[string]string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrShuttingDown
+	}
+	if _, ok := m.runners[jobType]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+
+	job := &Job{
+		ID:        uuid.NewString(),
+		Type:      jobType,
+		RayID:     rayid.FromContext(ctx),
+		Params:    params,
+		Status:    StatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	select {
+	case m.queue <- job.ID:
+	default:
+		return nil, ErrQueueFull
+	}
+
+	m.live[job.ID] = &entry{job: job}
+	m.save(job)
+
+	m.logger.Info("Job queued", zap.String("job_id", job.ID), zap.String("type", jobType))
+	return job.clone(), nil
+}
+
+// Get returns the current state of a job.
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.RLock()
+	if e, ok := m.live[id]; ok {
+		job := e.job.clone()
+		m.mu.RUnlock()
+		return job, nil
+	}
+	m.mu.RUnlock()
+
+	return m.store.Get(id)
+}
+
+// List returns the most recent jobs first, with live progress for unfinished jobs.
+func (m *Manager) List(limit int) ([]*Job, error) {
+	jobs, err := m.store.List(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i, job := range jobs {
+		if e, ok := m.live[job.ID]; ok {
+			jobs[i] = e.job.clone()
+		}
+	}
+	return jobs, nil
+}
+
+// Cancel stops a pending or running job through its context.
+// Returns ErrNotFound for unknown jobs and ErrFinished for jobs that already finished.
+func (m *Manager) Cancel(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.live[id]
+	if !ok {
+		if _, err := m.store.Get(id); err != nil {
+			return nil, err
+		}
+		return nil, ErrFinished
+	}
+
+	if e.job.Status == StatusPending {
+		// Not picked up yet: the worker skips it when dequeued
+		m.finish(e, StatusCancelled, "cancelled")
+	} else if e.cancel != nil {
+		e.cancel()
+	}
+
+	m.logger.Info("Job cancellation requested", zap.String("job_id", id))
+	return e.job.clone(), nil
+}
+
+// Shutdown stops accepting jobs, cancels pending ones and waits for running jobs to finish.
+// Running jobs are cancelled when ctx expires before they finish.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+		for _, e := range m.live {
+			if e.job.Status == StatusPending {
+				m.finish(e, StatusCancelled, "cancelled by shutdown")
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.cancel()
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// worker executes queued jobs until the queue is closed.
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for id := range m.queue {
+		m.run(id)
+	}
+}
+
+// run executes a single job and records its outcome.
+func (m *Manager) run(id string) {
+	m.mu.Lock()
+	e, ok := m.live[id]
+	if !ok || e.job.Status != StatusPending {
+		m.mu.Unlock()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(rayid.WithContext(m.ctx, e.job.RayID))
+	defer cancel()
+
+	now := time.Now().UTC()
+	e.cancel = cancel
+	e.job.Status = StatusRunning
+	e.job.StartedAt = &now
+	m.save(e.job)
+
+	run := m.runners[e.job.Type]
+	params := e.job.clone().Params
+	m.mu.Unlock()
+
+	l := m.logger.With(zap.String("job_id", id), zap.String("type", e.job.Type), zap.String("ray_id", e.job.RayID))
+	l.Info("Job started")
+
+	result, err := m.execute(ctx, run, params, m.reporter(id))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case ctx.Err() != nil:
+		m.finish(e, StatusCancelled, "cancelled")
+		l.Info("Job cancelled")
+	case err != nil:
+		m.finish(e, StatusFailed, err.Error())
+		l.Warn("Job failed", zap.Error(err))
+	default:
+		data, mErr := json.Marshal(result)
+		if mErr != nil {
+			m.finish(e, StatusFailed, fmt.Sprintf("failed to encode result: %v", mErr))
+			l.Warn("Job result could not be encoded", zap.Error(mErr))
+			return
+		}
+		e.job.Result = data
+		m.finish(e, StatusSucceeded, "")
+		l.Info("Job succeeded")
+	}
+}
+
+// execute calls the runner, converting a panic into an error so a faulty job
+// does not take the worker down.
+func (m *Manager) execute(ctx context.Context, run RunFunc, params map[string]string, report ProgressFunc) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx, params, report)
+}
+
+// reporter returns the ProgressFunc for a job. Progress is kept in memory only.
+func (m *Manager) reporter(id string) ProgressFunc {
+	return func(done, total int, message string) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if e, ok := m.live[id]; ok && e.job.Status == StatusRunning {
+			e.job.Progress = Progress{Done: done, Total: total, Message: message}
+		}
+	}
+}
+
+// finish moves a job to a terminal status and removes it from the live set.
+// Callers must hold m.mu.
+func (m *Manager) finish(e *entry, status Status, message string) {
+	now := time.Now().UTC()
+	e.job.Status = status
+	e.job.Error = message
+	e.job.FinishedAt = &now
+	m.save(e.job)
+	delete(m.live, e.job.ID)
+}
+
+// save persists a job, logging failures since the live state remains authoritative.
+func (m *Manager) save(job *Job) {
+	if err := m.store.Save(job); err != nil {
+		m.logger.Warn("Failed to persist job", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
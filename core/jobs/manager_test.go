@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// waitFor polls the job until it reaches a terminal status.
+func waitFor(t *testing.T, m *Manager, id string) *Job {
+	t.Helper()
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		return err == nil && job.Status.Finished()
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+// TestManager_Lifecycle tests submitting jobs and reading their outcome.
+func TestManager_Lifecycle(t *testing.T) {
+	m := NewManager(Config{Workers: 1, QueueSize: 4}, NewMemoryStore(10), zap.NewNop())
+	m.Register("echo", func(ctx context.Context, params map[string]string, report ProgressFunc) (any, error) {
+		report(1, 1, "done")
+		return map[string]string{"echo": params["value"]}, nil
+	})
+	m.Register("fail", func(ctx context.Context, params map[string]string, report ProgressFunc) (any, error) {
+		return nil, errors.New("boom")
+	})
+	m.Start()
+	defer m.Shutdown(context.Background())
+
+	_, err := m.Submit(context.Background(), "missing", nil)
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	job, err := m.Submit(context.Background(), "echo", map[string]string{"value": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, job.Status)
+
+	job = waitFor(t, m, job.ID)
+	assert.Equal(t, StatusSucceeded, job.Status)
+	assert.JSONEq(t, `{"echo":"hi"}`, string(job.Result))
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+
+	failed, err := m.Submit(context.Background(), "fail", nil)
+	require.NoError(t, err)
+	failed = waitFor(t, m, failed.ID)
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "boom", failed.Error)
+
+	list, err := m.List(0)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, failed.ID, list[0].ID, "Most recent job first")
+
+	_, err = m.Cancel(job.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+	_, err = m.Cancel("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestManager_Cancel tests cancelling running and pending jobs.
+func TestManager_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	m := NewManager(Config{Workers: 1, QueueSize: 1}, NewMemoryStore(10), zap.NewNop())
+	m.Register("block", func(ctx context.Context, params map[string]string, report ProgressFunc) (any, error) {
+		report(0, 10, "waiting")
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Start()
+	defer m.Shutdown(context.Background())
+
+	running, err := m.Submit(context.Background(), "block", nil)
+	require.NoError(t, err)
+	<-started
+
+	current, err := m.Get(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, current.Status)
+	assert.Equal(t, Progress{Done: 0, Total: 10, Message: "waiting"}, current.Progress)
+
+	// The single worker is busy: the next job waits in the queue, the one after is rejected
+	pending, err := m.Submit(context.Background(), "block", nil)
+	require.NoError(t, err)
+	_, err = m.Submit(context.Background(), "block", nil)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	cancelled, err := m.Cancel(pending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+
+	_, err = m.Cancel(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, waitFor(t, m, running.ID).Status)
+}
+
+// TestManager_Shutdown tests that shutdown cancels running jobs once the deadline expires.
+func TestManager_Shutdown(t *testing.T) {
+	store := NewMemoryStore(10)
+	m := NewManager(Config{Workers: 1, QueueSize: 1}, store, zap.NewNop())
+	started := make(chan struct{})
+	m.Register("block", func(ctx context.Context, params map[string]string, report ProgressFunc) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.Start()
+
+	job, err := m.Submit(context.Background(), "block", nil)
+	require.NoError(t, err)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Shutdown(ctx), context.DeadlineExceeded)
+
+	stored, err := store.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, stored.Status)
+
+	_, err = m.Submit(context.Background(), "block", nil)
+	assert.ErrorIs(t, err, ErrShuttingDown)
+}
+
+// TestManager_StartMarksInterrupted tests that unfinished jobs from a previous run are failed on start.
+func TestManager_StartMarksInterrupted(t *testing.T) {
+	store := NewMemoryStore(10)
+	require.NoError(t, store.Save(&Job{ID: "old", Type: "echo", Status: StatusRunning, CreatedAt: time.Now()}))
+
+	m := NewManager(Config{Workers: 1, QueueSize: 1}, store, zap.NewNop())
+	m.Start()
+	defer m.Shutdown(context.Background())
+
+	job, err := m.Get("old")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, "interrupted by restart", job.Error)
+}
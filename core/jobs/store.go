@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Store persists job history.
+type Store interface {
+	// Save inserts or replaces a job.
+	Save(job *Job) error
+	// Get returns a job by ID, or ErrNotFound.
+	Get(id string) (*Job, error)
+	// List returns the most recent jobs first. A limit <= 0 returns all jobs.
+	List(limit int) ([]*Job, error)
+}
+
+// NewStore creates the store selected by the configuration.
+func NewStore(cfg Config) (Store, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return NewMemoryStore(cfg.HistoryLimit), nil
+	case "sqlite":
+		return NewSQLiteStore(cfg.SQLitePath, cfg.HistoryLimit)
+	default:
+		return nil, fmt.Errorf("unknown job store %q (expected memory or sqlite)", cfg.Store)
+	}
+}
+
+// MemoryStore keeps job history in memory.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	jobs  map[string]*Job
+	limit int
+}
+
+// NewMemoryStore creates an in-memory store keeping at most limit jobs (0 = unlimited).
+func NewMemoryStore(limit int) *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*Job), limit: limit}
+}
+
+// Save inserts or replaces a job, dropping the oldest jobs beyond the history limit.
+func (s *MemoryStore) Save(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job.clone()
+
+	if s.limit > 0 && len(s.jobs) > s.limit {
+		for _, old := range sortJobs(s.jobs)[s.limit:] {
+			delete(s.jobs, old.ID)
+		}
+	}
+	return nil
+}
+
+// Get returns a job by ID.
+func (s *MemoryStore) Get(id string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.clone(), nil
+}
+
+// List returns the most recent jobs first.
+func (s *MemoryStore) List(limit int) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sorted := sortJobs(s.jobs)
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	jobs := make([]*Job, len(sorted))
+	for i, job := range sorted {
+		jobs[i] = job.clone()
+	}
+	return jobs, nil
+}
+
+// sortJobs returns the jobs ordered by creation time, most recent first.
+func sortJobs(jobs map[string]*Job) []*Job {
+	sorted := make([]*Job, 0, len(jobs))
+	for _, job := range jobs {
+		sorted = append(sorted, job)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID > sorted[j].ID
+		}
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+// jobRow is the SQLite representation of a job.
+type jobRow struct {
+	ID         string `gorm:"primaryKey"`
+	Type       string
+	RayID      string
+	Params     string
+	Status     string `gorm:"index"`
+	Progress   string
+	Result     string
+	Error      string
+	CreatedAt  time.Time `gorm:"index"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// TableName returns the table used for job history.
+func (jobRow) TableName() string {
+	return "jobs"
+}
+
+// SQLiteStore persists job history to a SQLite database so it survives restarts.
+type SQLiteStore struct {
+	db    *gorm.DB
+	limit int
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at path, keeping at most limit jobs (0 = unlimited).
+func NewSQLiteStore(path string, limit int) (*SQLiteStore, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job store %s: %w", path, err)
+	}
+
+	if err := db.AutoMigrate(&jobRow{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate job store: %w", err)
+	}
+
+	return &SQLiteStore{db: db, limit: limit}, nil
+}
+
+// Save inserts or replaces a job, dropping the oldest jobs beyond the history limit.
+func (s *SQLiteStore) Save(job *Job) error {
+	row, err := toRow(job)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Save(row).Error; err != nil {
+		return fmt.Errorf("failed to save job %s: %w", job.ID, err)
+	}
+
+	if s.limit > 0 {
+		keep := s.db.Model(&jobRow{}).Select("id").Order("created_at DESC").Limit(s.limit)
+		if err := s.db.Where("id NOT IN (?)", keep).Delete(&jobRow{}).Error; err != nil {
+			return fmt.Errorf("failed to prune job history: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns a job by ID.
+func (s *SQLiteStore) Get(id string) (*Job, error) {
+	var rows []jobRow
+	if err := s.db.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return fromRow(rows[0])
+}
+
+// List returns the most recent jobs first.
+func (s *SQLiteStore) List(limit int) ([]*Job, error) {
+	query := s.db.Order("created_at DESC").Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var rows []jobRow
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]*Job, 0, len(rows))
+	for _, row := range rows {
+		job, err := fromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// toRow converts a job to its SQLite representation.
+func toRow(job *Job) (*jobRow, error) {
+	params, err := json.Marshal(job.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode params of job %s: %w", job.ID, err)
+	}
+	progress, err := json.Marshal(job.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode progress of job %s: %w", job.ID, err)
+	}
+
+	return &jobRow{
+		ID:         job.ID,
+		Type:       job.Type,
+		RayID:      job.RayID,
+		Params:     string(params),
+		Status:     string(job.Status),
+		Progress:   string(progress),
+		Result:     string(job.Result),
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}, nil
+}
+
+// fromRow converts a SQLite row back to a job.
+func fromRow(row jobRow) (*Job, error) {
+	job := &Job{
+		ID:         row.ID,
+		Type:       row.Type,
+		RayID:      row.RayID,
+		Status:     Status(row.Status),
+		Error:      row.Error,
+		CreatedAt:  row.CreatedAt,
+		StartedAt:  row.StartedAt,
+		FinishedAt: row.FinishedAt,
+	}
+
+	if row.Params != "" {
+		if err := json.Unmarshal([]byte(row.Params), &job.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode params of job %s: %w", row.ID, err)
+		}
+	}
+	if row.Progress != "" {
+		if err := json.Unmarshal([]byte(row.Progress), &job.Progress); err != nil {
+			return nil, fmt.Errorf("failed to decode progress of job %s: %w", row.ID, err)
+		}
+	}
+	if row.Result != "" {
+		job.Result = json.RawMessage(row.Result)
+	}
+
+	return job, nil
+}
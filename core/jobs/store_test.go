@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStores tests saving, listing and pruning for every store implementation.
+func TestStores(t *testing.T) {
+	sqliteStore, err := NewSQLiteStore(filepath.Join(t.TempDir(), "jobs.db"), 2)
+	require.NoError(t, err)
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(2),
+		"sqlite": sqliteStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			finished := base.Add(time.Minute)
+
+			for i, id := range []string{"a", "b", "c"} {
+				require.NoError(t, store.Save(&Job{
+					ID:        id,
+					Type:      "integrity",
+					Params:    map[string]string{"db": "true"},
+					Status:    StatusPending,
+					CreatedAt: base.Add(time.Duration(i) * time.Second),
+				}))
+			}
+
+			_, err := store.Get("a")
+			assert.ErrorIs(t, err, ErrNotFound, "Oldest job is pruned beyond the history limit")
+
+			job, err := store.Get("c")
+			require.NoError(t, err)
+			job.Status = StatusSucceeded
+			job.Progress = Progress{Done: 3, Total: 3}
+			job.Result = json.RawMessage(`{"ok":true}`)
+			job.FinishedAt = &finished
+			require.NoError(t, store.Save(job))
+
+			saved, err := store.Get("c")
+			require.NoError(t, err)
+			assert.Equal(t, StatusSucceeded, saved.Status)
+			assert.Equal(t, map[string]string{"db": "true"}, saved.Params)
+			assert.Equal(t, Progress{Done: 3, Total: 3}, saved.Progress)
+			assert.JSONEq(t, `{"ok":true}`, string(saved.Result))
+			assert.True(t, finished.Equal(*saved.FinishedAt))
+
+			list, err := store.List(0)
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			assert.Equal(t, "c", list[0].ID)
+			assert.Equal(t, "b", list[1].ID)
+
+			list, err = store.List(1)
+			require.NoError(t, err)
+			assert.Len(t, list, 1)
+		})
+	}
+}
+
+// TestNewStore tests store selection from configuration.
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(Config{Store: "memory"})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	_, err = NewStore(Config{Store: "redis"})
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package loader
 
 import (
 	"log"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -16,6 +17,9 @@ type FeatureLoader interface {
 // Manager handles the registration and loading of features.
 type Manager struct {
 	loaders []FeatureLoader
+
+	mu     sync.RWMutex
+	loaded []string
 }
 
 // NewManager creates a new loader manager.
@@ -38,9 +42,19 @@ func (m *Manager) LoadAll(app fiber.Router) error {
 			if err := l.Load(app); err != nil {
 				return err
 			}
+			m.mu.Lock()
+			m.loaded = append(m.loaded, l.Name())
+			m.mu.Unlock()
 		} else {
 			log.Printf("Skipping disabled feature: %s", l.Name())
 		}
 	}
 	return nil
 }
+
+// Loaded returns the names of the features loaded by LoadAll, in load order.
+func (m *Manager) Loaded() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.loaded...)
+}
@@ -52,6 +52,7 @@ func TestManager(t *testing.T) {
 
 		err := manager.LoadAll(app)
 		assert.NoError(t, err)
+		assert.Equal(t, []string{"feature1"}, manager.Loaded())
 
 		loader1.AssertExpectations(t)
 		loader2.AssertExpectations(t)
@@ -70,5 +71,6 @@ func TestManager(t *testing.T) {
 		err := manager.LoadAll(app)
 		assert.Error(t, err)
 		assert.Equal(t, "fail", err.Error())
+		assert.Empty(t, manager.Loaded())
 	})
 }
@@ -40,11 +40,15 @@ func New(cfg *Config) (*zap.Logger, error) {
 	return logger, nil
 }
 
-// WithRayID returns a logger with the ray_id field set from the Fiber context.
+// WithRayID returns a logger with the ray_id field set from the Fiber context, and the
+// api_key field with the name of the API key once the request is authenticated.
 func WithRayID(l *zap.Logger, c *fiber.Ctx) *zap.Logger {
 	rid := c.Locals("ray_id")
 	if str, ok := rid.(string); ok && str != "" {
-		return l.With(zap.String("ray_id", str))
+		l = l.With(zap.String("ray_id", str))
+	}
+	if name, ok := c.Locals("api_key").(string); ok && name != "" {
+		l = l.With(zap.String("api_key", name))
 	}
 	return l
 }
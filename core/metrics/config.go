@@ -0,0 +1,10 @@
+package metrics
+
+// Config holds configuration for the Prometheus metrics endpoint.
+type Config struct {
+	// Enabled records metrics and exposes them at GET /metrics.
+	Enabled bool `mapstructure:"enabled" default:"true"`
+	// RequireAuth protects /metrics with the API key like every other route.
+	// Disable it for scrapers that cannot send the API key header.
+	RequireAuth bool `mapstructure:"require_auth" default:"true"`
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"asset-manager/core/database"
+
+	"gorm.io/gorm"
+)
+
+// dbStartKey holds the start time of a query in the GORM statement settings.
+const dbStartKey = "metrics:start"
+
+// InstrumentDB registers GORM callbacks recording the count, errors and latency of
+// every query by operation (create, query, update, delete, row, raw).
+// Record-not-found results are not counted as errors.
+func (m *Metrics) InstrumentDB(db *gorm.DB) error {
+	before := func(operation string, tx *gorm.DB) {
+		tx.InstanceSet(dbStartKey, time.Now())
+	}
+
+	after := func(operation string, tx *gorm.DB) {
+		value, ok := tx.InstanceGet(dbStartKey)
+		if !ok {
+			return
+		}
+		start := value.(time.Time)
+
+		m.dbQueries.WithLabelValues(operation).Inc()
+		m.dbDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			m.dbErrors.WithLabelValues(operation).Inc()
+		}
+	}
+
+	return database.RegisterCallbacks(db, "metrics", before, after)
+}
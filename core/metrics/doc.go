@@ -0,0 +1,30 @@
+// Package metrics exports Prometheus metrics of the HTTP server, storage, database and
+// reconcile engine at GET /metrics.
+//
+// # Metrics
+//
+// All metrics are prefixed with asset_manager_:
+//   - http_requests_total, http_request_duration_seconds: by method, route pattern and status.
+//   - storage_operations_total, storage_errors_total, storage_operation_duration_seconds,
+//     storage_bytes_total: by storage.Client method.
+//   - db_queries_total, db_errors_total, db_query_duration_seconds: by GORM operation.
+//   - reconcile_run_duration_seconds, reconcile_runs_total: builds, plans and applies by adapter.
+//   - reconcile_plan_items: the counters of the latest PlanSummary by adapter.
+//   - reconcile_cache_age_seconds, reconcile_cache_build_seconds: cached indices by cache key.
+//
+// Go runtime and process metrics are included.
+//
+// # Configuration
+//
+// METRICS_ENABLED (default true) records and exposes metrics. METRICS_REQUIRE_AUTH (default
+// true) puts /metrics behind the API key; disable it for scrapers that cannot send headers.
+//
+// # Usage
+//
+//	m := metrics.New()
+//	app.Use(m.Middleware())
+//	client = storage.Wrap(client, storage.Instrument(m))
+//	_ = m.InstrumentDB(db)
+//	reconcile.SetRunObserver(m)
+//	app.Get("/metrics", m.Handler())
+package metrics
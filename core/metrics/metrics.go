@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name.
+const namespace = "asset_manager"
+
+// Metrics holds the collectors of the service in their own registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	storageOps      *prometheus.CounterVec
+	storageErrors   *prometheus.CounterVec
+	storageDuration *prometheus.HistogramVec
+	storageBytes    *prometheus.CounterVec
+
+	dbQueries  *prometheus.CounterVec
+	dbErrors   *prometheus.CounterVec
+	dbDuration *prometheus.HistogramVec
+
+	reconcileRuns     *prometheus.CounterVec
+	reconcileDuration *prometheus.HistogramVec
+	planItems         *prometheus.GaugeVec
+}
+
+// New creates the collectors and registers them, with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		storageOps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_operations_total",
+			Help:      "Storage client calls by method.",
+		}, []string{"operation"}),
+		storageErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_errors_total",
+			Help:      "Failed storage client calls by method.",
+		}, []string{"operation"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_operation_duration_seconds",
+			Help:      "Storage client call latency by method. Listings and downloads are timed until fully consumed.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		storageBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_bytes_total",
+			Help:      "Bytes uploaded, downloaded or copied by storage client method.",
+		}, []string{"operation"}),
+
+		dbQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_queries_total",
+			Help:      "Database queries by GORM operation.",
+		}, []string{"operation"}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_errors_total",
+			Help:      "Failed database queries by GORM operation.",
+		}, []string{"operation"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by GORM operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+
+		reconcileRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconcile_runs_total",
+			Help:      "Reconcile builds, plans and applies by adapter and result.",
+		}, []string{"adapter", "operation", "result"}),
+		reconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "reconcile_run_duration_seconds",
+			Help:      "Duration of reconcile builds, plans and applies by adapter.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"adapter", "operation"}),
+		planItems: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "reconcile_plan_items",
+			Help:      "Counters of the latest reconcile plan summary by adapter.",
+		}, []string{"adapter", "counter"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration,
+		m.storageOps, m.storageErrors, m.storageDuration, m.storageBytes,
+		m.dbQueries, m.dbErrors, m.dbDuration,
+		m.reconcileRuns, m.reconcileDuration, m.planItems,
+		cacheCollector{},
+	)
+
+	return m
+}
+
+// Registry returns the registry holding the collectors, to register more.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMiddleware(t *testing.T) {
+	m := New()
+	app := fiber.New()
+	app.Use(m.Middleware())
+	app.Get("/furniture/:identifier", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/fail", func(c *fiber.Ctx) error { return fiber.ErrBadGateway })
+
+	for _, path := range []string{"/furniture/1", "/furniture/2", "/fail"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	// Labelled by route pattern, not raw path
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/furniture/:identifier", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/fail", "502")))
+}
+
+func TestObserveCall(t *testing.T) {
+	m := New()
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "ok").Return(true, nil)
+	client.On("BucketExists", mock.Anything, "down").Return(false, errors.New("unreachable"))
+	client.On("ListObjects", mock.Anything, "ok", mock.Anything).
+		Return(listing(minio.ObjectInfo{Key: "a"}, minio.ObjectInfo{Err: errors.New("denied")}))
+	client.On("PutObject", mock.Anything, "ok", "a", mock.Anything, int64(3), mock.Anything).
+		Return(minio.UploadInfo{Size: 3}, nil)
+
+	store := storage.Wrap(client, storage.Instrument(m))
+
+	_, err := store.BucketExists(context.Background(), "ok")
+	require.NoError(t, err)
+	_, err = store.BucketExists(context.Background(), "down")
+	require.Error(t, err)
+
+	// Listings are forwarded and recorded once consumed
+	var keys []string
+	for obj := range store.ListObjects(context.Background(), "ok", minio.ListObjectsOptions{}) {
+		keys = append(keys, obj.Key)
+	}
+	assert.Equal(t, []string{"a", ""}, keys)
+
+	_, err = store.PutObject(context.Background(), "ok", "a", strings.NewReader("abc"), 3, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.storageOps.WithLabelValues("BucketExists")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.storageErrors.WithLabelValues("BucketExists")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.storageBytes.WithLabelValues("PutObject")))
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(m.storageErrors.WithLabelValues("ListObjects")) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestInstrumentDB(t *testing.T) {
+	m := New()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, m.InstrumentDB(db))
+
+	var n int
+	require.NoError(t, db.Raw("SELECT 1").Scan(&n).Error)
+	assert.Error(t, db.Exec("SELECT * FROM missing").Error)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.dbQueries.WithLabelValues("raw")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.dbErrors.WithLabelValues("raw")))
+}
+
+func TestReconcileMetrics(t *testing.T) {
+	m := New()
+	m.ObserveRun("furniture", reconcile.OperationPlan, 2*time.Second, nil)
+	m.ObserveRun("furniture", reconcile.OperationApply, time.Second, errors.New("failed"))
+	m.ObservePlan("furniture", reconcile.PlanSummary{TotalItems: 10, MissingDB: 3})
+	m.ObservePlan("furniture", reconcile.PlanSummary{TotalItems: 12, MissingDB: 1})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.reconcileRuns.WithLabelValues("furniture", "plan", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.reconcileRuns.WithLabelValues("furniture", "apply", "error")))
+
+	// Gauges hold the latest summary
+	assert.Equal(t, 12.0, testutil.ToFloat64(m.planItems.WithLabelValues("furniture", "total_items")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.planItems.WithLabelValues("furniture", "missing_db")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ObservePlan("furniture", reconcile.PlanSummary{TotalItems: 10})
+
+	app := fiber.New()
+	app.Get("/metrics", m.Handler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `asset_manager_reconcile_plan_items{adapter="furniture",counter="total_items"} 10`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+// listing returns a closed channel holding the objects.
+func listing(objects ...minio.ObjectInfo) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo, len(objects))
+	for _, obj := range objects {
+		ch <- obj
+	}
+	close(ch)
+	return ch
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"time"
+
+	"asset-manager/core/reconcile"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ObserveRun implements reconcile.RunObserver.
+func (m *Metrics) ObserveRun(adapter string, operation reconcile.RunOperation, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.reconcileRuns.WithLabelValues(adapter, string(operation), result).Inc()
+	m.reconcileDuration.WithLabelValues(adapter, string(operation)).Observe(duration.Seconds())
+}
+
+// ObservePlan implements reconcile.RunObserver.
+func (m *Metrics) ObservePlan(adapter string, summary reconcile.PlanSummary) {
+	counters := map[string]int{
+		"total_items":      summary.TotalItems,
+		"missing_gamedata": summary.MissingGamedata,
+		"missing_storage":  summary.MissingStorage,
+		"missing_db":       summary.MissingDB,
+		"mismatches":       summary.Mismatches,
+		"purge_actions":    summary.PurgeActions,
+		"sync_actions":     summary.SyncActions,
+		"create_actions":   summary.CreateActions,
+	}
+	for counter, value := range counters {
+		m.planItems.WithLabelValues(adapter, counter).Set(float64(value))
+	}
+}
+
+var (
+	cacheAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "reconcile", "cache_age_seconds"),
+		"Age of the cached reconcile indices by cache key.",
+		[]string{"key"}, nil,
+	)
+	cacheBuildDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "reconcile", "cache_build_seconds"),
+		"Duration of the last build of the cached reconcile indices by cache key.",
+		[]string{"key"}, nil,
+	)
+)
+
+// cacheCollector reads the reconcile cache stats at scrape time.
+type cacheCollector struct{}
+
+// Describe implements prometheus.Collector.
+func (cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheAgeDesc
+	ch <- cacheBuildDesc
+}
+
+// Collect implements prometheus.Collector.
+func (cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range reconcile.AllStats() {
+		if stats.Cached {
+			ch <- prometheus.MustNewConstMetric(cacheAgeDesc, prometheus.GaugeValue, stats.AgeSeconds, stats.Key)
+		}
+		if stats.Builds > 0 {
+			ch <- prometheus.MustNewConstMetric(cacheBuildDesc, prometheus.GaugeValue, stats.LastBuildSeconds, stats.Key)
+		}
+	}
+}
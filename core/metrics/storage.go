@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"context"
+
+	"asset-manager/core/storage"
+)
+
+// ObserveCall implements storage.CallObserver, recording the count, errors, latency and
+// bytes transferred of every storage call. Register it with storage.Instrument.
+func (m *Metrics) ObserveCall(_ context.Context, call storage.Call) {
+	m.storageOps.WithLabelValues(call.Method).Inc()
+	m.storageDuration.WithLabelValues(call.Method).Observe(call.Duration.Seconds())
+	if call.Bytes > 0 {
+		m.storageBytes.WithLabelValues(call.Method).Add(float64(call.Bytes))
+	}
+	if call.Err != nil {
+		m.storageErrors.WithLabelValues(call.Method).Inc()
+	}
+}
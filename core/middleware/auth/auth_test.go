@@ -1,11 +1,16 @@
 package auth
 
 import (
+	"io"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -35,3 +40,131 @@ func TestAuthMiddleware(t *testing.T) {
 	resp, _ = app.Test(req)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 }
+
+func TestRequire(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{Keys: []Key{
+		{Name: "reader", Secret: "r", Scopes: []string{ScopeRead}},
+		{Name: "admin", Secret: "a", Scopes: []string{ScopeAll}},
+	}}))
+	app.Get("/read", Require(ScopeRead), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals(ContextKey).(string))
+	})
+	app.Post("/apply", Require(ScopeReconcileApply), func(c *fiber.Ctx) error {
+		return c.SendString("applied")
+	})
+
+	request := func(method, path, key string) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(HeaderKey, key)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request("GET", "/read", "r")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "reader", string(body), "the key name, never the secret, is stored")
+
+	assert.Equal(t, fiber.StatusForbidden, request("POST", "/apply", "r").StatusCode)
+	assert.Equal(t, fiber.StatusOK, request("POST", "/apply", "a").StatusCode)
+	assert.Equal(t, fiber.StatusUnauthorized, request("POST", "/apply", "x").StatusCode)
+}
+
+func TestRequire_WithoutAuth(t *testing.T) {
+	// Routes mounted without New refuse requests, since they carry no key
+	app := fiber.New()
+	app.Get("/", Require(ScopeRead), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequire_WithKey(t *testing.T) {
+	app := fiber.New()
+	app.Use(WithKey(Key{Name: "reader", Scopes: []string{ScopeRead}}))
+	app.Get("/read", Require(ScopeRead), func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Post("/write", Require(ScopeJobsWrite), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/read", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/write", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestAuthMiddleware_Lockout(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	app := fiber.New()
+	app.Use(New(Config{
+		ApiKey:  "secret",
+		Keys:    []Key{{Name: "ci", Secret: "known", Scopes: []string{ScopeAll}}},
+		Lockout: NewLockout(LockoutConfig{MaxFailures: 2, WindowSeconds: 60, DurationSeconds: 60}),
+		Logger:  zap.New(core),
+	}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	request := func(key string) *http.Response {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(HeaderKey, key)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// A key authenticating from the IP before the lockout
+	assert.Equal(t, fiber.StatusOK, request("known").StatusCode)
+
+	assert.Equal(t, fiber.StatusUnauthorized, request("guess-1").StatusCode)
+	assert.Equal(t, fiber.StatusUnauthorized, request("guess-2").StatusCode)
+
+	// Locked out, even with a right key never seen from the IP
+	resp := request("secret")
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, fiber.StatusTooManyRequests, request("guess-3").StatusCode)
+
+	// The known key still gets through
+	assert.Equal(t, fiber.StatusOK, request("known").StatusCode)
+
+	// Every rejection is audited, never with the secret
+	require.Equal(t, 4, logs.Len())
+	reasons := []string{}
+	for _, entry := range logs.All() {
+		reasons = append(reasons, entry.ContextMap()["reason"].(string))
+		for _, value := range entry.ContextMap() {
+			if str, ok := value.(string); ok {
+				assert.NotContains(t, str, "guess")
+				assert.NotContains(t, str, "secret")
+			}
+		}
+	}
+	assert.Equal(t, []string{reasonInvalidKey, reasonInvalidKey, reasonLockedOut, reasonLockedOut}, reasons)
+	assert.Equal(t, true, logs.All()[1].ContextMap()["locked_out"])
+}
+
+func TestAuthMiddleware_AuditScope(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	app := fiber.New()
+	app.Use(New(Config{
+		Keys:   []Key{{Name: "reader", Secret: "r", Scopes: []string{ScopeRead}}},
+		Logger: zap.New(core),
+	}))
+	app.Post("/apply", Require(ScopeReconcileApply), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	req := httptest.NewRequest("POST", "/apply", nil)
+	req.Header.Set(HeaderKey, "r")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, reasonMissingScope, fields["reason"])
+	assert.Equal(t, ScopeReconcileApply, fields["scope"])
+	assert.Equal(t, "reader", fields["api_key"])
+}
@@ -0,0 +1,62 @@
+package auth
+
+import "time"
+
+// JWTConfig holds configuration for bearer token (JWT) authentication.
+// It is enabled when Secret or JWKSFile is set.
+type JWTConfig struct {
+	// Secret is the shared secret of HS256 tokens. Empty disables HS256.
+	Secret string `mapstructure:"secret" default:""`
+	// JWKSFile is a local JWKS file holding the RSA public keys of RS256 tokens.
+	// Empty disables RS256.
+	JWKSFile string `mapstructure:"jwks_file" default:""`
+	// Issuer, if set, must match the iss claim.
+	Issuer string `mapstructure:"issuer" default:""`
+	// Audience, if set, must be one of the aud claim values.
+	Audience string `mapstructure:"audience" default:""`
+	// RolesClaim is the claim mapped to scopes. It can hold a string, a number (e.g. a rank)
+	// or an array of them.
+	RolesClaim string `mapstructure:"roles_claim" default:"roles"`
+	// RoleScopes maps claim values to scopes, as "role:scope,scope" entries separated by ';'
+	// (e.g. "admin:*;7:read,reconcile:apply"). Values without an entry grant nothing.
+	RoleScopes string `mapstructure:"role_scopes" default:""`
+	// LeewaySeconds is the clock skew tolerated on the exp and nbf claims.
+	LeewaySeconds int `mapstructure:"leeway_seconds" default:"30"`
+}
+
+// Enabled reports whether bearer tokens are accepted.
+func (c JWTConfig) Enabled() bool {
+	return c.Secret != "" || c.JWKSFile != ""
+}
+
+// Leeway returns the tolerated clock skew as a duration.
+func (c JWTConfig) Leeway() time.Duration {
+	return time.Duration(c.LeewaySeconds) * time.Second
+}
+
+// LockoutConfig holds the brute-force protection of the auth middleware.
+type LockoutConfig struct {
+	// MaxFailures is the number of failed attempts from one IP within the window that
+	// locks it out. If 0, clients are never locked out.
+	MaxFailures int `mapstructure:"max_failures" default:"10"`
+	// WindowSeconds is the period over which failed attempts are counted.
+	WindowSeconds int `mapstructure:"window_seconds" default:"60"`
+	// DurationSeconds is how long a locked out IP is refused. Keys that authenticated
+	// from the IP within the last day are still accepted.
+	DurationSeconds int `mapstructure:"duration_seconds" default:"300"`
+}
+
+// Enabled reports whether clients can be locked out.
+func (c LockoutConfig) Enabled() bool {
+	return c.MaxFailures > 0
+}
+
+// Window returns the counting period as a duration.
+func (c LockoutConfig) Window() time.Duration {
+	return time.Duration(c.WindowSeconds) * time.Second
+}
+
+// Duration returns the lockout duration.
+func (c LockoutConfig) Duration() time.Duration {
+	return time.Duration(c.DurationSeconds) * time.Second
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned by JWTVerifier.Verify for tokens that are malformed,
+// badly signed, expired or issued for someone else.
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// JWTVerifier verifies HS256 and RS256 bearer tokens and maps their roles claim to scopes.
+type JWTVerifier struct {
+	secret     []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	issuer     string
+	audience   string
+	rolesClaim string
+	roleScopes map[string][]string
+	leeway     time.Duration
+	now        func() time.Time
+}
+
+// NewJWTVerifier creates a verifier from the configuration, loading the JWKS file if set.
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("JWT authentication requires a secret or a JWKS file")
+	}
+
+	v := &JWTVerifier{
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+		rolesClaim: cfg.RolesClaim,
+		leeway:     cfg.Leeway(),
+		now:        time.Now,
+	}
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+	}
+
+	roleScopes, err := ParseRoleScopes(cfg.RoleScopes)
+	if err != nil {
+		return nil, err
+	}
+	v.roleScopes = roleScopes
+	return v, nil
+}
+
+// ParseRoleScopes parses role mappings in the JWT_ROLE_SCOPES format: entries separated
+// by ';', each "role:scope,scope". Scopes are validated like those of API keys.
+func ParseRoleScopes(s string) (map[string][]string, error) {
+	roles := make(map[string][]string)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		role, scopes, ok := strings.Cut(entry, ":")
+		role = strings.TrimSpace(role)
+		if !ok || role == "" {
+			return nil, fmt.Errorf("invalid role entry %q: expected role:scopes", entry)
+		}
+		for _, scope := range strings.Split(scopes, ",") {
+			scope = strings.TrimSpace(scope)
+			if scope == "" {
+				continue
+			}
+			if !isScope(scope) {
+				return nil, fmt.Errorf("role %s has unknown scope %q (valid: %s)", role, scope, strings.Join(Scopes, ", "))
+			}
+			roles[role] = append(roles[role], scope)
+		}
+	}
+	return roles, nil
+}
+
+// jwk is an RSA key of a JWKS document.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadJWKSFile reads the RSA signing keys of a JWKS file, keyed by kid.
+// Keys of other types or uses are ignored.
+func LoadJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file %s: %w", path, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != "RS256") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of JWKS key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of JWKS key %q: %w", k.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent of JWKS key %q", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS file %s holds no RS256 signing keys", path)
+	}
+	return keys, nil
+}
+
+// Verify checks the signature and claims of a token and returns the Key it stands for,
+// named "jwt:<sub>" and granted the scopes of its roles. The algorithm is pinned to the
+// configured keys: HS256 needs the secret and RS256 the JWKS file.
+func (v *JWTVerifier) Verify(token string) (Key, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Key{}, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Key{}, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+	if err := v.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return Key{}, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Key{}, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.verifyClaims(claims); err != nil {
+		return Key{}, err
+	}
+
+	name := "jwt"
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		name += ":" + sub
+	}
+	return Key{Name: name, Scopes: v.scopes(claims[v.rolesClaim])}, nil
+}
+
+// verifySignature checks the signature with the key of the algorithm.
+func (v *JWTVerifier) verifySignature(alg, kid, signed string, signature []byte) error {
+	switch {
+	case alg == "HS256" && v.secret != nil:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+
+	case alg == "RS256" && v.rsaKeys != nil:
+		digest := sha256.Sum256([]byte(signed))
+		if key, ok := v.rsaKeys[kid]; ok {
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+				return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+			}
+			return nil
+		}
+		// Tokens without a known kid are tried against every key
+		for _, key := range v.rsaKeys {
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+}
+
+// verifyClaims checks the expiry (required), not-before, issuer and audience claims.
+func (v *JWTVerifier) verifyClaims(claims map[string]any) error {
+	now := v.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.leeway)) {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+		}
+	}
+	if v.audience != "" && !containsValue(claims["aud"], v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// scopes returns the scopes granted by the roles claim value.
+func (v *JWTVerifier) scopes(roles any) []string {
+	var scopes []string
+	for _, role := range claimValues(roles) {
+		for _, scope := range v.roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// claimValues returns a string, number or array claim as strings. Numbers are
+// formatted as integers when whole (a rank of 7 is "7").
+func claimValues(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case float64:
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}
+	case []any:
+		var values []string
+		for _, item := range value {
+			values = append(values, claimValues(item)...)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// containsValue reports whether a string or array claim holds the value.
+func containsValue(claim any, value string) bool {
+	return slices.Contains(claimValues(claim), value)
+}
+
+// decodeSegment decodes a base64url JSON segment of a token.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeSegment encodes a token segment.
+func encodeSegment(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signHS256 returns an HS256 token of the claims.
+func signHS256(t *testing.T, secret string, claims map[string]any) string {
+	signed := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS256 returns an RS256 token of the claims.
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeJWKS writes a JWKS file holding the public key.
+func writeJWKS(t *testing.T, key *rsa.PublicKey, kid string) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	doc := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	v, err := NewJWTVerifier(JWTConfig{
+		Secret:     "cms-secret",
+		Issuer:     "cms",
+		Audience:   "asset-manager",
+		RolesClaim: "rank",
+		RoleScopes: "7:*;5:read,integrity:fix",
+	})
+	require.NoError(t, err)
+
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	claims := func(extra map[string]any) map[string]any {
+		c := map[string]any{"sub": "alice", "iss": "cms", "aud": []string{"asset-manager"}, "exp": exp, "rank": 5}
+		for k, value := range extra {
+			c[k] = value
+		}
+		return c
+	}
+
+	key, err := v.Verify(signHS256(t, "cms-secret", claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:alice", key.Name)
+	assert.Empty(t, key.Secret)
+	assert.True(t, key.Allows(ScopeIntegrityFix))
+	assert.False(t, key.Allows(ScopeReconcileApply))
+
+	// Unmapped roles grant nothing
+	key, err = v.Verify(signHS256(t, "cms-secret", claims(map[string]any{"rank": 1})))
+	require.NoError(t, err)
+	assert.Empty(t, key.Scopes)
+
+	invalid := map[string]string{
+		"wrong secret": signHS256(t, "other", claims(nil)),
+		"expired":      signHS256(t, "cms-secret", claims(map[string]any{"exp": float64(time.Now().Add(-time.Hour).Unix())})),
+		"no exp":       signHS256(t, "cms-secret", claims(map[string]any{"exp": nil})),
+		"not yet":      signHS256(t, "cms-secret", claims(map[string]any{"nbf": float64(time.Now().Add(time.Hour).Unix())})),
+		"issuer":       signHS256(t, "cms-secret", claims(map[string]any{"iss": "forum"})),
+		"audience":     signHS256(t, "cms-secret", claims(map[string]any{"aud": "other"})),
+		"malformed":    "not-a-token",
+		"alg none": encodeSegment(t, map[string]string{"alg": "none"}) + "." +
+			encodeSegment(t, claims(nil)) + ".",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestJWTVerifier_RS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	v, err := NewJWTVerifier(JWTConfig{
+		JWKSFile:   writeJWKS(t, &private.PublicKey, "k1"),
+		RolesClaim: "roles",
+		RoleScopes: "admin:*;staff:read",
+	})
+	require.NoError(t, err)
+
+	claims := map[string]any{"sub": "bob", "exp": float64(time.Now().Add(time.Hour).Unix()), "roles": []string{"staff", "vip"}}
+	key, err := v.Verify(signRS256(t, private, "k1", claims))
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:bob", key.Name)
+	assert.Equal(t, []string{ScopeRead}, key.Scopes)
+
+	// HS256 is refused without a secret, even when signed with public material
+	_, err = v.Verify(signHS256(t, "anything", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = v.Verify(signRS256(t, other, "k1", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewJWTVerifier_Invalid(t *testing.T) {
+	_, err := NewJWTVerifier(JWTConfig{})
+	assert.Error(t, err)
+
+	_, err = NewJWTVerifier(JWTConfig{Secret: "s", RoleScopes: "admin:write"})
+	assert.ErrorContains(t, err, `unknown scope "write"`)
+
+	_, err = NewJWTVerifier(JWTConfig{JWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
+
+func TestAuthMiddleware_Bearer(t *testing.T) {
+	v, err := NewJWTVerifier(JWTConfig{Secret: "cms-secret", RolesClaim: "roles", RoleScopes: "staff:read"})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(New(Config{ApiKey: "secret", JWT: v}))
+	app.Get("/", Require(ScopeRead), func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals(ContextKey).(string))
+	})
+	app.Post("/apply", Require(ScopeReconcileApply), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	token := signHS256(t, "cms-secret", map[string]any{
+		"sub": "alice", "roles": "staff", "exp": float64(time.Now().Add(time.Hour).Unix()),
+	})
+	request := func(method, path, authorization string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request("GET", "/", "Bearer "+token))
+	assert.Equal(t, fiber.StatusForbidden, request("POST", "/apply", "Bearer "+token))
+	forged := signHS256(t, "guess", map[string]any{"sub": "mallory", "roles": "staff", "exp": float64(time.Now().Add(time.Hour).Unix())})
+	assert.Equal(t, fiber.StatusUnauthorized, request("GET", "/", "Bearer "+forged))
+
+	// API keys keep working
+	req := httptest.NewRequest("POST", "/apply", nil)
+	req.Header.Set(HeaderKey, "secret")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
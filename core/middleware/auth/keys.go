@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// ScopeRead allows every read-only route: listings, checks without fixes, plans,
+	// job status and event streams.
+	ScopeRead = "read"
+	// ScopeIntegrityFix allows integrity checks with ?fix=true, which create missing folders.
+	ScopeIntegrityFix = "integrity:fix"
+	// ScopeReconcileApply allows applying reconcile plans, which mutate the database,
+	// gamedata and storage.
+	ScopeReconcileApply = "reconcile:apply"
+	// ScopeJobsWrite allows submitting and cancelling background jobs.
+	ScopeJobsWrite = "jobs:write"
+	// ScopeAll grants every scope.
+	ScopeAll = "*"
+)
+
+// Scopes lists every scope a key can be granted.
+var Scopes = []string{ScopeRead, ScopeIntegrityFix, ScopeReconcileApply, ScopeJobsWrite, ScopeAll}
+
+// DefaultKeyName is the name of the key configured by SERVER_API_KEY.
+const DefaultKeyName = "default"
+
+// Key is a named API key and the scopes it grants.
+type Key struct {
+	// Name identifies the key in logs. It is never the secret.
+	Name string `json:"name"`
+	// Secret is the value of the X-API-Key header.
+	Secret string `json:"key"`
+	// Scopes are the scopes granted to the key (see Scopes).
+	Scopes []string `json:"scopes"`
+}
+
+// isScope reports whether scope is one of Scopes.
+func isScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// Allows reports whether the key grants the scope.
+func (k Key) Allows(scope string) bool {
+	return slices.Contains(k.Scopes, ScopeAll) || slices.Contains(k.Scopes, scope)
+}
+
+// ParseKeys parses keys in the SERVER_API_KEYS format: entries separated by ';', each
+// "name:secret:scope,scope" (e.g. "ci:s3cret:read;deploy:0ther:read,reconcile:apply").
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	for i, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			// Do not echo the entry, it may hold a secret
+			return nil, fmt.Errorf("invalid API key entry %d: expected name:secret:scopes", i+1)
+		}
+
+		key := Key{Name: strings.TrimSpace(parts[0]), Secret: strings.TrimSpace(parts[1])}
+		for _, scope := range strings.Split(parts[2], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				key.Scopes = append(key.Scopes, scope)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// LoadKeysFile reads keys from a JSON file holding an array of
+// {"name": "...", "key": "...", "scopes": ["..."]} objects.
+func LoadKeysFile(path string) ([]Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var keys []Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// LoadKeys combines the legacy single key (named DefaultKeyName, granted every scope),
+// the keys of SERVER_API_KEYS and those of the keys file, and validates them.
+// Empty arguments are skipped.
+func LoadKeys(apiKey, apiKeys, keysFile string) ([]Key, error) {
+	var keys []Key
+	if apiKey != "" {
+		keys = append(keys, Key{Name: DefaultKeyName, Secret: apiKey, Scopes: []string{ScopeAll}})
+	}
+
+	parsed, err := ParseKeys(apiKeys)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, parsed...)
+
+	if keysFile != "" {
+		loaded, err := LoadKeysFile(keysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, loaded...)
+	}
+
+	if err := ValidateKeys(keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ValidateKeys checks that every key has a unique name and secret and known scopes.
+func ValidateKeys(keys []Key) error {
+	names := make(map[string]bool, len(keys))
+	secrets := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.Name == "" {
+			return errors.New("API key without a name")
+		}
+		if k.Secret == "" {
+			return fmt.Errorf("API key %s has an empty secret", k.Name)
+		}
+		if names[k.Name] {
+			return fmt.Errorf("duplicate API key name %s", k.Name)
+		}
+		if secrets[k.Secret] {
+			return fmt.Errorf("API key %s reuses the secret of another key", k.Name)
+		}
+		if len(k.Scopes) == 0 {
+			return fmt.Errorf("API key %s has no scopes", k.Name)
+		}
+		for _, scope := range k.Scopes {
+			if !isScope(scope) {
+				return fmt.Errorf("API key %s has unknown scope %q (valid: %s)", k.Name, scope, strings.Join(Scopes, ", "))
+			}
+		}
+		names[k.Name] = true
+		secrets[k.Secret] = true
+	}
+	return nil
+}
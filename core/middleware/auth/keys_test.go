@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("ci:s3cret:read; deploy:0ther:read, reconcile:apply;")
+	require.NoError(t, err)
+	assert.Equal(t, []Key{
+		{Name: "ci", Secret: "s3cret", Scopes: []string{ScopeRead}},
+		{Name: "deploy", Secret: "0ther", Scopes: []string{ScopeRead, ScopeReconcileApply}},
+	}, keys)
+
+	// Malformed entries are reported without their content
+	_, err = ParseKeys("ci:s3cret:read;0ther")
+	assert.EqualError(t, err, "invalid API key entry 2: expected name:secret:scopes")
+}
+
+func TestLoadKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "panel", "key": "p", "scopes": ["read", "integrity:fix"]}]`), 0o600))
+
+	keys, err := LoadKeys("legacy", "ci:c:read", path)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.Equal(t, Key{Name: DefaultKeyName, Secret: "legacy", Scopes: []string{ScopeAll}}, keys[0])
+	assert.Equal(t, "ci", keys[1].Name)
+	assert.True(t, keys[2].Allows(ScopeIntegrityFix))
+	assert.False(t, keys[2].Allows(ScopeReconcileApply))
+
+	_, err = LoadKeys("", "", filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []Key
+		err  string
+	}{
+		{"unknown scope", []Key{{Name: "a", Secret: "a", Scopes: []string{"write"}}}, `unknown scope "write"`},
+		{"no scopes", []Key{{Name: "a", Secret: "a"}}, "has no scopes"},
+		{"empty secret", []Key{{Name: "a", Scopes: []string{ScopeRead}}}, "empty secret"},
+		{"duplicate name", []Key{
+			{Name: "a", Secret: "a", Scopes: []string{ScopeRead}},
+			{Name: "a", Secret: "b", Scopes: []string{ScopeRead}},
+		}, "duplicate API key name a"},
+		{"duplicate secret", []Key{
+			{Name: "a", Secret: "a", Scopes: []string{ScopeRead}},
+			{Name: "b", Secret: "a", Scopes: []string{ScopeRead}},
+		}, "reuses the secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKeys(tt.keys)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
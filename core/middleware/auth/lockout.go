@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// knownKeyTTL is how long a key that authenticated from an IP stays exempt from its
+// lockouts without authenticating again.
+const knownKeyTTL = 24 * time.Hour
+
+// Lockout tracks failed authentication attempts per client IP and locks out clients
+// exceeding LockoutConfig.MaxFailures within the window. It also remembers the keys
+// that authenticated from each IP, so a lockout caused by someone else guessing from
+// a shared address does not refuse them. It is safe for concurrent use.
+type Lockout struct {
+	maxFailures int
+	window      time.Duration
+	duration    time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*attempts
+	known     map[knownKey]time.Time
+	lastSweep time.Time
+}
+
+// knownKey identifies a key that authenticated from an IP.
+type knownKey struct {
+	ip   string
+	name string
+}
+
+// attempts holds the failures of a client within the current window.
+type attempts struct {
+	failures    int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// NewLockout creates a tracker from the configuration, or returns nil if disabled.
+func NewLockout(cfg LockoutConfig) *Lockout {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &Lockout{
+		maxFailures: cfg.MaxFailures,
+		window:      cfg.Window(),
+		duration:    cfg.Duration(),
+		now:         time.Now,
+		clients:     make(map[string]*attempts),
+		known:       make(map[knownKey]time.Time),
+	}
+}
+
+// Locked returns how long the client remains locked out, or 0 if it is not.
+func (l *Lockout) Locked(ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.clients[ip]
+	if !ok {
+		return 0
+	}
+	return max(a.lockedUntil.Sub(l.now()), 0)
+}
+
+// Fail records a failed attempt and returns the number of failures in the window and
+// whether the client is now locked out.
+func (l *Lockout) Fail(ip string) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	a, ok := l.clients[ip]
+	if !ok || now.Sub(a.first) > l.window {
+		a = &attempts{first: now}
+		l.clients[ip] = a
+	}
+	a.failures++
+	if a.failures >= l.maxFailures {
+		a.lockedUntil = now.Add(l.duration)
+		return a.failures, true
+	}
+	return a.failures, false
+}
+
+// Succeed records that the named key authenticated from the client IP.
+func (l *Lockout) Succeed(ip, name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	l.known[knownKey{ip: ip, name: name}] = now
+}
+
+// Exempt reports whether the named key authenticated from the client IP recently
+// enough to bypass its lockout.
+func (l *Lockout) Exempt(ip, name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen, ok := l.known[knownKey{ip: ip, name: name}]
+	return ok && l.now().Sub(seen) <= knownKeyTTL
+}
+
+// sweep drops clients whose window and lockout ended and keys not seen within
+// knownKeyTTL, at most once per window, so guessing from many addresses does not
+// grow the maps forever.
+func (l *Lockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for ip, a := range l.clients {
+		if now.Sub(a.first) > l.window && now.After(a.lockedUntil) {
+			delete(l.clients, ip)
+		}
+	}
+	for k, seen := range l.known {
+		if now.Sub(seen) > knownKeyTTL {
+			delete(l.known, k)
+		}
+	}
+}
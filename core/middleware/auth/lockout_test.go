@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockout(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLockout(LockoutConfig{MaxFailures: 3, WindowSeconds: 60, DurationSeconds: 300})
+	require.NotNil(t, l)
+	l.now = func() time.Time { return now }
+
+	for i := 1; i < 3; i++ {
+		failures, locked := l.Fail("1.2.3.4")
+		assert.Equal(t, i, failures)
+		assert.False(t, locked)
+	}
+	assert.Zero(t, l.Locked("1.2.3.4"))
+
+	_, locked := l.Fail("1.2.3.4")
+	assert.True(t, locked)
+	assert.Equal(t, 300*time.Second, l.Locked("1.2.3.4"))
+	assert.Zero(t, l.Locked("5.6.7.8"), "other clients are not affected")
+
+	// The lockout ends, and failures outside the window start a new count
+	now = now.Add(301 * time.Second)
+	assert.Zero(t, l.Locked("1.2.3.4"))
+	failures, locked := l.Fail("1.2.3.4")
+	assert.Equal(t, 1, failures)
+	assert.False(t, locked)
+}
+
+func TestLockout_Sweep(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLockout(LockoutConfig{MaxFailures: 3, WindowSeconds: 60, DurationSeconds: 300})
+	l.now = func() time.Time { return now }
+
+	l.Fail("1.2.3.4")
+	now = now.Add(2 * time.Minute)
+	l.Fail("5.6.7.8")
+
+	assert.Len(t, l.clients, 1)
+}
+
+func TestLockout_Exempt(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLockout(LockoutConfig{MaxFailures: 3, WindowSeconds: 60, DurationSeconds: 300})
+	l.now = func() time.Time { return now }
+
+	l.Succeed("1.2.3.4", "ci")
+	assert.True(t, l.Exempt("1.2.3.4", "ci"))
+	assert.False(t, l.Exempt("1.2.3.4", "admin"), "other keys are not exempt")
+	assert.False(t, l.Exempt("5.6.7.8", "ci"), "other clients are not exempt")
+
+	// Keys not seen for a day are forgotten
+	now = now.Add(knownKeyTTL + time.Second)
+	assert.False(t, l.Exempt("1.2.3.4", "ci"))
+	l.Fail("1.2.3.4")
+	assert.Empty(t, l.known)
+}
+
+func TestNewLockout_Disabled(t *testing.T) {
+	assert.Nil(t, NewLockout(LockoutConfig{}))
+}
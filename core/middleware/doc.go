@@ -6,7 +6,8 @@
 //
 //   - Auth: Implements API key validation to protect endpoints.
 //   - RayID: Generates a unique Request ID (RayID) for every incoming request,
-//     injecting it into the context and response headers for tracing.
+//     injecting it into the context and response headers for tracing. rayid.Context
+//     carries it into a context.Context for services and background jobs.
 //
 // These middleware components are designed to be registered globally or per-route group
 // in the main application setup.
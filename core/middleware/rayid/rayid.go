@@ -1,6 +1,8 @@
 package rayid
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -21,6 +23,7 @@ func New() fiber.Handler {
 
 		// Set for internal use (Logger, etc.)
 		c.Locals(ContextKey, rid)
+		c.SetUserContext(WithContext(c.Context(), rid))
 
 		// Set Header
 		c.Set(HeaderKey, rid)
@@ -28,3 +31,38 @@ func New() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// contextKey is the context.Context key under which the Ray ID is stored.
+type contextKey struct{}
+
+// Get returns the Ray ID of the request, or an empty string if none was set.
+func Get(c *fiber.Ctx) string {
+	rid, _ := c.Locals(ContextKey).(string)
+	return rid
+}
+
+// Context returns the request context carrying the Ray ID, so services and
+// background work started by the request can be correlated with it.
+// It is c.UserContext(), so values set by later middleware (e.g. the tracing span)
+// are carried too.
+func Context(c *fiber.Ctx) context.Context {
+	ctx := c.UserContext()
+	if FromContext(ctx) != "" {
+		return ctx
+	}
+	return WithContext(ctx, Get(c))
+}
+
+// WithContext returns a copy of ctx carrying the Ray ID.
+func WithContext(ctx context.Context, rid string) context.Context {
+	if rid == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, rid)
+}
+
+// FromContext returns the Ray ID carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	rid, _ := ctx.Value(contextKey{}).(string)
+	return rid
+}
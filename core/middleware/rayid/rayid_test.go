@@ -1,6 +1,7 @@
 package rayid
 
 import (
+	"context"
 	"net/http/httptest"
 	"testing"
 
@@ -26,3 +27,40 @@ func TestRayIDMiddleware(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	assert.NotEmpty(t, resp.Header.Get(HeaderKey))
 }
+
+func TestRayIDContext(t *testing.T) {
+	app := fiber.New()
+	app.Use(New())
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		assert.Equal(t, Get(c), FromContext(Context(c)))
+		assert.NotEmpty(t, FromContext(Context(c)))
+		return c.SendString("ok")
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "abc", FromContext(WithContext(context.Background(), "abc")))
+}
+
+func TestRayIDContext_UserContext(t *testing.T) {
+	type key struct{}
+	app := fiber.New()
+	app.Use(New())
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), key{}, "span"))
+		return c.Next()
+	})
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		// Values set by later middleware are carried with the Ray ID
+		assert.Equal(t, "span", Context(c).Value(key{}))
+		assert.Equal(t, Get(c), FromContext(Context(c)))
+		return c.SendString("ok")
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
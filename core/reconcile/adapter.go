@@ -100,3 +100,102 @@ type Mutator interface {
 	// Returns an error if the sync fails.
 	SyncDBFromGamedata(ctx context.Context, key string, gdItem GDItem) error
 }
+
+// Creator extends Mutator with repair support for entities missing in the database.
+// Adapters implementing this interface can execute ActionCreateDB actions.
+type Creator interface {
+	// CreateDBFromGamedata inserts a new DB entity built from the gamedata item.
+	// Fields not present in gamedata use adapter-specific defaults.
+	// Reports whether the entity was inserted: existing ones are left untouched.
+	// Returns an error if the insert fails.
+	CreateDBFromGamedata(ctx context.Context, key string, gdItem GDItem) (bool, error)
+
+	// CreateGamedataFromDB appends a new gamedata entity built from the DB item.
+	// Reports whether the entity was appended: existing ones are left untouched.
+	// Returns an error if the write fails.
+	CreateGamedataFromDB(ctx context.Context, key string, dbItem DBItem) (bool, error)
+}
+
+// StorageSharer is implemented by adapters whose entities can share one storage object
+// (e.g. color variants sharing a bundle). Plans never delete a shared object while an
+// entity whose storage is not deleted too still uses it.
+type StorageSharer interface {
+	// SharedStorage returns, for each storage set key whose object is used by more than
+	// one entity, the keys of every entity using it.
+	SharedStorage(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}) map[string][]string
+}
+
+// StorageLinker is implemented by adapters that can only resolve some storage keys
+// once the DB index is known (e.g. storage objects named after a DB column for
+// entities missing in gamedata). BuildCache calls it after loading every index of
+// specs with LinkStorage set.
+type StorageLinker interface {
+	// LinkStorageKeys returns a copy of the storage set with keys rewritten to match DB
+	// entities, and the storage key each linked entity key replaced. It must not modify
+	// its arguments or the adapter: storage events link the keys of new objects while
+	// builds may be running (see ApplyObjectEvents).
+	LinkStorageKeys(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}) (map[string]struct{}, map[string]string)
+
+	// UseStorageLinks is called by BuildCache with the links of the indices it built, so
+	// mutations of the plans computed from them resolve linked keys to storage objects.
+	UseStorageLinks(links map[string]string)
+}
+
+// Incremental is implemented by adapters supporting incremental builds from a Snapshot
+// (see Spec.Snapshots). The engine then lists storage itself, resolving new and changed
+// objects with ExtractStorageKey once the gamedata index is loaded.
+type Incremental interface {
+	// DBChecksums returns a checksum of the DB rows of every entity key, computed by the
+	// database so unchanged rows are not transferred.
+	DBChecksums(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]uint32, error)
+
+	// LoadDBItems loads the DB items of the given entity keys.
+	LoadDBItems(ctx context.Context, db *gorm.DB, serverProfile string, keys []string) (map[string]DBItem, error)
+
+	// UseGamedataIndex restores the adapter state LoadGamedataIndex derives from the
+	// index, for indices reused from a snapshot.
+	UseGamedataIndex(index map[string]GDItem)
+}
+
+// Searchable is implemented by adapters whose entities can be searched by more than
+// their display name (e.g. a classname). Other adapters are searched by ResolveName.
+type Searchable interface {
+	// SearchTerms returns the terms an entity can be found by, given available DB
+	// and/or gamedata items.
+	SearchTerms(dbItem DBItem, gdItem GDItem) []string
+}
+
+// Journaler extends Mutator with undo support.
+// Adapters implementing this interface can capture entities before they are deleted
+// and restore them later from a Journal.
+type Journaler interface {
+	// CaptureGamedata returns one journal entry per key holding the raw gamedata
+	// entry exactly as it appears in the gamedata JSON.
+	CaptureGamedata(ctx context.Context, keys []string) ([]JournalEntry, error)
+
+	// QuarantineStorage copies the storage objects for the given keys under the
+	// quarantine prefix (or into the adapter's own soft-delete area, such as the
+	// trash) and returns one journal entry per copied object.
+	QuarantineStorage(ctx context.Context, keys []string, quarantinePrefix string) ([]JournalEntry, error)
+
+	// RestoreDB re-inserts deleted rows, reverts synced rows and removes created rows
+	// from journal entries. Entries whose row already exists (for deletions) are skipped.
+	RestoreDB(ctx context.Context, entries []JournalEntry) error
+
+	// RestoreGamedata re-appends removed gamedata entries and removes created ones
+	// in a single write. Removed entries whose ID already exists in gamedata are skipped.
+	RestoreGamedata(ctx context.Context, entries []JournalEntry) error
+
+	// RestoreStorage copies quarantined objects back to their original keys.
+	// Entries point at the copies made by QuarantineStorage.
+	RestoreStorage(ctx context.Context, entries []JournalEntry) error
+}
+
+// StorageKeeper is implemented by mutators that keep a copy of the storage objects they
+// delete (e.g. in a trash). With a journal, ApplyPlan deletes the objects QuarantineStorage
+// already copied through it, so they are not copied again.
+type StorageKeeper interface {
+	// DeleteQuarantinedStorage removes the original objects of the journal entries
+	// returned by QuarantineStorage, without keeping another copy.
+	DeleteQuarantinedStorage(ctx context.Context, entries []JournalEntry) error
+}
@@ -28,6 +28,25 @@ type ReconcileCache struct {
 
 	// TTL is the time-to-live for this cache.
 	TTL time.Duration
+
+	// Generation counts the storage event updates applied since the cache was built
+	// (see ApplyObjectEvents). Plans record it so CheckPlanFresh refuses them once the
+	// indices they were computed from changed, even though Built did not.
+	Generation int
+
+	// Delta describes what an incremental build reloaded; nil for full builds.
+	Delta *Delta
+
+	// searchOnce guards the search index, built on first use.
+	searchOnce sync.Once
+	search     *SearchIndex
+
+	// gamedataETag is the ETag of the gamedata object when the cache was built,
+	// recorded to validate the cache after a restart.
+	gamedataETag string
+
+	// restored marks caches loaded by RestoreCaches that were not validated yet.
+	restored bool
 }
 
 // IsExpired returns true if this cache has expired based on its TTL.
@@ -38,21 +57,38 @@ func (c *ReconcileCache) IsExpired() bool {
 	return time.Since(c.Built) > c.TTL
 }
 
+// servableStale reports whether an expired cache may still be served while it is
+// rebuilt, i.e. it expired less than maxStale ago.
+func (c *ReconcileCache) servableStale(maxStale time.Duration) bool {
+	return c.TTL > 0 && maxStale > 0 && time.Since(c.Built) <= c.TTL+maxStale
+}
+
 // cacheStore holds all reconcile caches keyed by spec cache key.
 type cacheStore struct {
 	mu     sync.RWMutex
 	caches map[string]*ReconcileCache
 	sf     singleflight.Group
+
+	// persister saves built caches across restarts (optional)
+	persister      CachePersister
+	onPersistError func(cacheKey string, err error)
+
+	// metrics holds build metrics by cache key, guarded by mu
+	metrics map[string]*cacheMetrics
 }
 
 // globalCacheStore is the singleton cache store for all reconcile operations.
 var globalCacheStore = &cacheStore{
-	caches: make(map[string]*ReconcileCache),
+	caches:  make(map[string]*ReconcileCache),
+	metrics: make(map[string]*cacheMetrics),
 }
 
 // BuildCache builds a new cache for the given spec by loading all indices.
 // This function does NOT store the cache; use GetOrBuildCache for that.
-func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
+func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (_ *ReconcileCache, err error) {
+	ctx, span := startSpan(ctx, string(OperationBuild), spec)
+	defer func() { endSpan(span, err) }()
+
 	var (
 		dbIndex    map[string]DBItem
 		gdIndex    map[string]GDItem
@@ -75,24 +111,57 @@ func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Cli
 		}
 	}
 
+	// Let the adapter Load methods report progress through the context
+	ctx = WithProgress(ctx, spec.Progress)
+
+	if inc, ok := spec.Adapter.(Incremental); ok && spec.Snapshots != nil {
+		return buildIncremental(ctx, spec, inc, db, client, bucket)
+	}
+
+	// Persisted caches are validated against the gamedata ETag after a restart.
+	// It is read before loading so a concurrent change invalidates the cache.
+	var gamedataETag string
+	if globalCacheStore.persisting() {
+		state, err := objectState(ctx, client, bucket, spec.GamedataObjectName)
+		if err != nil {
+			return nil, err
+		}
+		gamedataETag = state.ETag
+	}
+
 	wg.Add(3)
 
 	// Build DB index
 	go func() {
 		defer wg.Done()
+		ctx, span := startPhaseSpan(ctx, PhaseLoadDB, spec)
 		dbIndex, dbErr = spec.Adapter.LoadDBIndex(ctx, db, spec.ServerProfile)
+		endSpan(span, dbErr)
+		if dbErr == nil {
+			ReportProgress(ctx, PhaseLoadDB, len(dbIndex), len(dbIndex))
+		}
 	}()
 
 	// Build gamedata index
 	go func() {
 		defer wg.Done()
+		ctx, span := startPhaseSpan(ctx, PhaseLoadGamedata, spec)
 		gdIndex, gdErr = spec.Adapter.LoadGamedataIndex(ctx, client, bucket, spec.GamedataObjectName, spec.GamedataPaths)
+		endSpan(span, gdErr)
+		if gdErr == nil {
+			ReportProgress(ctx, PhaseLoadGamedata, len(gdIndex), len(gdIndex))
+		}
 	}()
 
 	// Build storage set
 	go func() {
 		defer wg.Done()
+		ctx, span := startPhaseSpan(ctx, PhaseLoadStorage, spec)
 		storageSet, storageErr = spec.Adapter.LoadStorageSet(ctx, client, bucket, spec.StoragePrefix, spec.StorageExtension)
+		endSpan(span, storageErr)
+		if storageErr == nil {
+			ReportProgress(ctx, PhaseLoadStorage, len(storageSet), len(storageSet))
+		}
 	}()
 
 	wg.Wait()
@@ -107,34 +176,46 @@ func BuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Cli
 	if storageErr != nil {
 		return nil, storageErr
 	}
-	if gdErr != nil {
-		return nil, gdErr
-	}
-	if storageErr != nil {
-		return nil, storageErr
+
+	// Let the adapter resolve storage keys that depend on the DB index
+	if linker, ok := spec.Adapter.(StorageLinker); ok && spec.LinkStorage {
+		var links map[string]string
+		storageSet, links = linker.LinkStorageKeys(dbIndex, gdIndex, storageSet)
+		linker.UseStorageLinks(links)
 	}
 
 	return &ReconcileCache{
-		DBIndex:    dbIndex,
-		GDIndex:    gdIndex,
-		StorageSet: storageSet,
-		Built:      time.Now(),
-		TTL:        spec.CacheTTL,
+		DBIndex:      dbIndex,
+		GDIndex:      gdIndex,
+		StorageSet:   storageSet,
+		Built:        time.Now(),
+		TTL:          spec.CacheTTL,
+		gamedataETag: gamedataETag,
 	}, nil
 }
 
 // GetOrBuildCache retrieves a cache for the given spec from the store,
 // or builds a new one if it doesn't exist or has expired.
 // Uses singleflight to prevent cache stampedes.
+//
+// If spec.MaxStale is set, a cache expired for less than MaxStale is returned right
+// away while it is rebuilt in the background (stale-while-revalidate).
 func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
 	cacheKey := spec.CacheKey()
 
 	// Fast path: check if cache exists and is fresh
 	globalCacheStore.mu.RLock()
 	cache, exists := globalCacheStore.caches[cacheKey]
+	usable := exists && !cache.restored
+	fresh := usable && !cache.IsExpired()
+	stale := usable && !fresh && cache.servableStale(spec.MaxStale)
 	globalCacheStore.mu.RUnlock()
 
-	if exists && !cache.IsExpired() {
+	if fresh {
+		return cache, nil
+	}
+	if stale {
+		globalCacheStore.revalidate(ctx, spec, db, client, bucket)
 		return cache, nil
 	}
 
@@ -143,24 +224,26 @@ func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storag
 		// Double-check after acquiring singleflight lock
 		globalCacheStore.mu.RLock()
 		cache, exists := globalCacheStore.caches[cacheKey]
+		restored := exists && cache.restored
 		globalCacheStore.mu.RUnlock()
 
 		if exists && !cache.IsExpired() {
-			return cache, nil
+			if !restored {
+				return cache, nil
+			}
+			// Restored after a restart: use it only if gamedata did not change meanwhile
+			if validateRestored(ctx, spec, client, bucket, cache) {
+				if inc, ok := spec.Adapter.(Incremental); ok {
+					inc.UseGamedataIndex(cache.GDIndex)
+				}
+				globalCacheStore.mu.Lock()
+				cache.restored = false
+				globalCacheStore.mu.Unlock()
+				return cache, nil
+			}
 		}
 
-		// Build new cache
-		newCache, err := BuildCache(ctx, spec, db, client, bucket)
-		if err != nil {
-			return nil, err
-		}
-
-		// Store in cache
-		globalCacheStore.mu.Lock()
-		globalCacheStore.caches[cacheKey] = newCache
-		globalCacheStore.mu.Unlock()
-
-		return newCache, nil
+		return globalCacheStore.rebuild(ctx, spec, db, client, bucket)
 	})
 
 	if err != nil {
@@ -170,11 +253,54 @@ func GetOrBuildCache(ctx context.Context, spec *Spec, db *gorm.DB, client storag
 	return result.(*ReconcileCache), nil
 }
 
+// rebuild builds, stores and persists the cache of a spec, recording build metrics.
+// Callers must run it within the singleflight group of the cache key.
+func (s *cacheStore) rebuild(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
+	cacheKey := spec.CacheKey()
+
+	start := time.Now()
+	newCache, err := BuildCache(ctx, spec, db, client, bucket)
+	s.recordBuild(cacheKey, time.Since(start), err)
+	observeRun(spec.Adapter.Name(), OperationBuild, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store in cache
+	s.mu.Lock()
+	s.caches[cacheKey] = newCache
+	s.mu.Unlock()
+
+	s.persist(cacheKey, newCache)
+
+	return newCache, nil
+}
+
+// CheckPlanFresh returns ErrStalePlan if the cache the plan was computed from has since
+// been rebuilt, invalidated or updated from storage events, meaning the sources may
+// have changed after planning.
+func CheckPlanFresh(spec *Spec, plan *ReconcilePlan) error {
+	globalCacheStore.mu.RLock()
+	cache, exists := globalCacheStore.caches[spec.CacheKey()]
+	globalCacheStore.mu.RUnlock()
+
+	if !exists || !cache.Built.Equal(plan.CacheBuilt) || cache.Generation != plan.CacheGeneration {
+		return ErrStalePlan
+	}
+	return nil
+}
+
 // InvalidateCache removes the cache for the given spec from the store.
 // This is useful for testing or forcing a rebuild.
 func InvalidateCache(spec *Spec) {
 	cacheKey := spec.CacheKey()
 	globalCacheStore.mu.Lock()
+	defer globalCacheStore.mu.Unlock()
+
 	delete(globalCacheStore.caches, cacheKey)
-	globalCacheStore.mu.Unlock()
+	if p := globalCacheStore.persister; p != nil {
+		if err := p.Delete(cacheKey); err != nil {
+			globalCacheStore.persistError(cacheKey, err)
+		}
+	}
 }
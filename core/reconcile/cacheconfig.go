@@ -0,0 +1,55 @@
+package reconcile
+
+import "time"
+
+// CacheConfig holds the settings of the index caches behind the listing endpoints.
+type CacheConfig struct {
+	// TTLSeconds is how long the indices behind listing endpoints are reused before
+	// being rebuilt. If zero, every request rebuilds them.
+	TTLSeconds int `mapstructure:"ttl_seconds" default:"60"`
+
+	// MaxStaleSeconds is how long after expiring the listing indices are still served
+	// while being rebuilt in the background. If zero, requests wait for the rebuild.
+	MaxStaleSeconds int `mapstructure:"max_stale_seconds" default:"300"`
+
+	// RefreshSeconds is how often the listing indices are rebuilt in the background.
+	// If zero, they are only rebuilt on request.
+	RefreshSeconds int `mapstructure:"refresh_seconds" default:"0"`
+
+	// Store selects where built indices are kept: "memory" (lost on restart),
+	// "file" or "sqlite" (restored on startup while within their TTL).
+	Store string `mapstructure:"store" default:"memory"`
+
+	// Dir is the directory of the file and sqlite cache stores.
+	Dir string `mapstructure:"dir" default:".cache/reconcile"`
+}
+
+// TTL returns the listing cache expiry as a duration.
+func (c CacheConfig) TTL() time.Duration {
+	return time.Duration(c.TTLSeconds) * time.Second
+}
+
+// MaxStale returns the stale-while-revalidate window as a duration.
+func (c CacheConfig) MaxStale() time.Duration {
+	return time.Duration(c.MaxStaleSeconds) * time.Second
+}
+
+// RefreshInterval returns the background refresh interval as a duration.
+func (c CacheConfig) RefreshInterval() time.Duration {
+	return time.Duration(c.RefreshSeconds) * time.Second
+}
+
+// SnapshotConfig holds the settings of the index snapshots of incremental builds.
+type SnapshotConfig struct {
+	// Dir is the directory where index snapshots are kept so builds only reload what
+	// changed since the previous one. If empty, every build reloads all sources.
+	Dir string `mapstructure:"dir" default:""`
+}
+
+// Store returns the snapshot store of Dir, or nil if it is not set.
+func (c SnapshotConfig) Store() SnapshotStore {
+	if c.Dir == "" {
+		return nil
+	}
+	return NewFileSnapshotStore(c.Dir)
+}
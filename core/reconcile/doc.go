@@ -28,6 +28,59 @@
 //   - Batch DB queries (no row-by-row iteration)
 //   - Efficient union operations over in-memory maps
 //
+// # Safety
+//
+// ApplyPlan refuses purges that exceed the guard rails in ReconcileOptions.Safety
+// (maximum deletions per run, or an empty source) unless Force is set, and can record
+// a Journal so an applied plan can be reverted with UndoJournal.
+//
+// For two-step flows (plan now, confirm later), a PlanStore keeps plans until they
+// expire, and CheckPlanFresh refuses plans whose ReconcileCache was rebuilt since planning.
+//
+// On shutdown, Drain refuses new applies (ErrShuttingDown) and waits for running ones.
+// Past its deadline, they stop with ErrInterrupted at the next checkpoint, between two
+// actions or batches, so the journal covers exactly what was executed.
+//
+// # Progress
+//
+// Spec.Progress is notified with a Phase and processed/total counts while BuildCache
+// loads the indices and while ApplyPlan executes actions. Adapters report from their
+// Load methods with ReportProgress, which reads the observer from the context.
+//
+// # Observability
+//
+// SetRunObserver reports the duration and result of every build, plan and apply, e.g.
+// to export metrics. SetTracerProvider records a span for each of them and for each
+// load phase, as children of the span carried by the context.
+//
+// # Incremental Builds
+//
+// When Spec.Snapshots is set and the adapter implements Incremental, each build saves a
+// Snapshot of its indices with DB row checksums, the gamedata ETag and the ETag of every
+// storage object. The next build only loads DB rows whose checksum changed, skips the
+// gamedata download if its ETag is unchanged and only resolves new or changed storage
+// objects. Storage is still listed in full, since listing is how changes are detected.
+// ReconcileCache.Delta reports what was reloaded.
+//
+// # Background Refresh
+//
+// With Spec.MaxStale set, GetOrBuildCache serves a cache that expired less than MaxStale
+// ago and rebuilds it in the background (stale-while-revalidate), so requests do not wait
+// for a full rebuild. StartRefresher also rebuilds a cache every Spec.RefreshInterval.
+// Stats and AllStats report cache age, rebuild durations, failures and stale hits.
+//
+// # Storage Events
+//
+// ApplyObjectEvents updates a held cache from bucket notifications (see feature/notify):
+// created and removed objects are added to or removed from its StorageSet, and a change
+// of the gamedata object drops the cache so the next request rebuilds it.
+//
+// # Warm Restarts
+//
+// With SetCachePersister, GetOrBuildCache saves every cache built with a TTL and
+// RestoreCaches loads them back on startup. A restored cache is only reused, within its
+// original TTL, if the gamedata object still has the ETag it was built from.
+//
 // # Usage Example
 //
 //	adapter := furniture.NewAdapter(serverProfile)
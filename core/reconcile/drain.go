@@ -0,0 +1,81 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrShuttingDown is returned by ApplyPlan once Drain has been called.
+	ErrShuttingDown = errors.New("reconcile is shutting down")
+	// ErrInterrupted is returned by ApplyPlan when a run stops at a checkpoint before
+	// executing every action, because of a shutdown or a cancelled context.
+	ErrInterrupted = errors.New("reconcile apply interrupted")
+)
+
+// applies tracks the ApplyPlan runs of the process so shutdown can wait for them.
+var applies = struct {
+	mu       sync.Mutex
+	running  sync.WaitGroup
+	draining bool
+	stop     chan struct{}
+}{stop: make(chan struct{})}
+
+// beginApply registers a running ApplyPlan. It returns ErrShuttingDown once draining.
+// The returned function must be called when the run ends.
+func beginApply() (func(), error) {
+	applies.mu.Lock()
+	defer applies.mu.Unlock()
+	if applies.draining {
+		return nil, ErrShuttingDown
+	}
+	applies.running.Add(1)
+	return applies.running.Done, nil
+}
+
+// checkpoint reports whether a run should stop before its next action or batch.
+// Actions already executed stay journaled, so an interrupted run can be undone or
+// completed by planning again.
+func checkpoint(ctx context.Context) error {
+	select {
+	case <-applies.stop:
+		return ErrInterrupted
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrInterrupted, ctx.Err())
+	default:
+		return nil
+	}
+}
+
+// Drain stops ApplyPlan from starting new runs and waits for the running ones to finish.
+// If ctx is done first, running applies stop at their next checkpoint (between actions
+// or batches, never within one); Drain waits for them to return and reports ctx.Err().
+// Drain is meant for process shutdown and cannot be undone.
+func Drain(ctx context.Context) error {
+	applies.mu.Lock()
+	applies.draining = true
+	applies.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		applies.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		applies.mu.Lock()
+		select {
+		case <-applies.stop:
+		default:
+			close(applies.stop)
+		}
+		applies.mu.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
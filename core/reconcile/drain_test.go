@@ -0,0 +1,113 @@
+package reconcile
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingMutator blocks its first DB deletion until released.
+type blockingMutator struct {
+	mockMutator
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (m *blockingMutator) DeleteDB(ctx context.Context, key string) error {
+	m.once.Do(func() {
+		close(m.started)
+		<-m.release
+	})
+	return m.mockMutator.DeleteDB(ctx, key)
+}
+
+// resetDrain restores the process-wide drain state between tests.
+func resetDrain() {
+	applies.mu.Lock()
+	defer applies.mu.Unlock()
+	applies.draining = false
+	applies.stop = make(chan struct{})
+}
+
+// TestDrain_Checkpoint tests that a drain deadline stops a running apply between actions.
+func TestDrain_Checkpoint(t *testing.T) {
+	t.Cleanup(resetDrain)
+
+	mutator := &blockingMutator{started: make(chan struct{}), release: make(chan struct{})}
+	spec := &Spec{Adapter: mutator}
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionDeleteDB, Key: "1"},
+		{Type: ActionDeleteDB, Key: "2"},
+	}}
+	opts := ReconcileOptions{Confirmed: true}
+
+	type result struct {
+		executed int
+		err      error
+	}
+	results := make(chan result, 1)
+	go func() {
+		executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, opts)
+		results <- result{executed, err}
+	}()
+	<-mutator.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	drained := make(chan error, 1)
+	go func() { drained <- Drain(ctx) }()
+
+	// New runs are refused while draining
+	require.Eventually(t, func() bool {
+		applies.mu.Lock()
+		defer applies.mu.Unlock()
+		return applies.draining
+	}, time.Second, time.Millisecond)
+	_, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, opts)
+	assert.ErrorIs(t, err, ErrShuttingDown)
+
+	// The running action completes, the next one is not started
+	applies.mu.Lock()
+	stop := applies.stop
+	applies.mu.Unlock()
+	<-stop
+	close(mutator.release)
+
+	res := <-results
+	assert.ErrorIs(t, res.err, ErrInterrupted)
+	assert.Equal(t, 1, res.executed)
+	assert.Equal(t, []string{"1"}, mutator.deletedDB)
+	assert.ErrorIs(t, <-drained, context.DeadlineExceeded)
+}
+
+// TestDrain_Finish tests that runs finishing within the deadline are not interrupted.
+func TestDrain_Finish(t *testing.T) {
+	t.Cleanup(resetDrain)
+
+	mutator := &blockingMutator{started: make(chan struct{}), release: make(chan struct{})}
+	spec := &Spec{Adapter: mutator}
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionDeleteDB, Key: "1"},
+		{Type: ActionDeleteDB, Key: "2"},
+	}}
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+		results <- err
+	}()
+	<-mutator.started
+
+	drained := make(chan error, 1)
+	go func() { drained <- Drain(context.Background()) }()
+	close(mutator.release)
+
+	assert.NoError(t, <-results)
+	assert.NoError(t, <-drained)
+	assert.Equal(t, []string{"1", "2"}, mutator.deletedDB)
+}
@@ -48,7 +48,7 @@ func ReconcileOne(ctx context.Context, spec *Spec, db *gorm.DB, client storage.C
 		}
 
 		// Find the key from the query
-		key := findKeyFromQuery(query, cache.DBIndex, cache.GDIndex, spec.Adapter)
+		key := findKeyFromQuery(query, cache, spec.Adapter)
 		if key == "" {
 			// Not found in cache
 			return &ReconcileResult{
@@ -169,36 +169,25 @@ func buildResult(key string, dbIndex map[string]DBItem, gdIndex map[string]GDIte
 }
 
 // findKeyFromQuery attempts to find the entity key from a query using cached indices.
-func findKeyFromQuery(query Query, dbIndex map[string]DBItem, gdIndex map[string]GDItem, adapter Adapter) string {
+// IDs are matched against the keys, then classname and name against the search terms.
+func findKeyFromQuery(query Query, cache *ReconcileCache, adapter Adapter) string {
 	// Try direct key match first
 	if query.ID != "" {
-		if _, exists := dbIndex[query.ID]; exists {
+		if _, exists := cache.DBIndex[query.ID]; exists {
 			return query.ID
 		}
-		if _, exists := gdIndex[query.ID]; exists {
+		if _, exists := cache.GDIndex[query.ID]; exists {
 			return query.ID
 		}
 	}
 
-	// Search by name or classname in DB index
-	if query.Name != "" || query.Classname != "" {
-		for key, item := range dbIndex {
-			// This is adapter-specific; we can't generically compare here
-			// For now, just return the first match by key if ID matches
-			_ = item
-			if key == query.Name || key == query.Classname {
-				return key
-			}
+	index := cache.SearchIndex(adapter)
+	for _, term := range []string{query.Classname, query.Name} {
+		if term == "" {
+			continue
 		}
-	}
-
-	// Search in gamedata index
-	if query.Name != "" || query.Classname != "" {
-		for key, item := range gdIndex {
-			_ = item
-			if key == query.Name || key == query.Classname {
-				return key
-			}
+		if key := index.Lookup(term); key != "" {
+			return key
 		}
 	}
 
@@ -0,0 +1,220 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// JournalPrefix is the storage prefix under which journals are persisted.
+	JournalPrefix = ".reconcile/journal"
+
+	// QuarantinePrefix is the storage prefix under which deleted objects are kept
+	// until their journal is undone.
+	QuarantinePrefix = ".reconcile/quarantine"
+)
+
+// JournalEntry records a single mutation executed by ApplyPlan.
+type JournalEntry struct {
+	// Type is the action that was executed.
+	Type ActionType `json:"type"`
+
+	// Key is the entity identifier.
+	Key string `json:"key"`
+
+	// Data is the adapter-specific snapshot taken before the mutation
+	// (the DB row for DB actions, the raw gamedata entry for gamedata deletions).
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// Object is the original storage object key. Only set for storage deletions.
+	Object string `json:"object,omitempty"`
+
+	// QuarantineKey is the storage key of the quarantined (or trashed) copy. Only set for
+	// storage deletions.
+	QuarantineKey string `json:"quarantine_key,omitempty"`
+}
+
+// Journal is the undo log of a single ApplyPlan execution.
+type Journal struct {
+	// ID is the unique journal identifier.
+	ID string `json:"id"`
+
+	// Adapter is the name of the adapter that executed the mutations.
+	Adapter string `json:"adapter"`
+
+	// ServerProfile is the emulator profile the mutations were executed against.
+	ServerProfile string `json:"server_profile"`
+
+	// CreatedAt is the time the journal was opened.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UndoneAt is the time the journal was undone, or nil if it is still active.
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+
+	// Entries contains the recorded mutations in execution order.
+	Entries []JournalEntry `json:"entries"`
+}
+
+// NewJournal opens an empty journal for the given spec.
+func NewJournal(spec *Spec) *Journal {
+	return &Journal{
+		ID:            uuid.New().String(),
+		Adapter:       spec.Adapter.Name(),
+		ServerProfile: spec.ServerProfile,
+		CreatedAt:     time.Now().UTC(),
+		Entries:       []JournalEntry{},
+	}
+}
+
+// QuarantinePrefix returns the storage prefix holding this journal's quarantined objects.
+func (j *Journal) QuarantinePrefix() string {
+	return QuarantinePrefix + "/" + j.ID
+}
+
+// EntriesOf returns the entries matching any of the given action types.
+func (j *Journal) EntriesOf(types ...ActionType) []JournalEntry {
+	var entries []JournalEntry
+	for _, entry := range j.Entries {
+		for _, t := range types {
+			if entry.Type == t {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// journalObjectName returns the storage key of the journal with the given ID.
+func journalObjectName(id string) string {
+	return fmt.Sprintf("%s/%s.json", JournalPrefix, id)
+}
+
+// SaveJournal persists the journal to storage, overwriting any previous version.
+func SaveJournal(ctx context.Context, client storage.Client, bucket string, j *Journal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	_, err = client.PutObject(
+		ctx,
+		bucket,
+		journalObjectName(j.ID),
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/json"},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write journal %s: %w", j.ID, err)
+	}
+
+	return nil
+}
+
+// LoadJournal reads the journal with the given ID from storage.
+func LoadJournal(ctx context.Context, client storage.Client, bucket, id string) (*Journal, error) {
+	reader, err := client.GetObject(ctx, bucket, journalObjectName(id), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal %s: %w", id, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %w", id, err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", id, err)
+	}
+
+	return &j, nil
+}
+
+// UndoJournal restores every mutation recorded in the journal with the given ID.
+// Mutations are reverted in reverse order (storage, gamedata, then database) and the
+// journal is marked as undone. Returns the journal and the number of restored entries.
+func UndoJournal(ctx context.Context, spec *Spec, client storage.Client, bucket, id string) (*Journal, int, error) {
+	journaler, ok := spec.Adapter.(Journaler)
+	if !ok {
+		return nil, 0, fmt.Errorf("adapter %s does not implement Journaler interface", spec.Adapter.Name())
+	}
+
+	j, err := LoadJournal(ctx, client, bucket, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if j.Adapter != spec.Adapter.Name() {
+		return j, 0, fmt.Errorf("journal %s belongs to adapter %s, not %s", id, j.Adapter, spec.Adapter.Name())
+	}
+	if j.UndoneAt != nil {
+		return j, 0, fmt.Errorf("journal %s was already undone at %s", id, j.UndoneAt.Format(time.RFC3339))
+	}
+
+	restored := 0
+
+	if entries := j.EntriesOf(ActionDeleteStorage); len(entries) > 0 {
+		if err := journaler.RestoreStorage(ctx, entries); err != nil {
+			return j, restored, fmt.Errorf("failed to restore storage: %w", err)
+		}
+		restored += len(entries)
+	}
+
+	if entries := j.EntriesOf(ActionDeleteGamedata, ActionCreateGamedata); len(entries) > 0 {
+		if err := journaler.RestoreGamedata(ctx, entries); err != nil {
+			return j, restored, fmt.Errorf("failed to restore gamedata: %w", err)
+		}
+		restored += len(entries)
+	}
+
+	if entries := j.EntriesOf(ActionDeleteDB, ActionSyncDB, ActionCreateDB); len(entries) > 0 {
+		if err := journaler.RestoreDB(ctx, entries); err != nil {
+			return j, restored, fmt.Errorf("failed to restore database: %w", err)
+		}
+		restored += len(entries)
+	}
+
+	now := time.Now().UTC()
+	j.UndoneAt = &now
+	if err := SaveJournal(ctx, client, bucket, j); err != nil {
+		return j, restored, err
+	}
+
+	// Invalidate cached indices so the next reconcile sees the restored state
+	InvalidateCache(spec)
+
+	return j, restored, nil
+}
+
+// journalDBActions builds journal entries for DB actions from their captured rows.
+func journalDBActions(actions []Action) ([]JournalEntry, error) {
+	entries := make([]JournalEntry, 0, len(actions))
+	for _, action := range actions {
+		var data json.RawMessage
+		if action.DBItem != nil {
+			raw, err := json.Marshal(action.DBItem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal DB row for key %s: %w", action.Key, err)
+			}
+			data = raw
+		}
+		entries = append(entries, JournalEntry{
+			Type: action.Type,
+			Key:  action.Key,
+			Data: data,
+		})
+	}
+	return entries, nil
+}
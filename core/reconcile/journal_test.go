@@ -0,0 +1,202 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockJournaler implements Adapter, Mutator and Journaler for testing.
+type mockJournaler struct {
+	mockMutator
+	restoredDB       []JournalEntry
+	restoredGamedata []JournalEntry
+	restoredStorage  []JournalEntry
+}
+
+func (m *mockJournaler) CaptureGamedata(ctx context.Context, keys []string) ([]JournalEntry, error) {
+	entries := make([]JournalEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, JournalEntry{Type: ActionDeleteGamedata, Key: key, Data: json.RawMessage(`{"id":"` + key + `"}`)})
+	}
+	return entries, nil
+}
+
+func (m *mockJournaler) QuarantineStorage(ctx context.Context, keys []string, quarantinePrefix string) ([]JournalEntry, error) {
+	entries := make([]JournalEntry, 0, len(keys))
+	for _, key := range keys {
+		object := "bundled/" + key + ".nitro"
+		entries = append(entries, JournalEntry{Type: ActionDeleteStorage, Key: key, Object: object, QuarantineKey: quarantinePrefix + "/" + object})
+	}
+	return entries, nil
+}
+
+func (m *mockJournaler) RestoreDB(ctx context.Context, entries []JournalEntry) error {
+	m.restoredDB = append(m.restoredDB, entries...)
+	return nil
+}
+
+func (m *mockJournaler) RestoreGamedata(ctx context.Context, entries []JournalEntry) error {
+	m.restoredGamedata = append(m.restoredGamedata, entries...)
+	return nil
+}
+
+func (m *mockJournaler) RestoreStorage(ctx context.Context, entries []JournalEntry) error {
+	m.restoredStorage = append(m.restoredStorage, entries...)
+	return nil
+}
+
+// TestApplyPlan_RecordsJournal tests that every executed mutation is journaled and persisted.
+func TestApplyPlan_RecordsJournal(t *testing.T) {
+	adapter := &mockJournaler{}
+	spec := &Spec{Adapter: adapter, ServerProfile: "arcturus"}
+
+	client := mocks.NewMemory()
+
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1", DBItem: map[string]any{"id": 1}},
+			{Type: ActionDeleteGamedata, Key: "2"},
+			{Type: ActionDeleteStorage, Key: "3"},
+			{Type: ActionSyncDB, Key: "4", DBItem: map[string]any{"id": 4}},
+		},
+	}
+
+	journal := NewJournal(spec)
+	opts := ReconcileOptions{Confirmed: true, Journal: journal}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, client, "bucket", plan, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, executed)
+
+	assert.Len(t, journal.Entries, 4)
+	assert.JSONEq(t, `{"id":1}`, string(journal.Entries[0].Data))
+	assert.Equal(t, ActionDeleteGamedata, journal.Entries[1].Type)
+	assert.Equal(t, journal.QuarantinePrefix()+"/bundled/3.nitro", journal.Entries[2].QuarantineKey)
+	assert.Equal(t, ActionSyncDB, journal.Entries[3].Type)
+
+	// Journal is persisted in storage
+	stored, err := LoadJournal(context.Background(), client, "bucket", journal.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Entries, 4)
+	assert.Contains(t, client.Objects, JournalPrefix+"/"+journal.ID+".json")
+}
+
+// keepingJournaler implements StorageKeeper, recording the entries it is asked to delete.
+type keepingJournaler struct {
+	mockJournaler
+	deletedQuarantined []JournalEntry
+}
+
+func (m *keepingJournaler) DeleteQuarantinedStorage(ctx context.Context, entries []JournalEntry) error {
+	m.deletedQuarantined = append(m.deletedQuarantined, entries...)
+	return nil
+}
+
+// TestApplyPlan_DeletesQuarantinedStorage tests that a StorageKeeper is passed the objects
+// QuarantineStorage copied, and only deletes storage through it when journaling.
+func TestApplyPlan_DeletesQuarantinedStorage(t *testing.T) {
+	adapter := &keepingJournaler{}
+	spec := &Spec{Adapter: adapter}
+	client := mocks.NewMemory()
+
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionDeleteStorage, Key: "3"}}}
+	journal := NewJournal(spec)
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, client, "bucket", plan, ReconcileOptions{Confirmed: true, Journal: journal})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, journal.Entries, adapter.deletedQuarantined)
+	assert.Empty(t, adapter.deletedStorage)
+
+	// Without a journal nothing was copied, the mutator keeps its own copy
+	executed, err = ApplyPlan(context.Background(), spec, nil, client, "bucket", plan, ReconcileOptions{Confirmed: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Len(t, adapter.deletedQuarantined, 1)
+	assert.Equal(t, []string{"3"}, adapter.deletedStorage)
+}
+
+// journalingCreator implements Journaler and Creator; entities in existing are skipped.
+type journalingCreator struct {
+	mockJournaler
+	existing map[string]bool
+}
+
+func (m *journalingCreator) CreateDBFromGamedata(ctx context.Context, key string, gdItem GDItem) (bool, error) {
+	return !m.existing[key], nil
+}
+
+func (m *journalingCreator) CreateGamedataFromDB(ctx context.Context, key string, dbItem DBItem) (bool, error) {
+	return !m.existing[key], nil
+}
+
+// TestApplyPlan_JournalsCreatedOnly tests that entities skipped by a create are not
+// journaled, so undo does not remove what the run did not insert.
+func TestApplyPlan_JournalsCreatedOnly(t *testing.T) {
+	adapter := &journalingCreator{existing: map[string]bool{"2": true}}
+	spec := &Spec{Adapter: adapter}
+
+	plan := &ReconcilePlan{Actions: []Action{
+		{Type: ActionCreateDB, Key: "1"},
+		{Type: ActionCreateDB, Key: "2"},
+		{Type: ActionCreateGamedata, Key: "2"},
+		{Type: ActionCreateGamedata, Key: "3"},
+	}}
+	journal := NewJournal(spec)
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, mocks.NewMemory(), "bucket", plan, ReconcileOptions{Confirmed: true, Journal: journal})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, executed)
+	if assert.Len(t, journal.Entries, 2) {
+		assert.Equal(t, JournalEntry{Type: ActionCreateDB, Key: "1"}, journal.Entries[0])
+		assert.Equal(t, JournalEntry{Type: ActionCreateGamedata, Key: "3"}, journal.Entries[1])
+	}
+}
+
+// TestApplyPlan_JournalRequiresJournaler tests that journaling fails for adapters without undo support.
+func TestApplyPlan_JournalRequiresJournaler(t *testing.T) {
+	spec := &Spec{Adapter: &mockMutator{}}
+	plan := &ReconcilePlan{Actions: []Action{{Type: ActionDeleteDB, Key: "1"}}}
+
+	opts := ReconcileOptions{Confirmed: true, Journal: NewJournal(spec)}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, opts)
+	assert.Error(t, err)
+	assert.Equal(t, 0, executed)
+}
+
+// TestUndoJournal tests that undo restores every entry and marks the journal as undone.
+func TestUndoJournal(t *testing.T) {
+	adapter := &mockJournaler{}
+	spec := &Spec{Adapter: adapter}
+
+	client := mocks.NewMemory()
+
+	journal := NewJournal(spec)
+	journal.Entries = []JournalEntry{
+		{Type: ActionDeleteDB, Key: "1"},
+		{Type: ActionDeleteGamedata, Key: "2"},
+		{Type: ActionDeleteStorage, Key: "3"},
+		{Type: ActionSyncDB, Key: "4"},
+	}
+	assert.NoError(t, SaveJournal(context.Background(), client, "bucket", journal))
+
+	undone, restored, err := UndoJournal(context.Background(), spec, client, "bucket", journal.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, restored)
+	assert.NotNil(t, undone.UndoneAt)
+
+	assert.Len(t, adapter.restoredStorage, 1)
+	assert.Len(t, adapter.restoredGamedata, 1)
+	assert.Len(t, adapter.restoredDB, 2)
+
+	// A second undo is refused
+	_, _, err = UndoJournal(context.Background(), spec, client, "bucket", journal.ID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already undone")
+}
@@ -0,0 +1,280 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// Status classifies a reconcile result for listing filters.
+type Status string
+
+const (
+	// StatusComplete matches entities present in every source without mismatches.
+	StatusComplete Status = "complete"
+	// StatusMismatch matches entities whose DB fields differ from gamedata.
+	StatusMismatch Status = "mismatch"
+	// StatusMissingDB matches entities missing in the database.
+	StatusMissingDB Status = "missing_db"
+	// StatusMissingGamedata matches entities missing in gamedata.
+	StatusMissingGamedata Status = "missing_gamedata"
+	// StatusMissingStorage matches entities missing in storage.
+	StatusMissingStorage Status = "missing_storage"
+)
+
+// statuses lists every status in the order counts are reported.
+var statuses = []Status{StatusComplete, StatusMismatch, StatusMissingDB, StatusMissingGamedata, StatusMissingStorage}
+
+// ParseStatuses parses status names (e.g. from ?status=missing_storage,mismatch).
+// Returns an error for unknown names.
+func ParseStatuses(names []string) ([]Status, error) {
+	parsed := make([]Status, 0, len(names))
+	for _, name := range names {
+		status := Status(strings.ToLower(strings.TrimSpace(name)))
+		if !status.valid() {
+			return nil, fmt.Errorf("unknown status %q (expected complete, mismatch, missing_db, missing_gamedata or missing_storage)", name)
+		}
+		parsed = append(parsed, status)
+	}
+	return parsed, nil
+}
+
+// valid reports whether the status is known.
+func (s Status) valid() bool {
+	for _, status := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the result has the status.
+func (s Status) Matches(result ReconcileResult) bool {
+	switch s {
+	case StatusComplete:
+		return result.DBPresent && result.GamedataPresent && result.StoragePresent && len(result.Mismatch) == 0
+	case StatusMismatch:
+		return len(result.Mismatch) > 0
+	case StatusMissingDB:
+		return !result.DBPresent
+	case StatusMissingGamedata:
+		return !result.GamedataPresent
+	case StatusMissingStorage:
+		return !result.StoragePresent
+	}
+	return false
+}
+
+// ListFilter selects, orders and paginates reconcile results.
+// Every set criterion must match; values within a criterion are alternatives.
+type ListFilter struct {
+	// Statuses keeps results matching any of the statuses.
+	Statuses []Status
+
+	// Presence keeps results existing in exactly the sources of any of the patterns.
+	Presence []Presence
+
+	// Query keeps results whose ID, name or one of the SearchMetadata values
+	// contains it (case-insensitive).
+	Query string
+
+	// SearchMetadata lists the metadata keys Query is matched against (e.g. "classname").
+	SearchMetadata []string
+
+	// Metadata keeps results whose metadata equals every given value (e.g. "type": "i").
+	Metadata map[string]string
+
+	// Sort is "id", "name" or a metadata key. Defaults to "id".
+	// IDs are compared numerically when both are integers.
+	Sort string
+
+	// Desc reverses the sort order.
+	Desc bool
+
+	// Page is the 1-based page to return. Defaults to 1.
+	Page int
+
+	// Limit is the page size. If zero, every matching result is returned.
+	Limit int
+}
+
+// ListPage is one page of filtered reconcile results.
+type ListPage struct {
+	// Items are the results of the page.
+	Items []ReconcileResult `json:"items"`
+
+	// Total is the number of results matching the filter.
+	Total int `json:"total"`
+
+	// Page is the returned page.
+	Page int `json:"page"`
+
+	// Limit is the page size, 0 when unpaginated.
+	Limit int `json:"limit"`
+
+	// Pages is the number of pages of the filtered results.
+	Pages int `json:"pages"`
+
+	// Counts is the number of entities per status before filtering, plus "all".
+	Counts map[string]int `json:"counts"`
+
+	// CacheBuilt is when the indices the page was computed from were loaded.
+	CacheBuilt time.Time `json:"cache_built"`
+}
+
+// ListResults returns a page of reconcile results built from the cached indices.
+// The indices are reused until spec.CacheTTL expires.
+func ListResults(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, filter ListFilter) (*ListPage, error) {
+	cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	unionKeys := buildUnion(cache.DBIndex, cache.GDIndex, cache.StorageSet, spec.Adapter)
+
+	counts := make(map[string]int, len(statuses)+1)
+	counts["all"] = len(unionKeys)
+	for _, status := range statuses {
+		counts[string(status)] = 0
+	}
+
+	results := make([]ReconcileResult, 0, len(unionKeys))
+	for key := range unionKeys {
+		result := buildResult(key, cache.DBIndex, cache.GDIndex, cache.StorageSet, spec.Adapter)
+		for _, status := range statuses {
+			if status.Matches(result) {
+				counts[string(status)]++
+			}
+		}
+		if filter.matches(result) {
+			results = append(results, result)
+		}
+	}
+
+	sortResults(results, filter.Sort, filter.Desc)
+
+	page := &ListPage{
+		Total:      len(results),
+		Page:       max(filter.Page, 1),
+		Limit:      max(filter.Limit, 0),
+		Counts:     counts,
+		CacheBuilt: cache.Built,
+	}
+
+	if page.Limit == 0 {
+		page.Items = results
+		if page.Total > 0 {
+			page.Pages = 1
+		}
+		return page, nil
+	}
+
+	page.Pages = (page.Total + page.Limit - 1) / page.Limit
+	start := (page.Page - 1) * page.Limit
+	if start >= page.Total {
+		page.Items = []ReconcileResult{}
+		return page, nil
+	}
+	page.Items = results[start:min(start+page.Limit, page.Total)]
+
+	return page, nil
+}
+
+// matches reports whether the result passes every criterion of the filter.
+func (f ListFilter) matches(result ReconcileResult) bool {
+	if len(f.Statuses) > 0 {
+		ok := false
+		for _, status := range f.Statuses {
+			if status.Matches(result) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(f.Presence) > 0 {
+		ok := false
+		for _, p := range f.Presence {
+			if p.Matches(result) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	for key, value := range f.Metadata {
+		if result.Metadata[key] != value {
+			return false
+		}
+	}
+
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		ok := strings.Contains(strings.ToLower(result.ID), q) || strings.Contains(strings.ToLower(result.Name), q)
+		for _, key := range f.SearchMetadata {
+			if ok {
+				break
+			}
+			ok = strings.Contains(strings.ToLower(result.Metadata[key]), q)
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortResults orders results by the field, falling back to the ID for ties.
+func sortResults(results []ReconcileResult, field string, desc bool) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		c := 0
+		switch field {
+		case "", "id":
+		case "name":
+			c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		default:
+			c = strings.Compare(strings.ToLower(a.Metadata[field]), strings.ToLower(b.Metadata[field]))
+		}
+		if c == 0 {
+			c = compareIDs(a.ID, b.ID)
+		}
+
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// compareIDs compares IDs numerically when both are integers, lexically otherwise.
+func compareIDs(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
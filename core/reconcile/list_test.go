@@ -0,0 +1,148 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// listFixture returns a spec over entities 1-10 where 1-6 are complete, 7 has a mismatch,
+// 8 is missing in storage, 9 is only in storage and 10 is missing in gamedata.
+func listFixture(t *testing.T) (*Spec, *mocks.Client) {
+	t.Helper()
+
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{},
+		gdIndex:    map[string]GDItem{},
+		storageSet: map[string]struct{}{},
+		mismatches: map[string][]string{"7": {"name: gd=a db=b"}},
+		nameResolver: func(dbItem DBItem, gdItem GDItem) string {
+			if dbItem != nil {
+				return "chair " + dbItem.(string)
+			}
+			return ""
+		},
+	}
+	for _, key := range []string{"1", "2", "3", "4", "5", "6", "7", "8", "10"} {
+		adapter.dbIndex[key] = key
+	}
+	for _, key := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		adapter.gdIndex[key] = key
+	}
+	for _, key := range []string{"1", "2", "3", "4", "5", "6", "7", "9", "10"} {
+		adapter.storageSet[key] = struct{}{}
+	}
+
+	spec := &Spec{Adapter: adapter, ServerProfile: t.Name()}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+	return spec, client
+}
+
+func resultIDs(results []ReconcileResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// TestListResults_Counts tests that status counts cover every entity regardless of the filter.
+func TestListResults_Counts(t *testing.T) {
+	spec, client := listFixture(t)
+
+	page, err := ListResults(context.Background(), spec, nil, client, "", ListFilter{Statuses: []Status{StatusMismatch}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"7"}, resultIDs(page.Items))
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, map[string]int{
+		"all":              10,
+		"complete":         6,
+		"mismatch":         1,
+		"missing_db":       1,
+		"missing_gamedata": 2,
+		"missing_storage":  1,
+	}, page.Counts)
+}
+
+// TestListResults_Filters tests status, presence and query filters.
+func TestListResults_Filters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ListFilter
+		want   []string
+	}{
+		{"no filter sorts IDs numerically", ListFilter{}, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}},
+		{"statuses are alternatives", ListFilter{Statuses: []Status{StatusMissingStorage, StatusMissingDB}}, []string{"8", "9"}},
+		{"presence is exact", ListFilter{Presence: []Presence{{DB: true, Storage: true}}}, []string{"10"}},
+		{"query matches name", ListFilter{Query: "CHAIR 1"}, []string{"1", "10"}},
+		{"query matches ID", ListFilter{Query: "9"}, []string{"9"}},
+		{"criteria combine", ListFilter{Statuses: []Status{StatusMissingGamedata}, Query: "chair"}, []string{"10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, client := listFixture(t)
+
+			page, err := ListResults(context.Background(), spec, nil, client, "", tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resultIDs(page.Items))
+		})
+	}
+}
+
+// TestListResults_Pagination tests page bounds and descending order.
+func TestListResults_Pagination(t *testing.T) {
+	spec, client := listFixture(t)
+
+	page, err := ListResults(context.Background(), spec, nil, client, "", ListFilter{Desc: true, Page: 2, Limit: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6", "5", "4", "3"}, resultIDs(page.Items))
+	assert.Equal(t, 10, page.Total)
+	assert.Equal(t, 3, page.Pages)
+
+	page, err = ListResults(context.Background(), spec, nil, client, "", ListFilter{Page: 4, Limit: 4})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Equal(t, 10, page.Total)
+}
+
+// TestParseStatuses tests status parsing.
+func TestParseStatuses(t *testing.T) {
+	statuses, err := ParseStatuses([]string{"Missing_Storage", " mismatch"})
+	require.NoError(t, err)
+	assert.Equal(t, []Status{StatusMissingStorage, StatusMismatch}, statuses)
+
+	_, err = ParseStatuses([]string{"broken"})
+	assert.Error(t, err)
+}
+
+// TestListResults_CacheName tests that one-off scans over the same sources do not
+// replace a named listing cache.
+func TestListResults_CacheName(t *testing.T) {
+	spec, client := listFixture(t)
+	spec.CacheTTL = time.Hour
+	spec.CacheName = "listing"
+
+	listing, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+
+	scan := &Spec{Adapter: spec.Adapter, ServerProfile: spec.ServerProfile}
+	t.Cleanup(func() { InvalidateCache(scan) })
+	_, err = ReconcileAll(context.Background(), scan, nil, client, "")
+	require.NoError(t, err)
+
+	cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Same(t, listing, cache)
+	assert.NotEqual(t, spec.CacheKey(), scan.CacheKey())
+}
@@ -0,0 +1,135 @@
+package reconcile
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"asset-manager/core/storage"
+)
+
+// EventResult describes how storage events changed the cache of a spec.
+type EventResult struct {
+	// StorageAdded is the number of keys added to the storage set.
+	StorageAdded int `json:"storage_added"`
+
+	// StorageRemoved is the number of keys removed from the storage set.
+	StorageRemoved int `json:"storage_removed"`
+
+	// GamedataInvalidated is true if the gamedata object changed and the cache was dropped.
+	GamedataInvalidated bool `json:"gamedata_invalidated"`
+}
+
+// ApplyObjectEvents updates the cache of a spec after storage objects were created or
+// removed, so it reflects the bucket without waiting for its TTL. Objects under the
+// spec's prefix with its extension are added to or removed from the StorageSet; a change
+// of the gamedata object drops the cache (see InvalidateCache), since its index cannot
+// be patched. Does nothing if no cache is held for the spec.
+//
+// The cache is replaced rather than mutated, as requests may be reading it. Its Built
+// time is kept so its expiry is unaffected, and its Generation is incremented so plans
+// computed from it are refused as stale (see CheckPlanFresh).
+func ApplyObjectEvents(spec *Spec, events []storage.ObjectEvent) EventResult {
+	var result EventResult
+
+	for _, event := range events {
+		if event.Key == spec.GamedataObjectName {
+			result.GamedataInvalidated = true
+		}
+	}
+	if result.GamedataInvalidated {
+		InvalidateCache(spec)
+		return result
+	}
+
+	cacheKey := spec.CacheKey()
+
+	globalCacheStore.mu.RLock()
+	cache, exists := globalCacheStore.caches[cacheKey]
+	globalCacheStore.mu.RUnlock()
+
+	for exists {
+		// Resolve keys outside the lock, since linking walks the DB index
+		keys := make([][]string, len(events))
+		for i, event := range events {
+			keys[i] = storageKeys(spec, cache, event.Key)
+		}
+
+		globalCacheStore.mu.Lock()
+		current, ok := globalCacheStore.caches[cacheKey]
+		if ok && current == cache {
+			updated, result := patchStorageSet(cache, events, keys)
+			if updated != nil {
+				globalCacheStore.caches[cacheKey] = updated
+			}
+			globalCacheStore.mu.Unlock()
+
+			if updated != nil {
+				globalCacheStore.persist(cacheKey, updated)
+			}
+			return result
+		}
+		globalCacheStore.mu.Unlock()
+
+		// Rebuilt or updated in between: resolve against the new cache
+		cache, exists = current, ok
+	}
+	return result
+}
+
+// patchStorageSet returns a copy of the cache with the resolved keys of the events added
+// to or removed from its storage set, or nil if the events change nothing.
+func patchStorageSet(cache *ReconcileCache, events []storage.ObjectEvent, keys [][]string) (*ReconcileCache, EventResult) {
+	var result EventResult
+
+	storageSet := maps.Clone(cache.StorageSet)
+	for i, event := range events {
+		for _, key := range keys[i] {
+			_, present := storageSet[key]
+			switch {
+			case event.Removed && present:
+				delete(storageSet, key)
+				result.StorageRemoved++
+			case !event.Removed && !present:
+				storageSet[key] = struct{}{}
+				result.StorageAdded++
+			}
+		}
+	}
+
+	if result.StorageAdded == 0 && result.StorageRemoved == 0 {
+		return nil, result
+	}
+
+	return &ReconcileCache{
+		DBIndex:      cache.DBIndex,
+		GDIndex:      cache.GDIndex,
+		StorageSet:   storageSet,
+		Built:        cache.Built,
+		TTL:          cache.TTL,
+		Generation:   cache.Generation + 1,
+		Delta:        cache.Delta,
+		gamedataETag: cache.gamedataETag,
+		restored:     cache.restored,
+	}, result
+}
+
+// storageKeys resolves the storage set keys of an object of the spec, the way BuildCache
+// does. An object linked to several entities (see StorageLinker) resolves to all of them.
+func storageKeys(spec *Spec, cache *ReconcileCache, objectKey string) []string {
+	// BuildCache only lists objects under the prefix
+	if !strings.HasPrefix(objectKey, spec.StoragePrefix) {
+		return nil
+	}
+
+	key, ok := spec.Adapter.ExtractStorageKey(objectKey, spec.StoragePrefix, spec.StorageExtension)
+	if !ok {
+		return nil
+	}
+
+	if linker, ok := spec.Adapter.(StorageLinker); ok && spec.LinkStorage {
+		linked, _ := linker.LinkStorageKeys(cache.DBIndex, cache.GDIndex, map[string]struct{}{key: {}})
+		return slices.Sorted(maps.Keys(linked))
+	}
+	return []string{key}
+}
@@ -0,0 +1,181 @@
+package reconcile
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestApplyObjectEvents tests that storage events patch the cached storage set and that
+// gamedata changes drop the cache.
+func TestApplyObjectEvents(t *testing.T) {
+	adapter := &incrementalAdapter{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"A": "A"},
+		gdIndex:    map[string]GDItem{"A": "A"},
+		storageSet: map[string]struct{}{"A": {}, "B": {}},
+	}}
+	spec := &Spec{
+		Adapter:            adapter,
+		CacheTTL:           time.Minute,
+		StoragePrefix:      "events/",
+		StorageExtension:   ".nitro",
+		GamedataObjectName: "gamedata.json",
+	}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	// No cache held: nothing to update
+	assert.Equal(t, EventResult{}, ApplyObjectEvents(spec, []storage.ObjectEvent{{Key: "events/C.nitro"}}))
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	plan := &ReconcilePlan{CacheBuilt: cache.Built, CacheGeneration: cache.Generation}
+	require.NoError(t, CheckPlanFresh(spec, plan))
+
+	result := ApplyObjectEvents(spec, []storage.ObjectEvent{
+		{Key: "events/C.nitro"},
+		{Key: "events/B.nitro", Removed: true},
+		{Key: "events/A.nitro"},                // already present
+		{Key: "events/readme.txt"},             // other extension
+		{Key: "other/D.nitro"},                 // other prefix
+		{Key: "events/E.nitro", Removed: true}, // not present
+	})
+	assert.Equal(t, EventResult{StorageAdded: 1, StorageRemoved: 1}, result)
+
+	updated, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.NotSame(t, cache, updated)
+	assert.Equal(t, map[string]struct{}{"A": {}, "C": {}}, updated.StorageSet)
+	assert.True(t, cache.Built.Equal(updated.Built))
+
+	// Plans computed before the update are stale
+	assert.Equal(t, 1, updated.Generation)
+	assert.ErrorIs(t, CheckPlanFresh(spec, plan), ErrStalePlan)
+
+	// The cache being read is left untouched
+	assert.Equal(t, map[string]struct{}{"A": {}, "B": {}}, cache.StorageSet)
+
+	// A gamedata change drops the cache
+	result = ApplyObjectEvents(spec, []storage.ObjectEvent{{Key: "gamedata.json"}})
+	assert.True(t, result.GamedataInvalidated)
+	assert.False(t, Stats(spec).Cached)
+}
+
+// variantLinker links a storage object named like DB items to all of them, like color
+// variants sharing a bundle.
+type variantLinker struct {
+	mockAdapter
+	mu    sync.Mutex
+	links map[string]string
+}
+
+func (m *variantLinker) LinkStorageKeys(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}) (map[string]struct{}, map[string]string) {
+	linked := maps.Clone(storageSet)
+	links := make(map[string]string)
+	for key, item := range dbIndex {
+		if _, ok := storageSet[item.(string)]; ok {
+			linked[key] = struct{}{}
+			links[key] = item.(string)
+		}
+	}
+	for _, object := range links {
+		delete(linked, object)
+	}
+	return linked, links
+}
+
+func (m *variantLinker) UseStorageLinks(links map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.links = links
+}
+
+// TestApplyObjectEvents_LinkedKeys tests that an object linked to several entities
+// updates every one of them.
+func TestApplyObjectEvents_LinkedKeys(t *testing.T) {
+	adapter := &variantLinker{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "chair", "2": "chair", "3": "table"},
+		gdIndex:    map[string]GDItem{},
+		storageSet: map[string]struct{}{},
+	}}
+	spec := &Spec{Adapter: adapter, CacheTTL: time.Minute, GamedataObjectName: "gamedata.json", LinkStorage: true}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+	_, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+
+	result := ApplyObjectEvents(spec, []storage.ObjectEvent{{Key: "chair"}})
+	assert.Equal(t, EventResult{StorageAdded: 2}, result)
+	assert.Equal(t, map[string]struct{}{"1": {}, "2": {}}, cachedStorageSet(t, spec, client))
+
+	result = ApplyObjectEvents(spec, []storage.ObjectEvent{{Key: "chair", Removed: true}})
+	assert.Equal(t, EventResult{StorageRemoved: 2}, result)
+	assert.Empty(t, cachedStorageSet(t, spec, client))
+}
+
+// TestApplyObjectEvents_DuringBuild tests that storage events update the cache while a
+// plan is being built from the same adapter, without changing the links the build adopts.
+func TestApplyObjectEvents_DuringBuild(t *testing.T) {
+	adapter := &variantLinker{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "chair", "2": "chair", "3": "table"},
+		gdIndex:    map[string]GDItem{},
+		storageSet: map[string]struct{}{"chair": {}},
+	}}
+	spec := &Spec{Adapter: adapter, CacheTTL: time.Minute, GamedataObjectName: "gamedata.json", LinkStorage: true}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+	_, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	chairLinks := map[string]string{"1": "chair", "2": "chair"}
+	assert.Equal(t, chairLinks, adapter.links)
+
+	// The next build waits in the DB load until the event was applied
+	loading, release := make(chan struct{}), make(chan struct{})
+	adapter.dbLoadFunc = func(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]DBItem, error) {
+		close(loading)
+		<-release
+		return adapter.dbIndex, nil
+	}
+	built := make(chan *ReconcileCache, 1)
+	go func() {
+		cache, err := BuildCache(context.Background(), spec, nil, client, "")
+		assert.NoError(t, err)
+		built <- cache
+	}()
+	<-loading
+
+	result := ApplyObjectEvents(spec, []storage.ObjectEvent{{Key: "table"}})
+	assert.Equal(t, EventResult{StorageAdded: 1}, result)
+	assert.Equal(t, map[string]struct{}{"1": {}, "2": {}, "3": {}}, cachedStorageSet(t, spec, client))
+
+	adapter.mu.Lock()
+	assert.Equal(t, chairLinks, adapter.links, "Events do not change the links of the adapter")
+	adapter.mu.Unlock()
+
+	close(release)
+	cache := <-built
+	assert.Equal(t, map[string]struct{}{"1": {}, "2": {}}, cache.StorageSet)
+}
+
+// cachedStorageSet returns the storage set of the cache held for the spec.
+func cachedStorageSet(t *testing.T, spec *Spec, client *mocks.Client) map[string]struct{} {
+	cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	return cache.StorageSet
+}
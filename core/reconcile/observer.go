@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RunOperation names a timed reconcile operation reported to the RunObserver.
+type RunOperation string
+
+const (
+	// OperationBuild is a cache build by GetOrBuildCache, a refresher or a revalidation.
+	OperationBuild RunOperation = "build"
+	// OperationPlan is a plan computed by ReconcileWithPlan.
+	OperationPlan RunOperation = "plan"
+	// OperationApply is a plan executed by ApplyPlan.
+	OperationApply RunOperation = "apply"
+)
+
+// RunObserver is notified of every reconcile run in the process, e.g. to export metrics.
+// Implementations must be safe for concurrent use.
+type RunObserver interface {
+	// ObserveRun is called when an operation of an adapter ends.
+	ObserveRun(adapter string, operation RunOperation, duration time.Duration, err error)
+
+	// ObservePlan is called with the summary of every computed plan.
+	ObservePlan(adapter string, summary PlanSummary)
+}
+
+// runObserver holds the process-wide RunObserver.
+var runObserver atomic.Pointer[RunObserver]
+
+// SetRunObserver sets the observer notified of every reconcile run. Nil disables it.
+func SetRunObserver(o RunObserver) {
+	if o == nil {
+		runObserver.Store(nil)
+		return
+	}
+	runObserver.Store(&o)
+}
+
+// observeRun reports a finished operation to the RunObserver, if set.
+func observeRun(adapter string, operation RunOperation, start time.Time, err error) {
+	if o := runObserver.Load(); o != nil {
+		(*o).ObserveRun(adapter, operation, time.Since(start), err)
+	}
+}
+
+// observePlan reports a plan summary to the RunObserver, if set.
+func observePlan(adapter string, summary PlanSummary) {
+	if o := runObserver.Load(); o != nil {
+		(*o).ObservePlan(adapter, summary)
+	}
+}
@@ -0,0 +1,59 @@
+package reconcile
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver records the runs and plans it observes.
+type recordingObserver struct {
+	mu    sync.Mutex
+	runs  []RunOperation
+	plans []PlanSummary
+}
+
+func (o *recordingObserver) ObserveRun(adapter string, operation RunOperation, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.runs = append(o.runs, operation)
+}
+
+func (o *recordingObserver) ObservePlan(adapter string, summary PlanSummary) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.plans = append(o.plans, summary)
+}
+
+// TestRunObserver tests that builds and plans are reported to the run observer.
+func TestRunObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	SetRunObserver(observer)
+	t.Cleanup(func() { SetRunObserver(nil) })
+
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"A": "A"},
+		gdIndex:    map[string]GDItem{"A": "A", "B": "B"},
+		storageSet: map[string]struct{}{"A": {}},
+		mismatches: map[string][]string{},
+	}
+	spec := &Spec{Adapter: adapter, StoragePrefix: "observer/"}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := ReconcileWithPlan(context.Background(), spec, nil, client, "", ReconcileOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []RunOperation{OperationBuild, OperationPlan}, observer.runs)
+	require.Len(t, observer.plans, 1)
+	assert.Equal(t, 2, observer.plans[0].TotalItems)
+}
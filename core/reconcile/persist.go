@@ -0,0 +1,346 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// cacheFormatVersion is the format version of persisted caches. Other versions are ignored.
+const cacheFormatVersion = 1
+
+// CachePersister saves built caches so they survive restarts (see SetCachePersister).
+type CachePersister interface {
+	// Save replaces the persisted cache of the cache key.
+	Save(cacheKey string, cache *ReconcileCache) error
+
+	// LoadAll returns every persisted cache by cache key.
+	LoadAll() (map[string]*ReconcileCache, error)
+
+	// Delete removes the persisted cache of the cache key, if any.
+	Delete(cacheKey string) error
+}
+
+// NewCachePersister creates the persister selected by the configuration.
+// Returns nil for the default in-memory store, which does not persist caches.
+func NewCachePersister(cfg CacheConfig) (CachePersister, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return nil, nil
+	case "file":
+		return NewFileCachePersister(cfg.Dir), nil
+	case "sqlite":
+		if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		return NewSQLiteCachePersister(filepath.Join(cfg.Dir, "cache.db"))
+	default:
+		return nil, fmt.Errorf("unknown cache store %q (expected memory, file or sqlite)", cfg.Store)
+	}
+}
+
+// SetCachePersister makes GetOrBuildCache save every cache it builds with a TTL, and
+// InvalidateCache delete it. Save and delete errors are passed to onError, if set.
+// A nil persister disables persistence.
+func SetCachePersister(p CachePersister, onError func(cacheKey string, err error)) {
+	globalCacheStore.mu.Lock()
+	defer globalCacheStore.mu.Unlock()
+
+	globalCacheStore.persister = p
+	globalCacheStore.onPersistError = onError
+}
+
+// RestoreCaches loads the persisted caches into the cache store and returns how many
+// were restored. Expired caches are deleted instead. Restored caches are validated
+// against the gamedata ETag the first time they are used.
+func RestoreCaches() (int, error) {
+	globalCacheStore.mu.Lock()
+	defer globalCacheStore.mu.Unlock()
+
+	p := globalCacheStore.persister
+	if p == nil {
+		return 0, nil
+	}
+
+	caches, err := p.LoadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load persisted caches: %w", err)
+	}
+
+	restored := 0
+	for key, cache := range caches {
+		if cache.IsExpired() {
+			if err := p.Delete(key); err != nil {
+				globalCacheStore.persistError(key, err)
+			}
+			continue
+		}
+		if _, exists := globalCacheStore.caches[key]; exists {
+			continue
+		}
+		cache.restored = true
+		globalCacheStore.caches[key] = cache
+		restored++
+	}
+	return restored, nil
+}
+
+// persist saves a built cache if a persister is set and the cache has a TTL.
+func (s *cacheStore) persist(cacheKey string, cache *ReconcileCache) {
+	s.mu.RLock()
+	p := s.persister
+	s.mu.RUnlock()
+
+	if p == nil || cache.TTL == 0 {
+		return
+	}
+	if err := p.Save(cacheKey, cache); err != nil {
+		s.mu.RLock()
+		s.persistError(cacheKey, err)
+		s.mu.RUnlock()
+	}
+}
+
+// persistError reports a persistence error. The caller must hold s.mu.
+func (s *cacheStore) persistError(cacheKey string, err error) {
+	if s.onPersistError != nil {
+		s.onPersistError(cacheKey, err)
+	}
+}
+
+// persisting reports whether a persister is set.
+func (s *cacheStore) persisting() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.persister != nil
+}
+
+// validateRestored reports whether a cache restored from disk may still be used: its
+// gamedata object must be unchanged. DB and storage changes within the TTL are
+// tolerated, as for caches built in this process.
+func validateRestored(ctx context.Context, spec *Spec, client storage.Client, bucket string, cache *ReconcileCache) bool {
+	if cache.gamedataETag == "" {
+		return false
+	}
+	state, err := objectState(ctx, client, bucket, spec.GamedataObjectName)
+	return err == nil && state.ETag == cache.gamedataETag
+}
+
+// persistedCache is the encoded form of a ReconcileCache.
+type persistedCache struct {
+	Version      int
+	CacheKey     string
+	DBIndex      map[string]DBItem
+	GDIndex      map[string]GDItem
+	StorageSet   map[string]struct{}
+	Built        time.Time
+	TTL          time.Duration
+	GamedataETag string
+}
+
+// encodeCache serializes a cache with gob. Adapters must gob.Register their item types.
+func encodeCache(cacheKey string, cache *ReconcileCache) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(persistedCache{
+		Version:      cacheFormatVersion,
+		CacheKey:     cacheKey,
+		DBIndex:      cache.DBIndex,
+		GDIndex:      cache.GDIndex,
+		StorageSet:   cache.StorageSet,
+		Built:        cache.Built,
+		TTL:          cache.TTL,
+		GamedataETag: cache.gamedataETag,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCache deserializes a cache. Returns a nil cache for other format versions.
+func decodeCache(data []byte) (string, *ReconcileCache, error) {
+	var p persistedCache
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&p); err != nil {
+		return "", nil, fmt.Errorf("failed to decode cache: %w", err)
+	}
+	if p.Version != cacheFormatVersion {
+		return "", nil, nil
+	}
+	return p.CacheKey, &ReconcileCache{
+		DBIndex:      p.DBIndex,
+		GDIndex:      p.GDIndex,
+		StorageSet:   p.StorageSet,
+		Built:        p.Built,
+		TTL:          p.TTL,
+		gamedataETag: p.GamedataETag,
+	}, nil
+}
+
+// FileCachePersister stores each cache as a gob file in a directory.
+type FileCachePersister struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileCachePersister creates a persister writing to dir, created on first save.
+func NewFileCachePersister(dir string) *FileCachePersister {
+	return &FileCachePersister{dir: dir}
+}
+
+// path returns the file of the cache key.
+func (p *FileCachePersister) path(cacheKey string) string {
+	sum := sha256.Sum256([]byte(cacheKey))
+	return filepath.Join(p.dir, "cache-"+hex.EncodeToString(sum[:8])+".gob")
+}
+
+// Save implements CachePersister. The file is replaced atomically.
+func (p *FileCachePersister) Save(cacheKey string, cache *ReconcileCache) error {
+	data, err := encodeCache(cacheKey, cache)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(p.dir, "cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.path(cacheKey)); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
+
+// LoadAll implements CachePersister. Unreadable files are skipped.
+func (p *FileCachePersister) LoadAll() (map[string]*ReconcileCache, error) {
+	caches := make(map[string]*ReconcileCache)
+
+	files, err := filepath.Glob(filepath.Join(p.dir, "cache-*.gob"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache files: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		key, cache, err := decodeCache(data)
+		if err != nil || cache == nil || p.path(key) != file {
+			continue
+		}
+		caches[key] = cache
+	}
+	return caches, nil
+}
+
+// Delete implements CachePersister.
+func (p *FileCachePersister) Delete(cacheKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := os.Remove(p.path(cacheKey)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache file: %w", err)
+	}
+	return nil
+}
+
+// cacheRow is the SQLite representation of a persisted cache.
+type cacheRow struct {
+	CacheKey  string `gorm:"primaryKey"`
+	Data      []byte
+	UpdatedAt time.Time
+}
+
+// TableName sets the table of persisted caches.
+func (cacheRow) TableName() string {
+	return "reconcile_caches"
+}
+
+// SQLiteCachePersister stores caches in a SQLite database.
+type SQLiteCachePersister struct {
+	db *gorm.DB
+}
+
+// NewSQLiteCachePersister opens (or creates) the SQLite database at path.
+func NewSQLiteCachePersister(path string) (*SQLiteCachePersister, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache store %s: %w", path, err)
+	}
+
+	if err := db.AutoMigrate(&cacheRow{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate cache store: %w", err)
+	}
+
+	return &SQLiteCachePersister{db: db}, nil
+}
+
+// Save implements CachePersister.
+func (p *SQLiteCachePersister) Save(cacheKey string, cache *ReconcileCache) error {
+	data, err := encodeCache(cacheKey, cache)
+	if err != nil {
+		return err
+	}
+	if err := p.db.Save(&cacheRow{CacheKey: cacheKey, Data: data}).Error; err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+	return nil
+}
+
+// LoadAll implements CachePersister. Undecodable rows are skipped.
+func (p *SQLiteCachePersister) LoadAll() (map[string]*ReconcileCache, error) {
+	var rows []cacheRow
+	if err := p.db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load caches: %w", err)
+	}
+
+	caches := make(map[string]*ReconcileCache, len(rows))
+	for _, row := range rows {
+		key, cache, err := decodeCache(row.Data)
+		if err != nil || cache == nil || key != row.CacheKey {
+			continue
+		}
+		caches[row.CacheKey] = cache
+	}
+	return caches, nil
+}
+
+// Delete implements CachePersister.
+func (p *SQLiteCachePersister) Delete(cacheKey string) error {
+	if err := p.db.Delete(&cacheRow{CacheKey: cacheKey}).Error; err != nil {
+		return fmt.Errorf("failed to delete cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package reconcile
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// usePersister sets the global cache persister for the duration of the test.
+func usePersister(t *testing.T, p CachePersister) {
+	SetCachePersister(p, func(cacheKey string, err error) {
+		t.Errorf("persist %s: %v", cacheKey, err)
+	})
+	t.Cleanup(func() { SetCachePersister(nil, nil) })
+}
+
+// TestCachePersisters tests the round trip of every persister.
+func TestCachePersisters(t *testing.T) {
+	sqlitePersister, err := NewSQLiteCachePersister(filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+
+	persisters := map[string]CachePersister{
+		"file":   NewFileCachePersister(filepath.Join(t.TempDir(), "nested")),
+		"sqlite": sqlitePersister,
+	}
+
+	for name, p := range persisters {
+		t.Run(name, func(t *testing.T) {
+			cache := &ReconcileCache{
+				DBIndex:      map[string]DBItem{"1": "one"},
+				GDIndex:      map[string]GDItem{"1": "uno"},
+				StorageSet:   map[string]struct{}{"1": {}},
+				Built:        time.Unix(100, 0).UTC(),
+				TTL:          time.Minute,
+				gamedataETag: "gd1",
+			}
+
+			caches, err := p.LoadAll()
+			require.NoError(t, err)
+			assert.Empty(t, caches)
+
+			require.NoError(t, p.Save("key", cache))
+			require.NoError(t, p.Save("key", cache))
+
+			caches, err = p.LoadAll()
+			require.NoError(t, err)
+			require.Len(t, caches, 1)
+			loaded := caches["key"]
+			require.NotNil(t, loaded)
+			assert.Equal(t, cache.DBIndex, loaded.DBIndex)
+			assert.Equal(t, cache.GDIndex, loaded.GDIndex)
+			assert.Equal(t, cache.StorageSet, loaded.StorageSet)
+			assert.True(t, cache.Built.Equal(loaded.Built))
+			assert.Equal(t, cache.TTL, loaded.TTL)
+			assert.Equal(t, "gd1", loaded.gamedataETag)
+
+			require.NoError(t, p.Delete("key"))
+			require.NoError(t, p.Delete("key"))
+			caches, err = p.LoadAll()
+			require.NoError(t, err)
+			assert.Empty(t, caches)
+		})
+	}
+}
+
+// TestNewCachePersister tests the cache store selection.
+func TestNewCachePersister(t *testing.T) {
+	p, err := NewCachePersister(CacheConfig{Store: "memory"})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = NewCachePersister(CacheConfig{Store: "file", Dir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.IsType(t, &FileCachePersister{}, p)
+
+	p, err = NewCachePersister(CacheConfig{Store: "sqlite", Dir: filepath.Join(t.TempDir(), "nested")})
+	assert.NoError(t, err)
+	assert.IsType(t, &SQLiteCachePersister{}, p)
+
+	_, err = NewCachePersister(CacheConfig{Store: "redis"})
+	assert.Error(t, err)
+}
+
+// TestGetOrBuildCache_WarmRestart tests that built caches are persisted, restored after a
+// restart and validated against the gamedata ETag before being reused.
+func TestGetOrBuildCache_WarmRestart(t *testing.T) {
+	persister := NewFileCachePersister(t.TempDir())
+	usePersister(t, persister)
+
+	loads := 0
+	adapter := &mockAdapter{
+		gdLoadFunc: func(ctx context.Context, client storage.Client, bucket, objectName string, paths []string) (map[string]GDItem, error) {
+			loads++
+			return map[string]GDItem{"1": "one"}, nil
+		},
+	}
+	client := &listingClient{Client: new(mocks.Client), etags: map[string]string{"gamedata.json": "gd1"}}
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	spec := &Spec{
+		Adapter:            adapter,
+		CacheTTL:           time.Minute,
+		StoragePrefix:      "warm/",
+		GamedataObjectName: "gamedata.json",
+	}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	_, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	caches, err := persister.LoadAll()
+	require.NoError(t, err)
+	require.Contains(t, caches, spec.CacheKey())
+
+	// Restart with unchanged gamedata: the restored cache is reused
+	dropCache(spec)
+	restored, err := RestoreCaches()
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+
+	cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, map[string]GDItem{"1": "one"}, cache.GDIndex)
+
+	// Restart after gamedata changed: the restored cache is rebuilt
+	dropCache(spec)
+	_, err = RestoreCaches()
+	require.NoError(t, err)
+	client.etags["gamedata.json"] = "gd2"
+
+	_, err = GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// Invalidation also drops the persisted copy
+	InvalidateCache(spec)
+	caches, err = persister.LoadAll()
+	require.NoError(t, err)
+	assert.NotContains(t, caches, spec.CacheKey())
+}
+
+// TestRestoreCaches_Expired tests that expired caches are deleted instead of restored.
+func TestRestoreCaches_Expired(t *testing.T) {
+	persister := NewFileCachePersister(t.TempDir())
+	usePersister(t, persister)
+
+	require.NoError(t, persister.Save("expired", &ReconcileCache{Built: time.Now().Add(-time.Hour), TTL: time.Minute}))
+
+	restored, err := RestoreCaches()
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+
+	caches, err := persister.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, caches)
+}
+
+// dropCache removes a cache from memory only, as a restart would.
+func dropCache(spec *Spec) {
+	globalCacheStore.mu.Lock()
+	delete(globalCacheStore.caches, spec.CacheKey())
+	globalCacheStore.mu.Unlock()
+}
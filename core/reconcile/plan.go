@@ -3,6 +3,8 @@ package reconcile
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"asset-manager/core/storage"
 
@@ -19,7 +21,14 @@ func ReconcileWithPlan(
 	client storage.Client,
 	bucket string,
 	opts ReconcileOptions,
-) (*ReconcilePlan, error) {
+) (_ *ReconcilePlan, err error) {
+	start := time.Now()
+	ctx, span := startSpan(ctx, string(OperationPlan), spec)
+	defer func() {
+		endSpan(span, err)
+		observeRun(spec.Adapter.Name(), OperationPlan, start, err)
+	}()
+
 	// Build cache (which loads all indices concurrently)
 	cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
 	if err != nil {
@@ -35,16 +44,32 @@ func ReconcileWithPlan(
 	// Build summary and actions
 	summary, actions := buildPlanFromResults(results, cache, spec.Adapter, opts)
 
-	return &ReconcilePlan{
-		Results: results,
-		Actions: actions,
-		Summary: summary,
-	}, nil
+	plan := &ReconcilePlan{
+		Results:         results,
+		Actions:         actions,
+		Summary:         summary,
+		CacheBuilt:      cache.Built,
+		CacheGeneration: cache.Generation,
+		Delta:           cache.Delta,
+	}
+
+	// Report guard rail violations up front so dry-runs show them
+	if reason := CheckSafety(plan, opts.Safety); reason != "" {
+		plan.Summary.BlockedReason = reason
+		plan.Summary.Forced = opts.Force
+	}
+
+	observePlan(spec.Adapter.Name(), plan.Summary)
+
+	return plan, nil
 }
 
 // ApplyPlan executes the actions in a reconcile plan.
 // Returns the number of actions executed and any error encountered.
 // Requires opts.Confirmed=true and opts.DryRun=false to actually execute.
+// Plans exceeding opts.Safety are refused with ErrSafetyThreshold unless opts.Force is set.
+// Runs stop with ErrInterrupted at the next checkpoint (before an action or batch) once
+// ctx is done or a Drain deadline passes, and are refused with ErrShuttingDown after Drain.
 func ApplyPlan(
 	ctx context.Context,
 	spec *Spec,
@@ -59,23 +84,59 @@ func ApplyPlan(
 		return 0, nil
 	}
 
+	// Shutdown waits for running applies (see Drain)
+	release, err := beginApply()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	start := time.Now()
+	ctx, span := startSpan(ctx, string(OperationApply), spec)
+	defer func() {
+		endSpan(span, err)
+		observeRun(spec.Adapter.Name(), OperationApply, start, err)
+	}()
+
+	// Guard rails: refuse runs that would delete too much
+	if reason := CheckSafety(plan, opts.Safety); reason != "" {
+		plan.Summary.BlockedReason = reason
+		plan.Summary.Forced = opts.Force
+		if !opts.Force {
+			return 0, fmt.Errorf("%w: %s (use force to override)", ErrSafetyThreshold, reason)
+		}
+	}
+
 	// Check if adapter implements Mutator
 	mutator, ok := spec.Adapter.(Mutator)
 	if !ok {
 		return 0, fmt.Errorf("adapter %s does not implement Mutator interface", spec.Adapter.Name())
 	}
 
+	// Journaling requires the adapter to capture and restore entities
+	var journaler Journaler
+	if opts.Journal != nil {
+		journaler, ok = mutator.(Journaler)
+		if !ok {
+			return 0, fmt.Errorf("adapter %s does not implement Journaler interface", spec.Adapter.Name())
+		}
+	}
+
 	// Group actions by type for efficient execution
 	var (
-		deleteDBKeys       []string
-		deleteGamedataKeys []string
-		deleteStorageKeys  []string
-		syncActions        []Action
+		deleteDBActions       []Action
+		deleteDBKeys          []string
+		deleteGamedataKeys    []string
+		deleteStorageKeys     []string
+		syncActions           []Action
+		createActions         []Action
+		createGamedataActions []Action
 	)
 
 	for _, action := range plan.Actions {
 		switch action.Type {
 		case ActionDeleteDB:
+			deleteDBActions = append(deleteDBActions, action)
 			deleteDBKeys = append(deleteDBKeys, action.Key)
 		case ActionDeleteGamedata:
 			deleteGamedataKeys = append(deleteGamedataKeys, action.Key)
@@ -83,95 +144,310 @@ func ApplyPlan(
 			deleteStorageKeys = append(deleteStorageKeys, action.Key)
 		case ActionSyncDB:
 			syncActions = append(syncActions, action)
+		case ActionCreateDB:
+			createActions = append(createActions, action)
+		case ActionCreateGamedata:
+			createGamedataActions = append(createGamedataActions, action)
+		}
+	}
+
+	// Creation requires the adapter to build DB entities from gamedata
+	var creator Creator
+	if len(createActions) > 0 || len(createGamedataActions) > 0 {
+		creator, ok = mutator.(Creator)
+		if !ok {
+			return 0, fmt.Errorf("adapter %s does not implement Creator interface", spec.Adapter.Name())
 		}
 	}
 
+	// Report progress after each executed action or batch, and the outcome of
+	// every action (actions of a batch share the batch error)
+	ctx = WithProgress(ctx, spec.Progress)
+	ctx = WithActionObserver(ctx, spec.OnAction)
+	reportApply := func() {
+		ReportProgress(ctx, PhaseApply, executed, len(plan.Actions))
+	}
+	reportActions := func(actions []Action, err error) {
+		for _, action := range actions {
+			ReportAction(ctx, action, err)
+		}
+	}
+	reportKeys := func(actionType ActionType, keys []string, err error) {
+		for _, key := range keys {
+			ReportAction(ctx, Action{Type: actionType, Key: key}, err)
+		}
+	}
+	reportApply()
+
 	// Execute deletions (purge actions) using batch methods if available
 
 	// DB deletions
 	if len(deleteDBKeys) > 0 {
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+		if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+			return journalDBActions(deleteDBActions)
+		}); err != nil {
+			return executed, err
+		}
+
 		// Try batch delete first
 		type DBBatchDeleter interface {
 			DeleteDBBatch(ctx context.Context, keys []string) error
 		}
 		if batchDeleter, ok := mutator.(DBBatchDeleter); ok {
 			if err := batchDeleter.DeleteDBBatch(ctx, deleteDBKeys); err != nil {
+				reportActions(deleteDBActions, err)
 				return executed, fmt.Errorf("failed to batch delete DB keys: %w", err)
 			}
 			executed += len(deleteDBKeys)
+			reportActions(deleteDBActions, nil)
+			reportApply()
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteDBKeys {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
 				if err := mutator.DeleteDB(ctx, key); err != nil {
+					reportKeys(ActionDeleteDB, []string{key}, err)
 					return executed, fmt.Errorf("failed to delete DB key %s: %w", key, err)
 				}
 				executed++
+				reportKeys(ActionDeleteDB, []string{key}, nil)
+				reportApply()
 			}
 		}
 	}
 
 	// Gamedata deletions
 	if len(deleteGamedataKeys) > 0 {
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+		if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+			return journaler.CaptureGamedata(ctx, deleteGamedataKeys)
+		}); err != nil {
+			return executed, err
+		}
+
 		// Try batch delete first
 		type GDBatchDeleter interface {
 			DeleteGamedataBatch(ctx context.Context, keys []string) error
 		}
 		if batchDeleter, ok := mutator.(GDBatchDeleter); ok {
 			if err := batchDeleter.DeleteGamedataBatch(ctx, deleteGamedataKeys); err != nil {
+				reportKeys(ActionDeleteGamedata, deleteGamedataKeys, err)
 				return executed, fmt.Errorf("failed to batch delete gamedata keys: %w", err)
 			}
 			executed += len(deleteGamedataKeys)
+			reportKeys(ActionDeleteGamedata, deleteGamedataKeys, nil)
+			reportApply()
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteGamedataKeys {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
 				if err := mutator.DeleteGamedata(ctx, key); err != nil {
+					reportKeys(ActionDeleteGamedata, []string{key}, err)
 					return executed, fmt.Errorf("failed to delete gamedata key %s: %w", key, err)
 				}
 				executed++
+				reportKeys(ActionDeleteGamedata, []string{key}, nil)
+				reportApply()
 			}
 		}
 	}
 
 	// Storage deletions
 	if len(deleteStorageKeys) > 0 {
-		// Try batch delete first
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+		var quarantined []JournalEntry
+		if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+			entries, err := journaler.QuarantineStorage(ctx, deleteStorageKeys, opts.Journal.QuarantinePrefix())
+			quarantined = entries
+			return entries, err
+		}); err != nil {
+			return executed, err
+		}
+
+		// Try batch delete first, skipping the copies of mutators keeping their own
 		type StorageBatchDeleter interface {
 			DeleteStorageBatch(ctx context.Context, keys []string) error
 		}
-		if batchDeleter, ok := mutator.(StorageBatchDeleter); ok {
+		if keeper, ok := mutator.(StorageKeeper); ok && opts.Journal != nil {
+			if err := keeper.DeleteQuarantinedStorage(ctx, quarantined); err != nil {
+				reportKeys(ActionDeleteStorage, deleteStorageKeys, err)
+				return executed, fmt.Errorf("failed to delete quarantined storage keys: %w", err)
+			}
+			executed += len(deleteStorageKeys)
+			reportKeys(ActionDeleteStorage, deleteStorageKeys, nil)
+			reportApply()
+		} else if batchDeleter, ok := mutator.(StorageBatchDeleter); ok {
 			if err := batchDeleter.DeleteStorageBatch(ctx, deleteStorageKeys); err != nil {
+				reportKeys(ActionDeleteStorage, deleteStorageKeys, err)
 				return executed, fmt.Errorf("failed to batch delete storage keys: %w", err)
 			}
 			executed += len(deleteStorageKeys)
+			reportKeys(ActionDeleteStorage, deleteStorageKeys, nil)
+			reportApply()
 		} else {
 			// Fallback to one-at-a-time
 			for _, key := range deleteStorageKeys {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
 				if err := mutator.DeleteStorage(ctx, key); err != nil {
+					reportKeys(ActionDeleteStorage, []string{key}, err)
 					return executed, fmt.Errorf("failed to delete storage key %s: %w", key, err)
 				}
 				executed++
+				reportKeys(ActionDeleteStorage, []string{key}, nil)
+				reportApply()
+			}
+		}
+	}
+
+	// Execute DB creates. Only the rows actually inserted are journaled after the
+	// fact: entities that already exist are skipped and must survive an undo.
+	if len(createActions) > 0 {
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+
+		// Try batch create first
+		type CreateBatcher interface {
+			CreateDBBatch(ctx context.Context, actions []Action) ([]string, error)
+		}
+		if batchCreator, ok := mutator.(CreateBatcher); ok {
+			created, err := batchCreator.CreateDBBatch(ctx, createActions)
+			// Rows inserted before a failure are journaled too
+			if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+				return journalDBActions(actionsOf(createActions, created))
+			}); err != nil {
+				return executed, err
+			}
+			if err != nil {
+				reportActions(createActions, err)
+				return executed, fmt.Errorf("failed to batch create DB: %w", err)
+			}
+			executed += len(createActions)
+			reportActions(createActions, nil)
+			reportApply()
+		} else {
+			// Fallback to one-at-a-time
+			for _, action := range createActions {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
+				created, err := creator.CreateDBFromGamedata(ctx, action.Key, action.GDItem)
+				if err != nil {
+					ReportAction(ctx, action, err)
+					return executed, fmt.Errorf("failed to create DB key %s: %w", action.Key, err)
+				}
+				if created {
+					if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+						return journalDBActions([]Action{action})
+					}); err != nil {
+						return executed, err
+					}
+				}
+				executed++
+				ReportAction(ctx, action, nil)
+				reportApply()
+			}
+		}
+	}
+
+	// Execute gamedata creates, journaling only the entries actually appended
+	if len(createGamedataActions) > 0 {
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+
+		// Try batch create first
+		type GDCreateBatcher interface {
+			CreateGamedataBatch(ctx context.Context, actions []Action) ([]string, error)
+		}
+		if batchCreator, ok := mutator.(GDCreateBatcher); ok {
+			created, err := batchCreator.CreateGamedataBatch(ctx, createGamedataActions)
+			if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+				return journalDBActions(actionsOf(createGamedataActions, created))
+			}); err != nil {
+				return executed, err
+			}
+			if err != nil {
+				reportActions(createGamedataActions, err)
+				return executed, fmt.Errorf("failed to batch create gamedata: %w", err)
+			}
+			executed += len(createGamedataActions)
+			reportActions(createGamedataActions, nil)
+			reportApply()
+		} else {
+			// Fallback to one-at-a-time
+			for _, action := range createGamedataActions {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
+				created, err := creator.CreateGamedataFromDB(ctx, action.Key, action.DBItem)
+				if err != nil {
+					ReportAction(ctx, action, err)
+					return executed, fmt.Errorf("failed to create gamedata key %s: %w", action.Key, err)
+				}
+				if created {
+					if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+						return journalDBActions([]Action{action})
+					}); err != nil {
+						return executed, err
+					}
+				}
+				executed++
+				ReportAction(ctx, action, nil)
+				reportApply()
 			}
 		}
 	}
 
 	// Execute syncs
 	if len(syncActions) > 0 {
+		if err := checkpoint(ctx); err != nil {
+			return executed, err
+		}
+		if err := journalStep(ctx, client, bucket, opts.Journal, func() ([]JournalEntry, error) {
+			return journalDBActions(syncActions)
+		}); err != nil {
+			return executed, err
+		}
+
 		// Try batch sync first
 		type SyncBatcher interface {
 			SyncDBBatch(ctx context.Context, actions []Action) error
 		}
 		if batchSyncer, ok := mutator.(SyncBatcher); ok {
 			if err := batchSyncer.SyncDBBatch(ctx, syncActions); err != nil {
+				reportActions(syncActions, err)
 				return executed, fmt.Errorf("failed to batch sync DB: %w", err)
 			}
 			executed += len(syncActions)
+			reportActions(syncActions, nil)
+			reportApply()
 		} else {
 			// Fallback to one-at-a-time
 			for _, action := range syncActions {
+				if err := checkpoint(ctx); err != nil {
+					return executed, err
+				}
 				if err := mutator.SyncDBFromGamedata(ctx, action.Key, action.GDItem); err != nil {
+					ReportAction(ctx, action, err)
 					return executed, fmt.Errorf("failed to sync key %s: %w", action.Key, err)
 				}
 				executed++
+				ReportAction(ctx, action, nil)
+				reportApply()
 			}
 		}
 	}
@@ -179,6 +455,39 @@ func ApplyPlan(
 	return executed, nil
 }
 
+// journalStep captures the state of a group of actions before it is executed and
+// persists the journal, so a run that fails midway can still be undone.
+// It is a no-op when no journal was requested.
+func journalStep(ctx context.Context, client storage.Client, bucket string, j *Journal, capture func() ([]JournalEntry, error)) error {
+	if j == nil {
+		return nil
+	}
+
+	entries, err := capture()
+	if err != nil {
+		return fmt.Errorf("failed to journal mutations: %w", err)
+	}
+
+	j.Entries = append(j.Entries, entries...)
+	return SaveJournal(ctx, client, bucket, j)
+}
+
+// actionsOf returns the actions whose key is one of keys, in order.
+func actionsOf(actions []Action, keys []string) []Action {
+	wanted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		wanted[key] = struct{}{}
+	}
+
+	filtered := make([]Action, 0, len(keys))
+	for _, action := range actions {
+		if _, ok := wanted[action.Key]; ok {
+			filtered = append(filtered, action)
+		}
+	}
+	return filtered
+}
+
 // ReconcileAndApply is a convenience wrapper that plans and optionally applies actions.
 // It returns the plan, number of actions executed, and any error.
 func ReconcileAndApply(
@@ -246,20 +555,49 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 			summary.Mismatches++
 		}
 
+		// Plan create actions: repair entities missing only in DB
+		if opts.DoCreateDB && result.GamedataPresent && result.StoragePresent && !result.DBPresent {
+			actions = append(actions, Action{
+				Type:   ActionCreateDB,
+				Key:    result.ID,
+				Reason: "missing in: [database]",
+				GDItem: cache.GDIndex[result.ID],
+			})
+			summary.CreateActions++
+			// Creation takes precedence: skip purge for this item
+			continue
+		}
+
+		// Plan create actions: repair entities missing only in gamedata
+		if opts.DoCreateGamedata && result.DBPresent && result.StoragePresent && !result.GamedataPresent {
+			actions = append(actions, Action{
+				Type:   ActionCreateGamedata,
+				Key:    result.ID,
+				Reason: "missing in: [gamedata]",
+				DBItem: cache.DBIndex[result.ID],
+			})
+			summary.CreateActions++
+			// Creation takes precedence: skip purge for this item
+			continue
+		}
+
 		// Plan purge actions: delete if missing in ANY store
 		if opts.DoPurge {
 			missingInAny := !result.GamedataPresent || !result.StoragePresent || !result.DBPresent
-			if missingInAny {
-				// Delete from all stores
-				if result.DBPresent {
+			if missingInAny && purgePresence(opts, result) {
+				purged := summary.PurgeActions
+
+				// Delete from every store in scope
+				if result.DBPresent && purgeScope(opts, SourceDB) {
 					actions = append(actions, Action{
 						Type:   ActionDeleteDB,
 						Key:    result.ID,
 						Reason: getMissingReason(result),
+						DBItem: cache.DBIndex[result.ID],
 					})
 					summary.PurgeActions++
 				}
-				if result.GamedataPresent {
+				if result.GamedataPresent && purgeScope(opts, SourceGamedata) {
 					actions = append(actions, Action{
 						Type:   ActionDeleteGamedata,
 						Key:    result.ID,
@@ -267,7 +605,7 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 					})
 					summary.PurgeActions++
 				}
-				if result.StoragePresent {
+				if result.StoragePresent && purgeScope(opts, SourceStorage) {
 					actions = append(actions, Action{
 						Type:   ActionDeleteStorage,
 						Key:    result.ID,
@@ -276,7 +614,9 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 					summary.PurgeActions++
 				}
 				// Purge takes precedence: skip sync for this item
-				continue
+				if summary.PurgeActions > purged {
+					continue
+				}
 			}
 		}
 
@@ -289,15 +629,49 @@ func buildPlanFromResults(results []ReconcileResult, cache *ReconcileCache, adap
 					Key:    result.ID,
 					Reason: fmt.Sprintf("mismatch: %v", result.Mismatch),
 					GDItem: gdItem,
+					DBItem: cache.DBIndex[result.ID],
 				})
 				summary.SyncActions++
 			}
 		}
 	}
 
+	// Never delete a storage object other entities still use
+	if sharer, ok := adapter.(StorageSharer); ok && opts.DoPurge {
+		actions = keepSharedStorage(actions, &summary, sharer.SharedStorage(cache.DBIndex, cache.GDIndex, cache.StorageSet))
+	}
+
 	return summary, actions
 }
 
+// keepSharedStorage drops the storage deletions of shared objects still used by an
+// entity whose storage is not deleted by the plan. They are left for a later run.
+func keepSharedStorage(actions []Action, summary *PlanSummary, shared map[string][]string) []Action {
+	if len(shared) == 0 {
+		return actions
+	}
+
+	deleted := make(map[string]bool)
+	for _, action := range actions {
+		if action.Type == ActionDeleteStorage {
+			deleted[action.Key] = true
+		}
+	}
+
+	kept := actions[:0]
+	for _, action := range actions {
+		if action.Type == ActionDeleteStorage && slices.ContainsFunc(shared[action.Key], func(user string) bool {
+			return !deleted[user]
+		}) {
+			summary.PurgeActions--
+			summary.SharedStorageKept++
+			continue
+		}
+		kept = append(kept, action)
+	}
+	return kept
+}
+
 // getMissingReason builds a reason string for why an entity should be purged.
 func getMissingReason(result ReconcileResult) string {
 	var missing []string
@@ -2,6 +2,7 @@ package reconcile
 
 import (
 	"context"
+	"maps"
 	"testing"
 
 	"asset-manager/core/storage/mocks"
@@ -256,3 +257,172 @@ func (m *mockMutator) SyncDBFromGamedata(ctx context.Context, key string, gdItem
 	m.synced = append(m.synced, key)
 	return nil
 }
+
+// mockCreator implements Adapter, Mutator and Creator for testing.
+type mockCreator struct {
+	mockMutator
+	created         []string
+	createdGamedata []string
+}
+
+func (m *mockCreator) CreateDBFromGamedata(ctx context.Context, key string, gdItem GDItem) (bool, error) {
+	m.created = append(m.created, key)
+	return true, nil
+}
+
+func (m *mockCreator) CreateGamedataFromDB(ctx context.Context, key string, dbItem DBItem) (bool, error) {
+	m.createdGamedata = append(m.createdGamedata, key)
+	return true, nil
+}
+
+// TestReconcileWithPlan_CreateActions tests that create actions take precedence over purge.
+func TestReconcileWithPlan_CreateActions(t *testing.T) {
+	adapter := &mockCreator{mockMutator: mockMutator{mockAdapter: mockAdapter{
+		dbIndex: map[string]DBItem{},
+		gdIndex: map[string]GDItem{
+			"1": "item1", // In gamedata and storage: created
+			"2": "item2", // In gamedata only: purged
+		},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{},
+	}}}
+
+	spec := &Spec{Adapter: adapter}
+	opts := ReconcileOptions{DoPurge: true, DoCreateDB: true, Confirmed: true}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.CreateActions)
+	assert.Equal(t, 1, plan.Summary.PurgeActions)
+
+	for _, action := range plan.Actions {
+		if action.Type == ActionCreateDB {
+			assert.Equal(t, "1", action.Key)
+			assert.Equal(t, "item1", action.GDItem)
+		}
+	}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, mockClient, "", plan, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, executed)
+	assert.Equal(t, []string{"1"}, adapter.created)
+	assert.Equal(t, []string{"2"}, adapter.deletedGamedata)
+
+	// Adapters without Creator cannot apply create actions
+	_, err = ApplyPlan(context.Background(), &Spec{Adapter: &mockMutator{}}, nil, nil, "", plan, opts)
+	assert.Error(t, err)
+}
+
+// TestReconcileWithPlan_CreateGamedataActions tests that gamedata is created for items in DB and storage.
+func TestReconcileWithPlan_CreateGamedataActions(t *testing.T) {
+	adapter := &mockCreator{mockMutator: mockMutator{mockAdapter: mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "item1", "2": "item2"},
+		gdIndex:    map[string]GDItem{},
+		storageSet: map[string]struct{}{"1": {}},
+		mismatches: map[string][]string{},
+	}}}
+
+	spec := &Spec{Adapter: adapter}
+	opts := ReconcileOptions{DoCreateGamedata: true, Confirmed: true}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, plan.Summary.CreateActions)
+	assert.Len(t, plan.Actions, 1)
+	assert.Equal(t, ActionCreateGamedata, plan.Actions[0].Type)
+	assert.Equal(t, "item1", plan.Actions[0].DBItem)
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, mockClient, "", plan, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, []string{"1"}, adapter.createdGamedata)
+}
+
+// linkingAdapter rewrites a storage key during cache building.
+type linkingAdapter struct {
+	mockAdapter
+}
+
+func (m *linkingAdapter) LinkStorageKeys(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}) (map[string]struct{}, map[string]string) {
+	linked := maps.Clone(storageSet)
+	delete(linked, "chair")
+	linked["1"] = struct{}{}
+	return linked, map[string]string{"1": "chair"}
+}
+
+func (m *linkingAdapter) UseStorageLinks(links map[string]string) {}
+
+// TestBuildCache_StorageLinker tests that storage keys are linked after all indices are loaded,
+// and only for specs with LinkStorage set.
+func TestBuildCache_StorageLinker(t *testing.T) {
+	adapter := &linkingAdapter{mockAdapter{
+		dbIndex:    map[string]DBItem{"1": "item1"},
+		gdIndex:    map[string]GDItem{},
+		storageSet: map[string]struct{}{"chair": {}},
+	}}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	cache, err := BuildCache(context.Background(), &Spec{Adapter: adapter, LinkStorage: true}, nil, mockClient, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"1": {}}, cache.StorageSet)
+
+	// A plain reconcile matches storage as listed
+	spec := &Spec{Adapter: adapter}
+	cache, err = BuildCache(context.Background(), spec, nil, mockClient, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"chair": {}}, cache.StorageSet)
+
+	plan, err := ReconcileWithPlan(context.Background(), spec, nil, mockClient, "", ReconcileOptions{DoCreateGamedata: true})
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Actions)
+	assert.NotEqual(t, spec.CacheKey(), (&Spec{Adapter: adapter, LinkStorage: true}).CacheKey())
+}
+
+// sharingMutator implements StorageSharer: keys in shared use the same storage object.
+type sharingMutator struct {
+	mockMutator
+	shared map[string][]string
+}
+
+func (m *sharingMutator) SharedStorage(dbIndex map[string]DBItem, gdIndex map[string]GDItem, storageSet map[string]struct{}) map[string][]string {
+	return m.shared
+}
+
+// TestReconcileWithPlan_SharedStorage tests that storage objects still used by other
+// entities are not purged.
+func TestReconcileWithPlan_SharedStorage(t *testing.T) {
+	adapter := &sharingMutator{
+		mockMutator: mockMutator{mockAdapter: mockAdapter{
+			dbIndex:    map[string]DBItem{"1": "item1", "3": "item3"},
+			gdIndex:    map[string]GDItem{"1": "item1", "4": "item4"},
+			storageSet: map[string]struct{}{"1": {}, "3": {}, "4": {}, "orphan": {}},
+			mismatches: map[string][]string{},
+		}},
+		// "3" shares the object of "1", which stays; "4" only with "orphan", purged too
+		shared: map[string][]string{"3": {"1", "3"}, "4": {"4", "orphan"}, "orphan": {"4", "orphan"}},
+	}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	plan, err := ReconcileWithPlan(context.Background(), &Spec{Adapter: adapter}, nil, mockClient, "", ReconcileOptions{DoPurge: true})
+	assert.NoError(t, err)
+
+	var storageDeleted []string
+	for _, action := range plan.Actions {
+		if action.Type == ActionDeleteStorage {
+			storageDeleted = append(storageDeleted, action.Key)
+		}
+	}
+	assert.ElementsMatch(t, []string{"4", "orphan"}, storageDeleted)
+	assert.Equal(t, 1, plan.Summary.SharedStorageKept)
+	assert.Equal(t, len(plan.Actions), plan.Summary.PurgeActions)
+}
@@ -0,0 +1,118 @@
+package reconcile
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPlanNotFound is returned when a stored plan does not exist or was already applied.
+	ErrPlanNotFound = errors.New("reconcile plan not found")
+	// ErrPlanExpired is returned when a stored plan is older than its TTL.
+	ErrPlanExpired = errors.New("reconcile plan expired")
+	// ErrStalePlan is returned when the indices a plan was computed from have been rebuilt.
+	ErrStalePlan = errors.New("reconcile plan is stale: indices were rebuilt since planning")
+)
+
+// PlanConfig holds the settings of plans stored by the two-step plan/apply flow.
+type PlanConfig struct {
+	// TTLMinutes is how long a stored plan can be applied.
+	TTLMinutes int `mapstructure:"ttl_minutes" default:"15"`
+}
+
+// TTL returns the plan expiry as a duration.
+func (c PlanConfig) TTL() time.Duration {
+	return time.Duration(c.TTLMinutes) * time.Minute
+}
+
+// StoredPlan is a plan awaiting confirmation in the two-step plan/apply flow.
+type StoredPlan struct {
+	// ID identifies the plan in the apply request.
+	ID string
+	// Plan is the computed reconcile plan.
+	Plan *ReconcilePlan
+	// Options are the options the plan was computed with, reused when applying.
+	Options ReconcileOptions
+	// CreatedAt is the time the plan was stored.
+	CreatedAt time.Time
+	// ExpiresAt is the time after which the plan can no longer be applied.
+	ExpiresAt time.Time
+}
+
+// PlanStore keeps computed plans in memory until they are applied or expire.
+type PlanStore struct {
+	mu    sync.Mutex
+	plans map[string]*StoredPlan
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewPlanStore creates a plan store whose plans expire after ttl.
+func NewPlanStore(ttl time.Duration) *PlanStore {
+	return &PlanStore{
+		plans: make(map[string]*StoredPlan),
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// Save stores a plan and returns its entry. Expired plans are dropped.
+func (s *PlanStore) Save(plan *ReconcilePlan, opts ReconcileOptions) *StoredPlan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, stored := range s.plans {
+		if now.After(stored.ExpiresAt) {
+			delete(s.plans, id)
+		}
+	}
+
+	stored := &StoredPlan{
+		ID:        uuid.NewString(),
+		Plan:      plan,
+		Options:   opts,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	s.plans[stored.ID] = stored
+	return stored
+}
+
+// Get returns a plan without removing it, e.g. to check it before Take.
+// Returns ErrPlanNotFound or ErrPlanExpired.
+func (s *PlanStore) Get(id string) (*StoredPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.plans[id]
+	if !ok {
+		return nil, ErrPlanNotFound
+	}
+	if s.now().After(stored.ExpiresAt) {
+		delete(s.plans, id)
+		return nil, ErrPlanExpired
+	}
+	return stored, nil
+}
+
+// Take removes and returns a plan so it can be applied at most once.
+// Returns ErrPlanNotFound or ErrPlanExpired.
+func (s *PlanStore) Take(id string) (*StoredPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.plans[id]
+	if !ok {
+		return nil, ErrPlanNotFound
+	}
+	delete(s.plans, id)
+
+	if s.now().After(stored.ExpiresAt) {
+		return nil, ErrPlanExpired
+	}
+	return stored, nil
+}
@@ -0,0 +1,61 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanStore tests that stored plans can be taken once and expire.
+func TestPlanStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewPlanStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	plan := &ReconcilePlan{Summary: PlanSummary{TotalItems: 3}}
+	stored := store.Save(plan, ReconcileOptions{DoPurge: true})
+	assert.Equal(t, now.Add(time.Minute), stored.ExpiresAt)
+
+	got, err := store.Get(stored.ID)
+	require.NoError(t, err)
+	assert.Same(t, plan, got.Plan, "Get does not consume the plan")
+
+	taken, err := store.Take(stored.ID)
+	require.NoError(t, err)
+	assert.Same(t, plan, taken.Plan)
+	assert.True(t, taken.Options.DoPurge)
+
+	_, err = store.Take(stored.ID)
+	assert.ErrorIs(t, err, ErrPlanNotFound, "A plan can only be applied once")
+
+	expired := store.Save(plan, ReconcileOptions{})
+	now = now.Add(2 * time.Minute)
+	_, err = store.Get(expired.ID)
+	assert.ErrorIs(t, err, ErrPlanExpired)
+	_, err = store.Take(expired.ID)
+	assert.ErrorIs(t, err, ErrPlanNotFound, "Expired plans are dropped")
+}
+
+// TestCheckPlanFresh tests that plans are refused once their cache is rebuilt or invalidated.
+func TestCheckPlanFresh(t *testing.T) {
+	spec := &Spec{Adapter: &mockAdapter{}, ServerProfile: "fresh-test"}
+	built := time.Now()
+
+	globalCacheStore.mu.Lock()
+	globalCacheStore.caches[spec.CacheKey()] = &ReconcileCache{Built: built}
+	globalCacheStore.mu.Unlock()
+	defer InvalidateCache(spec)
+
+	plan := &ReconcilePlan{CacheBuilt: built}
+	assert.NoError(t, CheckPlanFresh(spec, plan))
+
+	globalCacheStore.mu.Lock()
+	globalCacheStore.caches[spec.CacheKey()] = &ReconcileCache{Built: built.Add(time.Second)}
+	globalCacheStore.mu.Unlock()
+	assert.ErrorIs(t, CheckPlanFresh(spec, plan), ErrStalePlan)
+
+	InvalidateCache(spec)
+	assert.ErrorIs(t, CheckPlanFresh(spec, plan), ErrStalePlan)
+}
@@ -0,0 +1,80 @@
+package reconcile
+
+import "context"
+
+// Phase identifies a stage of a reconcile run.
+type Phase string
+
+const (
+	// PhaseLoadDB is the loading of the database index.
+	PhaseLoadDB Phase = "load_db"
+	// PhaseLoadGamedata is the loading of the gamedata index.
+	PhaseLoadGamedata Phase = "load_gamedata"
+	// PhaseLoadStorage is the listing of the storage set.
+	PhaseLoadStorage Phase = "load_storage"
+	// PhaseApply is the execution of plan actions.
+	PhaseApply Phase = "apply"
+)
+
+// ProgressObserver receives progress updates during a reconcile run.
+// Total is zero when the amount of work is not known in advance (e.g. storage listing).
+// Index loads run concurrently, so observers must be safe for concurrent use.
+type ProgressObserver func(phase Phase, processed, total int)
+
+// progressKey is the context key under which the observer is stored.
+type progressKey struct{}
+
+// WithProgress returns a context carrying the observer, so adapters can report
+// progress from their Load methods without changing the Adapter interface.
+// The observer is chained after any observer already attached (e.g. by a caller
+// that does not own the Spec). A nil observer returns ctx unchanged.
+func WithProgress(ctx context.Context, observer ProgressObserver) context.Context {
+	if observer == nil {
+		return ctx
+	}
+	if parent, ok := ctx.Value(progressKey{}).(ProgressObserver); ok {
+		next := observer
+		observer = func(phase Phase, processed, total int) {
+			parent(phase, processed, total)
+			next(phase, processed, total)
+		}
+	}
+	return context.WithValue(ctx, progressKey{}, observer)
+}
+
+// ReportProgress notifies the observer carried by ctx, if any.
+func ReportProgress(ctx context.Context, phase Phase, processed, total int) {
+	if observer, ok := ctx.Value(progressKey{}).(ProgressObserver); ok {
+		observer(phase, processed, total)
+	}
+}
+
+// ActionObserver receives the outcome of each action executed by ApplyPlan.
+// Actions executed as a batch are reported individually with the batch error.
+type ActionObserver func(action Action, err error)
+
+// actionKey is the context key under which the action observer is stored.
+type actionKey struct{}
+
+// WithActionObserver returns a context carrying the action observer, chained after
+// any observer already attached. A nil observer returns ctx unchanged.
+func WithActionObserver(ctx context.Context, observer ActionObserver) context.Context {
+	if observer == nil {
+		return ctx
+	}
+	if parent, ok := ctx.Value(actionKey{}).(ActionObserver); ok {
+		next := observer
+		observer = func(action Action, err error) {
+			parent(action, err)
+			next(action, err)
+		}
+	}
+	return context.WithValue(ctx, actionKey{}, observer)
+}
+
+// ReportAction notifies the action observer carried by ctx, if any.
+func ReportAction(ctx context.Context, action Action, err error) {
+	if observer, ok := ctx.Value(actionKey{}).(ActionObserver); ok {
+		observer(action, err)
+	}
+}
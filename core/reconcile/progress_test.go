@@ -0,0 +1,105 @@
+package reconcile
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// progressRecorder collects progress reports keyed by phase.
+type progressRecorder struct {
+	mu      sync.Mutex
+	reports map[Phase][][2]int
+}
+
+func (r *progressRecorder) observe(phase Phase, processed, total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reports == nil {
+		r.reports = make(map[Phase][][2]int)
+	}
+	r.reports[phase] = append(r.reports[phase], [2]int{processed, total})
+}
+
+// TestBuildCache_ReportsProgress tests that index loads report completion per phase.
+func TestBuildCache_ReportsProgress(t *testing.T) {
+	recorder := &progressRecorder{}
+	spec := &Spec{
+		Adapter: &mockAdapter{
+			dbIndex:    map[string]DBItem{"A": "A", "B": "B"},
+			gdIndex:    map[string]GDItem{"A": "A"},
+			storageSet: map[string]struct{}{"A": {}, "B": {}, "C": {}},
+		},
+		Progress: recorder.observe,
+	}
+
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := BuildCache(context.Background(), spec, nil, mockClient, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, [][2]int{{2, 2}}, recorder.reports[PhaseLoadDB])
+	assert.Equal(t, [][2]int{{1, 1}}, recorder.reports[PhaseLoadGamedata])
+	assert.Equal(t, [][2]int{{3, 3}}, recorder.reports[PhaseLoadStorage])
+}
+
+// TestApplyPlan_ReportsProgress tests that ApplyPlan reports executed actions against the plan size.
+func TestApplyPlan_ReportsProgress(t *testing.T) {
+	recorder := &progressRecorder{}
+	spec := &Spec{Adapter: &mockMutator{}, Progress: recorder.observe}
+
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1"},
+			{Type: ActionDeleteDB, Key: "2"},
+			{Type: ActionDeleteStorage, Key: "3"},
+		},
+	}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, executed)
+	assert.Equal(t, [][2]int{{0, 3}, {1, 3}, {2, 3}, {3, 3}}, recorder.reports[PhaseApply])
+}
+
+// TestReportProgress_ContextObserver tests that an observer attached to the context is kept
+// when the spec has none.
+func TestReportProgress_ContextObserver(t *testing.T) {
+	recorder := &progressRecorder{}
+	ctx := WithProgress(context.Background(), recorder.observe)
+	ctx = WithProgress(ctx, nil)
+
+	ReportProgress(ctx, PhaseLoadStorage, 5, 0)
+	ReportProgress(context.Background(), PhaseLoadStorage, 6, 0)
+
+	assert.Equal(t, [][2]int{{5, 0}}, recorder.reports[PhaseLoadStorage])
+}
+
+// TestApplyPlan_ReportsActions tests that every executed action is reported, including batch failures.
+func TestApplyPlan_ReportsActions(t *testing.T) {
+	var reported []string
+	spec := &Spec{
+		Adapter: &mockMutator{},
+		OnAction: func(action Action, err error) {
+			reported = append(reported, string(action.Type)+":"+action.Key)
+		},
+	}
+
+	plan := &ReconcilePlan{
+		Actions: []Action{
+			{Type: ActionDeleteDB, Key: "1"},
+			{Type: ActionDeleteStorage, Key: "2"},
+			{Type: ActionSyncDB, Key: "3"},
+		},
+	}
+
+	_, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, ReconcileOptions{Confirmed: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"delete_db:1", "delete_storage:2", "sync_db:3"}, reported)
+}
@@ -0,0 +1,217 @@
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// CacheStats describes a cached index set and its builds, for monitoring.
+type CacheStats struct {
+	// Key is the cache key of the spec (see Spec.CacheKey).
+	Key string `json:"key"`
+
+	// Cached reports whether a cache is currently held for the key.
+	Cached bool `json:"cached"`
+
+	// Built is when the current cache was built. Zero if none is held.
+	Built time.Time `json:"built,omitempty"`
+
+	// AgeSeconds is the age of the current cache.
+	AgeSeconds float64 `json:"age_seconds"`
+
+	// TTLSeconds is the time-to-live of the current cache.
+	TTLSeconds float64 `json:"ttl_seconds"`
+
+	// Expired reports whether the current cache is past its TTL.
+	Expired bool `json:"expired"`
+
+	// Refreshing reports whether a background rebuild is running.
+	Refreshing bool `json:"refreshing"`
+
+	// Builds is the number of completed builds, successful or not.
+	Builds int `json:"builds"`
+
+	// BuildErrors is the number of failed builds.
+	BuildErrors int `json:"build_errors"`
+
+	// StaleServed is the number of requests served an expired cache during a rebuild.
+	StaleServed int `json:"stale_served"`
+
+	// LastBuildSeconds is the duration of the last build.
+	LastBuildSeconds float64 `json:"last_build_seconds"`
+
+	// LastError is the error of the last build, if it failed.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// cacheMetrics accumulates the build metrics of a cache key.
+type cacheMetrics struct {
+	builds      int
+	buildErrors int
+	staleServed int
+	lastBuild   time.Duration
+	lastError   string
+	refreshing  bool
+}
+
+// metricsFor returns the metrics of a cache key. The caller must hold s.mu for writing.
+func (s *cacheStore) metricsFor(cacheKey string) *cacheMetrics {
+	m, ok := s.metrics[cacheKey]
+	if !ok {
+		m = &cacheMetrics{}
+		s.metrics[cacheKey] = m
+	}
+	return m
+}
+
+// recordBuild records the outcome of a build.
+func (s *cacheStore) recordBuild(cacheKey string, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.metricsFor(cacheKey)
+	m.builds++
+	m.lastBuild = duration
+	m.lastError = ""
+	if err != nil {
+		m.buildErrors++
+		m.lastError = err.Error()
+	}
+}
+
+// revalidate serves a stale cache: it counts the stale hit and starts a background
+// rebuild unless one is already running. The rebuild outlives the request context.
+func (s *cacheStore) revalidate(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) {
+	cacheKey := spec.CacheKey()
+
+	s.mu.Lock()
+	m := s.metricsFor(cacheKey)
+	m.staleServed++
+	running := m.refreshing
+	m.refreshing = true
+	s.mu.Unlock()
+
+	if running {
+		return
+	}
+
+	go func() {
+		defer s.setRefreshing(cacheKey, false)
+		// Joins a rebuild already in flight for the key, if any
+		_, _, _ = s.sf.Do(cacheKey, func() (any, error) {
+			return s.rebuild(context.WithoutCancel(ctx), spec, db, client, bucket)
+		})
+	}()
+}
+
+// setRefreshing flags whether a background rebuild of the key is running.
+func (s *cacheStore) setRefreshing(cacheKey string, refreshing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metricsFor(cacheKey).refreshing = refreshing
+}
+
+// RefreshCache rebuilds and stores the cache of a spec, even if it is still fresh.
+// Concurrent builds of the same spec are shared.
+func RefreshCache(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
+	cacheKey := spec.CacheKey()
+
+	globalCacheStore.setRefreshing(cacheKey, true)
+	defer globalCacheStore.setRefreshing(cacheKey, false)
+
+	result, err, _ := globalCacheStore.sf.Do(cacheKey, func() (any, error) {
+		return globalCacheStore.rebuild(ctx, spec, db, client, bucket)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ReconcileCache), nil
+}
+
+// StartRefresher rebuilds the cache of a spec right away and then every
+// spec.RefreshInterval in the background, so requests rarely find it expired.
+// Build errors are passed to onError, if set. It stops when ctx is cancelled and
+// does nothing if RefreshInterval is not set.
+func StartRefresher(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, onError func(error)) {
+	if spec.RefreshInterval <= 0 {
+		return
+	}
+
+	refresh := func() {
+		if _, err := RefreshCache(ctx, spec, db, client, bucket); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	go func() {
+		refresh()
+
+		ticker := time.NewTicker(spec.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+}
+
+// Stats returns the cache metrics of a spec.
+func Stats(spec *Spec) CacheStats {
+	globalCacheStore.mu.RLock()
+	defer globalCacheStore.mu.RUnlock()
+	return globalCacheStore.stats(spec.CacheKey())
+}
+
+// AllStats returns the cache metrics of every cache key built or held, sorted by key.
+func AllStats() []CacheStats {
+	globalCacheStore.mu.RLock()
+	defer globalCacheStore.mu.RUnlock()
+
+	keys := make(map[string]struct{}, len(globalCacheStore.metrics))
+	for key := range globalCacheStore.metrics {
+		keys[key] = struct{}{}
+	}
+	for key := range globalCacheStore.caches {
+		keys[key] = struct{}{}
+	}
+
+	stats := make([]CacheStats, 0, len(keys))
+	for key := range keys {
+		stats = append(stats, globalCacheStore.stats(key))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+// stats builds the metrics of a cache key. The caller must hold s.mu.
+func (s *cacheStore) stats(cacheKey string) CacheStats {
+	stats := CacheStats{Key: cacheKey}
+
+	if cache, ok := s.caches[cacheKey]; ok {
+		stats.Cached = true
+		stats.Built = cache.Built
+		stats.AgeSeconds = time.Since(cache.Built).Seconds()
+		stats.TTLSeconds = cache.TTL.Seconds()
+		stats.Expired = cache.IsExpired()
+	}
+
+	if m, ok := s.metrics[cacheKey]; ok {
+		stats.Refreshing = m.refreshing
+		stats.Builds = m.builds
+		stats.BuildErrors = m.buildErrors
+		stats.StaleServed = m.staleServed
+		stats.LastBuildSeconds = m.lastBuild.Seconds()
+		stats.LastError = m.lastError
+	}
+	return stats
+}
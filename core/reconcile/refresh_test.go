@@ -0,0 +1,160 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// countingAdapter returns a mock adapter counting DB loads. Loads block while gate is
+// set and fail while failing is set.
+func countingAdapter(loads *atomic.Int32, gate *atomic.Pointer[chan struct{}], failing *atomic.Bool) *mockAdapter {
+	return &mockAdapter{
+		dbLoadFunc: func(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]DBItem, error) {
+			if gate != nil {
+				if ch := gate.Load(); ch != nil {
+					<-*ch
+				}
+			}
+			loads.Add(1)
+			if failing != nil && failing.Load() {
+				return nil, errors.New("db down")
+			}
+			return map[string]DBItem{"A": "A"}, nil
+		},
+	}
+}
+
+// TestGetOrBuildCache_StaleWhileRevalidate tests that an expired cache is served while it
+// is rebuilt in the background, and that caches past MaxStale are rebuilt on the request.
+func TestGetOrBuildCache_StaleWhileRevalidate(t *testing.T) {
+	var loads atomic.Int32
+	var gate atomic.Pointer[chan struct{}]
+	adapter := countingAdapter(&loads, &gate, nil)
+
+	spec := &Spec{
+		Adapter:       adapter,
+		CacheTTL:      20 * time.Millisecond,
+		MaxStale:      time.Hour,
+		StoragePrefix: "swr/",
+	}
+	resetStats(t, spec)
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	first, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), loads.Load())
+
+	// Expired: the rebuild blocks, yet requests get the stale cache right away
+	time.Sleep(30 * time.Millisecond)
+	release := make(chan struct{})
+	gate.Store(&release)
+
+	for range 3 {
+		cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+		require.NoError(t, err)
+		assert.Same(t, first, cache)
+	}
+
+	stats := Stats(spec)
+	assert.True(t, stats.Refreshing)
+	assert.True(t, stats.Expired)
+	assert.Equal(t, 3, stats.StaleServed)
+
+	gate.Store(nil)
+	close(release)
+
+	// A single background rebuild replaces the cache
+	assert.Eventually(t, func() bool {
+		cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+		return err == nil && cache != first
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), loads.Load())
+
+	stats = Stats(spec)
+	assert.Equal(t, 2, stats.Builds)
+	assert.False(t, stats.Refreshing)
+
+	// Past MaxStale the request waits for the rebuild
+	spec.MaxStale = time.Millisecond
+	time.Sleep(30 * time.Millisecond)
+	cache, err := GetOrBuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), loads.Load())
+	assert.False(t, cache.IsExpired())
+}
+
+// TestStartRefresher tests that the refresher rebuilds the cache on its interval and
+// records build errors.
+func TestStartRefresher(t *testing.T) {
+	var loads atomic.Int32
+	var failing atomic.Bool
+	adapter := countingAdapter(&loads, nil, &failing)
+
+	spec := &Spec{
+		Adapter:         adapter,
+		CacheTTL:        time.Hour,
+		RefreshInterval: 10 * time.Millisecond,
+		StoragePrefix:   "refresh/",
+	}
+	resetStats(t, spec)
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	errs := make(chan error, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	StartRefresher(ctx, spec, nil, client, "", func(err error) { errs <- err })
+
+	// Builds are recorded once stored, after their indices were loaded
+	assert.Eventually(t, func() bool { return Stats(spec).Builds >= 3 }, time.Second, 5*time.Millisecond)
+	stats := Stats(spec)
+	assert.True(t, stats.Cached)
+	assert.GreaterOrEqual(t, int(loads.Load()), 3)
+	assert.Zero(t, stats.BuildErrors)
+
+	// Failed rebuilds keep the previous cache and are reported
+	failing.Store(true)
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "db down")
+	case <-time.After(time.Second):
+		t.Fatal("refresh error not reported")
+	}
+	stats = Stats(spec)
+	assert.True(t, stats.Cached)
+	assert.Positive(t, stats.BuildErrors)
+	assert.Equal(t, "db down", stats.LastError)
+
+	// Cancelling stops the refresher
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	stopped := loads.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, loads.Load())
+}
+
+// resetStats clears the cache and metrics of a spec before and after the test.
+func resetStats(t *testing.T, spec *Spec) {
+	reset := func() {
+		InvalidateCache(spec)
+		globalCacheStore.mu.Lock()
+		delete(globalCacheStore.metrics, spec.CacheKey())
+		globalCacheStore.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
@@ -0,0 +1,70 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSafetyThreshold is returned by ApplyPlan when a plan exceeds the configured guard rails.
+var ErrSafetyThreshold = errors.New("reconcile safety threshold exceeded")
+
+// Config holds guard rails for destructive reconcile runs.
+// A zero value disables every guard.
+type Config struct {
+	// MaxDeletes is the maximum number of entities that may be purged in a single run.
+	// If zero, there is no absolute limit.
+	MaxDeletes int `mapstructure:"max_deletes" default:"0"`
+
+	// MaxDeletePercent is the maximum percentage (0-100) of all entities that may be
+	// purged in a single run. If zero, there is no percentage limit.
+	MaxDeletePercent float64 `mapstructure:"max_delete_percent" default:"10"`
+
+	// RefuseEmptySource refuses to purge when a whole source (database, gamedata or
+	// storage) is empty, which usually means a misconfigured bucket or emulator.
+	RefuseEmptySource bool `mapstructure:"refuse_empty_source" default:"true"`
+}
+
+// CheckSafety evaluates a plan against the guard rails and returns the reason it
+// should be blocked, or an empty string if it is within limits.
+// Only purge actions are considered; sync actions never delete entities.
+func CheckSafety(plan *ReconcilePlan, cfg Config) string {
+	deleted := make(map[string]struct{})
+	for _, action := range plan.Actions {
+		switch action.Type {
+		case ActionDeleteDB, ActionDeleteGamedata, ActionDeleteStorage:
+			deleted[action.Key] = struct{}{}
+		}
+	}
+
+	if len(deleted) == 0 {
+		return ""
+	}
+
+	s := plan.Summary
+
+	if cfg.RefuseEmptySource && s.TotalItems > 0 {
+		// Every entity is in the union of the sources, so a source is empty
+		// exactly when every entity is missing from it.
+		switch s.TotalItems {
+		case s.MissingDB:
+			return "database source is empty"
+		case s.MissingGamedata:
+			return "gamedata source is empty"
+		case s.MissingStorage:
+			return "storage source is empty"
+		}
+	}
+
+	if cfg.MaxDeletes > 0 && len(deleted) > cfg.MaxDeletes {
+		return fmt.Sprintf("%d entities would be purged, limit is %d", len(deleted), cfg.MaxDeletes)
+	}
+
+	if cfg.MaxDeletePercent > 0 && s.TotalItems > 0 {
+		percent := float64(len(deleted)) * 100 / float64(s.TotalItems)
+		if percent > cfg.MaxDeletePercent {
+			return fmt.Sprintf("%.1f%% of entities would be purged, limit is %.1f%%", percent, cfg.MaxDeletePercent)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// purgePlan builds a plan purging the given keys from every store.
+func purgePlan(summary PlanSummary, keys ...string) *ReconcilePlan {
+	plan := &ReconcilePlan{Summary: summary}
+	for _, key := range keys {
+		plan.Actions = append(plan.Actions,
+			Action{Type: ActionDeleteDB, Key: key},
+			Action{Type: ActionDeleteGamedata, Key: key},
+		)
+	}
+	return plan
+}
+
+// TestCheckSafety tests each guard rail and that entities are counted once across stores.
+func TestCheckSafety(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    *ReconcilePlan
+		cfg     Config
+		blocked string
+	}{
+		{
+			name: "Disabled guards",
+			plan: purgePlan(PlanSummary{TotalItems: 2, MissingStorage: 2}, "1", "2"),
+			cfg:  Config{},
+		},
+		{
+			name:    "Empty source",
+			plan:    purgePlan(PlanSummary{TotalItems: 2, MissingStorage: 2}, "1", "2"),
+			cfg:     Config{RefuseEmptySource: true},
+			blocked: "storage source is empty",
+		},
+		{
+			name:    "Absolute limit",
+			plan:    purgePlan(PlanSummary{TotalItems: 100, MissingStorage: 3}, "1", "2", "3"),
+			cfg:     Config{MaxDeletes: 2},
+			blocked: "3 entities would be purged, limit is 2",
+		},
+		{
+			name: "Within absolute limit",
+			plan: purgePlan(PlanSummary{TotalItems: 100, MissingStorage: 2}, "1", "2"),
+			cfg:  Config{MaxDeletes: 2},
+		},
+		{
+			name:    "Percentage limit",
+			plan:    purgePlan(PlanSummary{TotalItems: 10, MissingStorage: 2}, "1", "2"),
+			cfg:     Config{MaxDeletePercent: 10},
+			blocked: "20.0% of entities would be purged, limit is 10.0%",
+		},
+		{
+			name: "Sync only",
+			plan: &ReconcilePlan{
+				Summary: PlanSummary{TotalItems: 1, MissingStorage: 1},
+				Actions: []Action{{Type: ActionSyncDB, Key: "1"}},
+			},
+			cfg: Config{MaxDeletes: 1, MaxDeletePercent: 1, RefuseEmptySource: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, CheckSafety(tt.plan, tt.cfg))
+		})
+	}
+}
+
+// TestApplyPlan_SafetyThreshold tests that ApplyPlan refuses blocked plans unless forced.
+func TestApplyPlan_SafetyThreshold(t *testing.T) {
+	adapter := &mockMutator{}
+	spec := &Spec{Adapter: adapter}
+
+	plan := purgePlan(PlanSummary{TotalItems: 4}, "1", "2", "3")
+	opts := ReconcileOptions{Confirmed: true, Safety: Config{MaxDeletePercent: 50}}
+
+	executed, err := ApplyPlan(context.Background(), spec, nil, nil, "", plan, opts)
+	assert.True(t, errors.Is(err, ErrSafetyThreshold))
+	assert.Equal(t, 0, executed)
+	assert.Empty(t, adapter.deletedDB)
+	assert.Equal(t, "75.0% of entities would be purged, limit is 50.0%", plan.Summary.BlockedReason)
+
+	opts.Force = true
+	executed, err = ApplyPlan(context.Background(), spec, nil, nil, "", plan, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, executed)
+	assert.True(t, plan.Summary.Forced)
+}
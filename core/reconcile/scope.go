@@ -0,0 +1,119 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source identifies one of the three sources of truth.
+type Source string
+
+const (
+	// SourceDB is the database.
+	SourceDB Source = "db"
+	// SourceGamedata is the gamedata JSON.
+	SourceGamedata Source = "gamedata"
+	// SourceStorage is the storage bucket.
+	SourceStorage Source = "storage"
+)
+
+// ParseSources parses source names (e.g. from --purge-from=storage,db).
+// Returns an error for unknown names.
+func ParseSources(names []string) ([]Source, error) {
+	sources := make([]Source, 0, len(names))
+	for _, name := range names {
+		source := Source(strings.ToLower(strings.TrimSpace(name)))
+		switch source {
+		case SourceDB, SourceGamedata, SourceStorage:
+			sources = append(sources, source)
+		default:
+			return nil, fmt.Errorf("unknown source %q (expected db, gamedata or storage)", name)
+		}
+	}
+	return sources, nil
+}
+
+// Presence describes in which sources an entity exists.
+type Presence struct {
+	// DB indicates presence in the database.
+	DB bool
+	// Gamedata indicates presence in gamedata.
+	Gamedata bool
+	// Storage indicates presence in storage.
+	Storage bool
+}
+
+// ParsePresence parses a presence pattern listing the sources an entity exists in,
+// joined by "+". For example "storage" matches entities in storage only, and
+// "db+gamedata" matches entities missing only in storage.
+func ParsePresence(pattern string) (Presence, error) {
+	var p Presence
+
+	sources, err := ParseSources(strings.Split(pattern, "+"))
+	if err != nil {
+		return p, fmt.Errorf("invalid presence pattern %q: %w", pattern, err)
+	}
+
+	for _, source := range sources {
+		switch source {
+		case SourceDB:
+			p.DB = true
+		case SourceGamedata:
+			p.Gamedata = true
+		case SourceStorage:
+			p.Storage = true
+		}
+	}
+
+	return p, nil
+}
+
+// String returns the pattern form of the presence (e.g. "db+storage").
+func (p Presence) String() string {
+	var parts []string
+	if p.DB {
+		parts = append(parts, string(SourceDB))
+	}
+	if p.Gamedata {
+		parts = append(parts, string(SourceGamedata))
+	}
+	if p.Storage {
+		parts = append(parts, string(SourceStorage))
+	}
+	return strings.Join(parts, "+")
+}
+
+// Matches reports whether the result exists in exactly the sources of the pattern.
+func (p Presence) Matches(result ReconcileResult) bool {
+	return p.DB == result.DBPresent &&
+		p.Gamedata == result.GamedataPresent &&
+		p.Storage == result.StoragePresent
+}
+
+// purgeScope reports whether purge actions may target the source.
+// An empty scope allows every source.
+func purgeScope(opts ReconcileOptions, source Source) bool {
+	if len(opts.PurgeFrom) == 0 {
+		return true
+	}
+	for _, s := range opts.PurgeFrom {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// purgePresence reports whether the result matches one of the presence filters.
+// An empty filter matches every result.
+func purgePresence(opts ReconcileOptions, result ReconcileResult) bool {
+	if len(opts.PurgePresence) == 0 {
+		return true
+	}
+	for _, p := range opts.PurgePresence {
+		if p.Matches(result) {
+			return true
+		}
+	}
+	return false
+}
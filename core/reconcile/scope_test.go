@@ -0,0 +1,74 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParsePresence tests parsing of presence patterns.
+func TestParsePresence(t *testing.T) {
+	p, err := ParsePresence("storage")
+	assert.NoError(t, err)
+	assert.Equal(t, Presence{Storage: true}, p)
+
+	p, err = ParsePresence("DB+gamedata")
+	assert.NoError(t, err)
+	assert.Equal(t, Presence{DB: true, Gamedata: true}, p)
+	assert.Equal(t, "db+gamedata", p.String())
+
+	_, err = ParsePresence("db+bundle")
+	assert.Error(t, err)
+}
+
+// TestBuildPlan_PurgeScopes tests that purge scopes and presence filters restrict planned deletions.
+func TestBuildPlan_PurgeScopes(t *testing.T) {
+	results := []ReconcileResult{
+		{ID: "1", StoragePresent: true},                                            // Orphaned storage file
+		{ID: "2", DBPresent: true, GamedataPresent: true},                          // DB row without bundle
+		{ID: "3", DBPresent: true, StoragePresent: true, Mismatch: []string{"x"}},  // Missing gamedata
+		{ID: "4", DBPresent: true, GamedataPresent: true, Mismatch: []string{"x"}}, // Missing bundle, mismatched
+	}
+	cache := &ReconcileCache{DBIndex: map[string]DBItem{}, GDIndex: map[string]GDItem{}}
+
+	keysOf := func(actions []Action) []string {
+		var keys []string
+		for _, a := range actions {
+			keys = append(keys, string(a.Type)+":"+a.Key)
+		}
+		return keys
+	}
+
+	t.Run("Storage only", func(t *testing.T) {
+		opts := ReconcileOptions{DoPurge: true, PurgeFrom: []Source{SourceStorage}}
+		summary, actions := buildPlanFromResults(results, cache, &mockAdapter{}, opts)
+		assert.Equal(t, []string{"delete_storage:1", "delete_storage:3"}, keysOf(actions))
+		assert.Equal(t, 2, summary.PurgeActions)
+	})
+
+	t.Run("Presence filter", func(t *testing.T) {
+		opts := ReconcileOptions{DoPurge: true, PurgePresence: []Presence{{Storage: true}}}
+		_, actions := buildPlanFromResults(results, cache, &mockAdapter{}, opts)
+		assert.Equal(t, []string{"delete_storage:1"}, keysOf(actions))
+	})
+
+	t.Run("DB rows without bundle", func(t *testing.T) {
+		opts := ReconcileOptions{
+			DoPurge:       true,
+			PurgeFrom:     []Source{SourceDB},
+			PurgePresence: []Presence{{DB: true, Gamedata: true}},
+		}
+		_, actions := buildPlanFromResults(results, cache, &mockAdapter{}, opts)
+		assert.Equal(t, []string{"delete_db:2", "delete_db:4"}, keysOf(actions))
+	})
+
+	t.Run("Sync when purge is out of scope", func(t *testing.T) {
+		opts := ReconcileOptions{DoPurge: true, DoSync: true, PurgeFrom: []Source{SourceGamedata}}
+		_, actions := buildPlanFromResults(results, cache, &mockAdapter{}, opts)
+		assert.Equal(t, []string{"delete_gamedata:2", "delete_gamedata:4"}, keysOf(actions))
+
+		opts.PurgePresence = []Presence{{Storage: true}}
+		_, actions = buildPlanFromResults(results, cache, &mockAdapter{}, opts)
+		assert.Equal(t, []string{"sync_db:4"}, keysOf(actions), "Items not purged are still synced")
+	})
+}
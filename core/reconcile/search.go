@@ -0,0 +1,316 @@
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"asset-manager/core/storage"
+
+	"gorm.io/gorm"
+)
+
+// MatchType describes how a search query matched an entity, from best to worst.
+type MatchType string
+
+const (
+	// MatchExact means the query equals the entity key or one of its terms.
+	MatchExact MatchType = "exact"
+	// MatchPrefix means a term starts with the query.
+	MatchPrefix MatchType = "prefix"
+	// MatchSubstring means a term contains the query.
+	MatchSubstring MatchType = "substring"
+	// MatchFuzzy means a term, or a word of it, is within a small edit distance of the query.
+	MatchFuzzy MatchType = "fuzzy"
+)
+
+// rank orders match types, lower is better.
+func (m MatchType) rank() int {
+	switch m {
+	case MatchExact:
+		return 0
+	case MatchPrefix:
+		return 1
+	case MatchSubstring:
+		return 2
+	}
+	return 3
+}
+
+// SearchHit is a reconcile result found by a search query.
+type SearchHit struct {
+	ReconcileResult
+
+	// Matched is the key or term the query matched.
+	Matched string `json:"matched"`
+
+	// Match is how the query matched.
+	Match MatchType `json:"match"`
+
+	// Distance is the edit distance of fuzzy matches, 0 otherwise.
+	Distance int `json:"distance"`
+}
+
+// searchEntry holds the searchable terms of one entity.
+type searchEntry struct {
+	key   string
+	terms []string // lowercase
+	words [][]string
+}
+
+// SearchIndex finds entities of a cache by key and by the terms returned by a
+// Searchable adapter (ResolveName otherwise).
+type SearchIndex struct {
+	entries []searchEntry
+	// exact maps lowercase terms to the sorted keys of the entities having them
+	exact map[string][]string
+}
+
+// NewSearchIndex indexes the DB and gamedata entities of the cache.
+// Storage-only entities have no terms and can only be found by key.
+func NewSearchIndex(cache *ReconcileCache, adapter Adapter) *SearchIndex {
+	searchable, _ := adapter.(Searchable)
+
+	keys := make(map[string]struct{}, len(cache.DBIndex)+len(cache.GDIndex))
+	for key := range cache.DBIndex {
+		keys[key] = struct{}{}
+	}
+	for key := range cache.GDIndex {
+		keys[key] = struct{}{}
+	}
+	for key := range cache.StorageSet {
+		keys[key] = struct{}{}
+	}
+
+	idx := &SearchIndex{
+		entries: make([]searchEntry, 0, len(keys)),
+		exact:   make(map[string][]string),
+	}
+
+	for key := range keys {
+		var dbItem DBItem
+		var gdItem GDItem
+		if item, ok := cache.DBIndex[key]; ok {
+			dbItem = item
+		}
+		if item, ok := cache.GDIndex[key]; ok {
+			gdItem = item
+		}
+
+		var raw []string
+		if dbItem != nil || gdItem != nil {
+			if searchable != nil {
+				raw = searchable.SearchTerms(dbItem, gdItem)
+			} else {
+				raw = []string{adapter.ResolveName(dbItem, gdItem)}
+			}
+		}
+
+		entry := searchEntry{key: key}
+		seen := make(map[string]struct{}, len(raw))
+		for _, term := range raw {
+			term = strings.ToLower(strings.TrimSpace(term))
+			if term == "" {
+				continue
+			}
+			if _, dup := seen[term]; dup {
+				continue
+			}
+			seen[term] = struct{}{}
+			entry.terms = append(entry.terms, term)
+			entry.words = append(entry.words, splitWords(term))
+			idx.exact[term] = append(idx.exact[term], key)
+		}
+		idx.entries = append(idx.entries, entry)
+	}
+
+	for term := range idx.exact {
+		sort.Slice(idx.exact[term], func(i, j int) bool {
+			return compareIDs(idx.exact[term][i], idx.exact[term][j]) < 0
+		})
+	}
+
+	return idx
+}
+
+// Lookup returns the key of the entity having the term (case-insensitive), or an
+// empty string. If several entities share the term, the lowest key is returned.
+func (idx *SearchIndex) Lookup(term string) string {
+	keys := idx.exact[strings.ToLower(strings.TrimSpace(term))]
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// searchMatch is the best match of a query against one entity.
+type searchMatch struct {
+	key      string
+	matched  string
+	match    MatchType
+	distance int
+}
+
+// better reports whether m ranks before o.
+func (m searchMatch) better(o searchMatch) bool {
+	if m.match.rank() != o.match.rank() {
+		return m.match.rank() < o.match.rank()
+	}
+	if m.distance != o.distance {
+		return m.distance < o.distance
+	}
+	if len(m.matched) != len(o.matched) {
+		return len(m.matched) < len(o.matched)
+	}
+	return compareIDs(m.key, o.key) < 0
+}
+
+// search returns the best matches of the query, best first.
+// If limit is positive, at most limit matches are returned.
+func (idx *SearchIndex) search(query string, limit int) []searchMatch {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil
+	}
+	maxDistance := fuzzyDistance(q)
+
+	var matches []searchMatch
+	for _, entry := range idx.entries {
+		best := searchMatch{key: entry.key}
+		found := false
+
+		consider := func(m searchMatch) {
+			if !found || m.better(best) {
+				best = m
+				found = true
+			}
+		}
+
+		if entry.key == q {
+			consider(searchMatch{key: entry.key, matched: entry.key, match: MatchExact})
+		}
+
+		for i, term := range entry.terms {
+			switch {
+			case term == q:
+				consider(searchMatch{key: entry.key, matched: term, match: MatchExact})
+			case strings.HasPrefix(term, q):
+				consider(searchMatch{key: entry.key, matched: term, match: MatchPrefix})
+			case strings.Contains(term, q):
+				consider(searchMatch{key: entry.key, matched: term, match: MatchSubstring})
+			case maxDistance > 0:
+				d := editDistance(q, term, maxDistance)
+				for _, word := range entry.words[i] {
+					d = min(d, editDistance(q, word, maxDistance))
+				}
+				if d <= maxDistance {
+					consider(searchMatch{key: entry.key, matched: term, match: MatchFuzzy, distance: d})
+				}
+			}
+		}
+
+		if found {
+			matches = append(matches, best)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].better(matches[j]) })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Search finds entities whose key or terms match the query by prefix, substring or
+// edit distance, best first. If limit is positive, at most limit hits are returned.
+func (c *ReconcileCache) Search(adapter Adapter, query string, limit int) []SearchHit {
+	matches := c.SearchIndex(adapter).search(query, limit)
+
+	hits := make([]SearchHit, 0, len(matches))
+	for _, m := range matches {
+		hits = append(hits, SearchHit{
+			ReconcileResult: buildResult(m.key, c.DBIndex, c.GDIndex, c.StorageSet, adapter),
+			Matched:         m.matched,
+			Match:           m.match,
+			Distance:        m.distance,
+		})
+	}
+	return hits
+}
+
+// SearchIndex returns the search index of the cache, building it on first use.
+func (c *ReconcileCache) SearchIndex(adapter Adapter) *SearchIndex {
+	c.searchOnce.Do(func() {
+		c.search = NewSearchIndex(c, adapter)
+	})
+	return c.search
+}
+
+// SearchResults searches the cached indices of the spec, building them if needed.
+func SearchResults(ctx context.Context, spec *Spec, db *gorm.DB, client storage.Client, bucket string, query string, limit int) ([]SearchHit, error) {
+	cache, err := GetOrBuildCache(ctx, spec, db, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return cache.Search(spec.Adapter, query, limit), nil
+}
+
+// fuzzyDistance returns the maximum edit distance tolerated for the query.
+// Short queries only match by prefix or substring.
+func fuzzyDistance(q string) int {
+	switch n := len([]rune(q)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// splitWords splits a term on anything that is not a letter or digit
+// (e.g. "chair_polyfon*2" into "chair", "polyfon" and "2").
+func splitWords(term string) []string {
+	return strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance returns the optimal string alignment distance between a and b
+// (insertions, deletions, substitutions and adjacent transpositions).
+// Returns limit+1 as soon as the distance is known to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return min(prev[len(rb)], limit+1)
+}
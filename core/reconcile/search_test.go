@@ -0,0 +1,127 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// searchAdapter is a mock adapter whose items are their search terms.
+type searchAdapter struct {
+	mockAdapter
+}
+
+func (a *searchAdapter) SearchTerms(dbItem DBItem, gdItem GDItem) []string {
+	var terms []string
+	if dbItem != nil {
+		terms = append(terms, dbItem.(string))
+	}
+	if gdItem != nil {
+		terms = append(terms, gdItem.(string))
+	}
+	return terms
+}
+
+func searchCache() (*ReconcileCache, Adapter) {
+	adapter := &searchAdapter{mockAdapter{mismatches: map[string][]string{}}}
+	cache := &ReconcileCache{
+		DBIndex: map[string]DBItem{
+			"1": "chair_polyfon",
+			"2": "chair_polyfon*2",
+			"3": "table_polyfon",
+			"4": "Throne",
+		},
+		GDIndex: map[string]GDItem{
+			"1": "Polyfon Chair",
+			"5": "chair_norja",
+		},
+		StorageSet: map[string]struct{}{"6": {}},
+	}
+	return cache, adapter
+}
+
+func hitKeys(hits []SearchHit) []string {
+	keys := make([]string, 0, len(hits))
+	for _, h := range hits {
+		keys = append(keys, h.ID)
+	}
+	return keys
+}
+
+// TestSearch_Ranking tests that exact matches rank before prefix, substring and fuzzy matches.
+func TestSearch_Ranking(t *testing.T) {
+	cache, adapter := searchCache()
+
+	hits := cache.Search(adapter, "chair_polyfon", 0)
+	assert.Equal(t, []string{"1", "2"}, hitKeys(hits))
+	assert.Equal(t, MatchExact, hits[0].Match)
+	assert.Equal(t, MatchPrefix, hits[1].Match)
+
+	hits = cache.Search(adapter, "polyfon", 0)
+	assert.Equal(t, []string{"1", "3", "2"}, hitKeys(hits))
+	assert.Equal(t, MatchPrefix, hits[0].Match)
+	assert.Equal(t, "polyfon chair", hits[0].Matched)
+	assert.Equal(t, MatchSubstring, hits[1].Match)
+
+	// Typos match words of a term; short queries never match fuzzily
+	hits = cache.Search(adapter, "THRONR", 0)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "4", hits[0].ID)
+	assert.Equal(t, MatchFuzzy, hits[0].Match)
+	assert.Equal(t, 1, hits[0].Distance)
+	assert.Equal(t, []string{"5"}, hitKeys(cache.Search(adapter, "norja", 0)))
+	assert.Empty(t, cache.Search(adapter, "nrj", 0))
+
+	// Keys match exactly, even for storage-only entities
+	assert.Equal(t, []string{"6"}, hitKeys(cache.Search(adapter, "6", 0)))
+
+	assert.Len(t, cache.Search(adapter, "chair", 2), 2)
+	assert.Empty(t, cache.Search(adapter, " ", 0))
+}
+
+// TestSearchIndex_Lookup tests exact, case-insensitive term lookup.
+func TestSearchIndex_Lookup(t *testing.T) {
+	cache, adapter := searchCache()
+	index := cache.SearchIndex(adapter)
+
+	assert.Equal(t, "1", index.Lookup("polyfon chair"))
+	assert.Equal(t, "2", index.Lookup("CHAIR_POLYFON*2"))
+	assert.Equal(t, "", index.Lookup("chair"))
+	assert.Same(t, index, cache.SearchIndex(adapter))
+}
+
+// TestReconcileOne_CachedNameLookup tests that cached targeted lookups find entities by name.
+func TestReconcileOne_CachedNameLookup(t *testing.T) {
+	_, adapter := searchCache()
+	a := adapter.(*searchAdapter)
+	a.dbIndex = map[string]DBItem{"1": "chair_polyfon"}
+	a.gdIndex = map[string]GDItem{}
+	a.storageSet = map[string]struct{}{}
+
+	spec := &Spec{Adapter: a, CacheTTL: time.Minute, ServerProfile: t.Name()}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	result, err := ReconcileOne(context.Background(), spec, nil, client, "", Query{Classname: "Chair_Polyfon"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", result.ID)
+	assert.True(t, result.DBPresent)
+}
+
+// TestEditDistance tests the optimal string alignment distance and its cutoff.
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("chair", "chair", 2))
+	assert.Equal(t, 1, editDistance("chiar", "chair", 2))
+	assert.Equal(t, 1, editDistance("chai", "chair", 2))
+	assert.Equal(t, 2, editDistance("chr", "chair", 2))
+	assert.Equal(t, 3, editDistance("table", "chair", 2))
+	assert.Equal(t, 3, editDistance("a", "chair", 2))
+}
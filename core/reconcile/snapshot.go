@@ -0,0 +1,403 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// SnapshotVersion is the format version of snapshots. Snapshots of another version are ignored.
+const SnapshotVersion = 1
+
+// ErrNoSnapshot is returned by a SnapshotStore when no snapshot exists for a cache key.
+var ErrNoSnapshot = errors.New("no reconcile snapshot")
+
+// dbItemsBatch is the number of keys loaded per LoadDBItems call.
+const dbItemsBatch = 1000
+
+// ObjectState is the state of a storage object when a snapshot was taken.
+type ObjectState struct {
+	// ETag is the object ETag.
+	ETag string
+
+	// LastModified is the object modification time.
+	LastModified time.Time
+
+	// Key is the entity key the object resolved to, if OK.
+	Key string
+
+	// OK reports whether the object resolved to an entity key.
+	OK bool
+}
+
+// Snapshot holds the indices of a build and what they were loaded from, so the next
+// build can reload only what changed.
+type Snapshot struct {
+	// Version is the snapshot format version.
+	Version int
+
+	// CacheKey is the Spec.CacheKey the snapshot was built for.
+	CacheKey string
+
+	// Built is when the indices were loaded.
+	Built time.Time
+
+	// DBIndex is the DB index of the build.
+	DBIndex map[string]DBItem
+
+	// DBChecksums holds the row checksum of every DB entity key.
+	DBChecksums map[string]uint32
+
+	// GDIndex is the gamedata index of the build.
+	GDIndex map[string]GDItem
+
+	// GamedataETag is the ETag of the gamedata object the index was parsed from.
+	GamedataETag string
+
+	// StorageObjects holds the state of every listed storage object by object key.
+	StorageObjects map[string]ObjectState
+}
+
+// Delta describes what an incremental build reloaded.
+type Delta struct {
+	// Incremental is false when no usable snapshot existed and every source was loaded.
+	Incremental bool `json:"incremental"`
+
+	// DBChanged is the number of DB entities added or changed since the snapshot.
+	DBChanged int `json:"db_changed"`
+
+	// DBRemoved is the number of DB entities removed since the snapshot.
+	DBRemoved int `json:"db_removed"`
+
+	// GamedataReloaded reports whether the gamedata object changed and was parsed again.
+	GamedataReloaded bool `json:"gamedata_reloaded"`
+
+	// StorageChanged is the number of storage objects added or changed since the snapshot.
+	StorageChanged int `json:"storage_changed"`
+
+	// StorageRemoved is the number of storage objects removed since the snapshot.
+	StorageRemoved int `json:"storage_removed"`
+
+	// SnapshotErr is the error saving the new snapshot, if any. The build itself succeeded.
+	SnapshotErr error `json:"-"`
+}
+
+// String summarizes the delta (e.g. "incremental: db 3 changed, 1 removed; gamedata unchanged; storage 0 changed, 0 removed").
+func (d *Delta) String() string {
+	if !d.Incremental {
+		return "full build (no snapshot)"
+	}
+	gamedata := "unchanged"
+	if d.GamedataReloaded {
+		gamedata = "reloaded"
+	}
+	return fmt.Sprintf("incremental: db %d changed, %d removed; gamedata %s; storage %d changed, %d removed",
+		d.DBChanged, d.DBRemoved, gamedata, d.StorageChanged, d.StorageRemoved)
+}
+
+// SnapshotStore persists one snapshot per cache key.
+type SnapshotStore interface {
+	// Load returns the snapshot of the cache key, or ErrNoSnapshot.
+	Load(cacheKey string) (*Snapshot, error)
+
+	// Save replaces the snapshot of its cache key.
+	Save(snapshot *Snapshot) error
+}
+
+// FileSnapshotStore stores snapshots as gob files in a directory.
+type FileSnapshotStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileSnapshotStore creates a snapshot store writing to dir, created on first save.
+func NewFileSnapshotStore(dir string) *FileSnapshotStore {
+	return &FileSnapshotStore{dir: dir}
+}
+
+// path returns the file of the cache key's snapshot.
+func (s *FileSnapshotStore) path(cacheKey string) string {
+	sum := sha256.Sum256([]byte(cacheKey))
+	return filepath.Join(s.dir, "snapshot-"+hex.EncodeToString(sum[:8])+".gob")
+}
+
+// Load implements SnapshotStore.
+func (s *FileSnapshotStore) Load(cacheKey string) (*Snapshot, error) {
+	f, err := os.Open(s.path(cacheKey))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSnapshot
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	var snapshot Snapshot
+	if err := gob.NewDecoder(f).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion || snapshot.CacheKey != cacheKey {
+		return nil, ErrNoSnapshot
+	}
+	return &snapshot, nil
+}
+
+// Save implements SnapshotStore. The file is replaced atomically.
+func (s *FileSnapshotStore) Save(snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "snapshot-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(snapshot); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(snapshot.CacheKey)); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// buildIncremental builds a cache reusing the previous snapshot of the spec:
+// DB rows are reloaded only when their checksum changed, gamedata only when its ETag
+// changed, and storage objects are resolved only when new or changed.
+// Without a usable snapshot every source is loaded and a first snapshot is saved.
+func buildIncremental(ctx context.Context, spec *Spec, inc Incremental, db *gorm.DB, client storage.Client, bucket string) (*ReconcileCache, error) {
+	cacheKey := spec.CacheKey()
+
+	prev, err := spec.Snapshots.Load(cacheKey)
+	if err != nil {
+		// Unreadable snapshots are rebuilt from scratch
+		prev = nil
+	}
+
+	next := &Snapshot{Version: SnapshotVersion, CacheKey: cacheKey}
+	delta := &Delta{Incremental: prev != nil}
+
+	var (
+		dbErr, gdErr error
+		wg           sync.WaitGroup
+	)
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		ctx, span := startPhaseSpan(ctx, PhaseLoadDB, spec)
+		next.DBIndex, next.DBChecksums, dbErr = loadDBDelta(ctx, spec, inc, db, prev, delta)
+		endSpan(span, dbErr)
+		if dbErr == nil {
+			ReportProgress(ctx, PhaseLoadDB, len(next.DBIndex), len(next.DBIndex))
+		}
+	}()
+
+	// Storage keys depend on the gamedata index, so storage is listed after it
+	go func() {
+		defer wg.Done()
+		gdCtx, span := startPhaseSpan(ctx, PhaseLoadGamedata, spec)
+		gdErr = loadGamedataDelta(gdCtx, spec, inc, client, bucket, prev, next, delta)
+		endSpan(span, gdErr)
+		if gdErr != nil {
+			return
+		}
+		ReportProgress(ctx, PhaseLoadGamedata, len(next.GDIndex), len(next.GDIndex))
+
+		storageCtx, span := startPhaseSpan(ctx, PhaseLoadStorage, spec)
+		gdErr = listStorageDelta(storageCtx, spec, client, bucket, prev, next, delta)
+		endSpan(span, gdErr)
+	}()
+
+	wg.Wait()
+
+	if dbErr != nil {
+		return nil, dbErr
+	}
+	if gdErr != nil {
+		return nil, gdErr
+	}
+
+	storageSet := make(map[string]struct{}, len(next.StorageObjects))
+	for _, obj := range next.StorageObjects {
+		if obj.OK {
+			storageSet[obj.Key] = struct{}{}
+		}
+	}
+	ReportProgress(ctx, PhaseLoadStorage, len(storageSet), len(storageSet))
+
+	if linker, ok := spec.Adapter.(StorageLinker); ok && spec.LinkStorage {
+		var links map[string]string
+		storageSet, links = linker.LinkStorageKeys(next.DBIndex, next.GDIndex, storageSet)
+		linker.UseStorageLinks(links)
+	}
+
+	next.Built = time.Now()
+	delta.SnapshotErr = spec.Snapshots.Save(next)
+
+	return &ReconcileCache{
+		DBIndex:      next.DBIndex,
+		GDIndex:      next.GDIndex,
+		StorageSet:   storageSet,
+		Built:        next.Built,
+		TTL:          spec.CacheTTL,
+		Delta:        delta,
+		gamedataETag: next.GamedataETag,
+	}, nil
+}
+
+// loadDBDelta reloads the DB items whose checksum differs from the snapshot.
+func loadDBDelta(ctx context.Context, spec *Spec, inc Incremental, db *gorm.DB, prev *Snapshot, delta *Delta) (map[string]DBItem, map[string]uint32, error) {
+	checksums, err := inc.DBChecksums(ctx, db, spec.ServerProfile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if prev == nil {
+		index, err := spec.Adapter.LoadDBIndex(ctx, db, spec.ServerProfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		delta.DBChanged = len(index)
+		return index, checksums, nil
+	}
+
+	index := make(map[string]DBItem, len(checksums))
+	var changed []string
+	for key, sum := range checksums {
+		item, known := prev.DBIndex[key]
+		if old, ok := prev.DBChecksums[key]; ok && old == sum && known {
+			index[key] = item
+			continue
+		}
+		changed = append(changed, key)
+	}
+	for key := range prev.DBChecksums {
+		if _, ok := checksums[key]; !ok {
+			delta.DBRemoved++
+		}
+	}
+
+	sort.Strings(changed)
+	for start := 0; start < len(changed); start += dbItemsBatch {
+		items, err := inc.LoadDBItems(ctx, db, spec.ServerProfile, changed[start:min(start+dbItemsBatch, len(changed))])
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, item := range items {
+			index[key] = item
+		}
+	}
+	delta.DBChanged = len(changed)
+
+	return index, checksums, nil
+}
+
+// loadGamedataDelta reuses the snapshot gamedata index if the gamedata object is unchanged.
+func loadGamedataDelta(ctx context.Context, spec *Spec, inc Incremental, client storage.Client, bucket string, prev, next *Snapshot, delta *Delta) error {
+	state, err := objectState(ctx, client, bucket, spec.GamedataObjectName)
+	if err != nil {
+		return err
+	}
+	next.GamedataETag = state.ETag
+
+	if prev != nil && state.ETag != "" && state.ETag == prev.GamedataETag {
+		next.GDIndex = prev.GDIndex
+		inc.UseGamedataIndex(next.GDIndex)
+		return nil
+	}
+
+	index, err := spec.Adapter.LoadGamedataIndex(ctx, client, bucket, spec.GamedataObjectName, spec.GamedataPaths)
+	if err != nil {
+		return err
+	}
+	next.GDIndex = index
+	delta.GamedataReloaded = true
+	return nil
+}
+
+// listStorageDelta lists storage, resolving only objects that are new or changed since
+// the snapshot. Every object is resolved again if the gamedata index was reloaded.
+func listStorageDelta(ctx context.Context, spec *Spec, client storage.Client, bucket string, prev, next *Snapshot, delta *Delta) error {
+	next.StorageObjects = make(map[string]ObjectState)
+
+	listed := 0
+	for obj := range client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: spec.StoragePrefix, Recursive: true}) {
+		if obj.Err != nil {
+			return fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if listed++; listed%1000 == 0 {
+			ReportProgress(ctx, PhaseLoadStorage, listed, 0)
+		}
+
+		state := ObjectState{ETag: obj.ETag, LastModified: obj.LastModified}
+
+		var old ObjectState
+		var known bool
+		if prev != nil {
+			old, known = prev.StorageObjects[obj.Key]
+		}
+		unchanged := known && old.ETag == state.ETag && old.LastModified.Equal(state.LastModified)
+
+		if unchanged && !delta.GamedataReloaded {
+			state.Key, state.OK = old.Key, old.OK
+		} else {
+			state.Key, state.OK = spec.Adapter.ExtractStorageKey(obj.Key, spec.StoragePrefix, spec.StorageExtension)
+		}
+		if !unchanged {
+			delta.StorageChanged++
+		}
+
+		next.StorageObjects[obj.Key] = state
+	}
+
+	if prev != nil {
+		for objectKey := range prev.StorageObjects {
+			if _, ok := next.StorageObjects[objectKey]; !ok {
+				delta.StorageRemoved++
+			}
+		}
+	}
+
+	return nil
+}
+
+// objectState returns the state of a single object by listing its exact key.
+// Returns an empty state if the object does not exist.
+func objectState(ctx context.Context, client storage.Client, bucket, objectName string) (ObjectState, error) {
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range client.ListObjects(listCtx, bucket, minio.ListObjectsOptions{Prefix: objectName}) {
+		if obj.Err != nil {
+			return ObjectState{}, fmt.Errorf("failed to stat %s: %w", objectName, obj.Err)
+		}
+		if obj.Key == objectName {
+			return ObjectState{ETag: obj.ETag, LastModified: obj.LastModified}, nil
+		}
+	}
+	return ObjectState{}, nil
+}
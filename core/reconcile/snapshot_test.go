@@ -0,0 +1,178 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// incrementalAdapter is a mock adapter supporting incremental builds.
+type incrementalAdapter struct {
+	mockAdapter
+	checksums map[string]uint32
+	loaded    []string
+	gdLoads   int
+	reused    int
+}
+
+func (a *incrementalAdapter) DBChecksums(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]uint32, error) {
+	return a.checksums, nil
+}
+
+func (a *incrementalAdapter) LoadDBItems(ctx context.Context, db *gorm.DB, serverProfile string, keys []string) (map[string]DBItem, error) {
+	items := make(map[string]DBItem)
+	for _, key := range keys {
+		a.loaded = append(a.loaded, key)
+		items[key] = a.dbIndex[key]
+	}
+	return items, nil
+}
+
+func (a *incrementalAdapter) LoadGamedataIndex(ctx context.Context, client storage.Client, bucket, objectName string, paths []string) (map[string]GDItem, error) {
+	a.gdLoads++
+	return a.gdIndex, nil
+}
+
+func (a *incrementalAdapter) UseGamedataIndex(index map[string]GDItem) {
+	a.reused++
+}
+
+func (a *incrementalAdapter) ExtractStorageKey(objectKey, prefix, extension string) (string, bool) {
+	return strings.TrimSuffix(strings.TrimPrefix(objectKey, prefix), extension), strings.HasSuffix(objectKey, extension)
+}
+
+// listingClient serves ListObjects from a map of object keys to ETags.
+type listingClient struct {
+	*mocks.Client
+	etags map[string]string
+}
+
+func (c *listingClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	ch := make(chan minio.ObjectInfo, len(c.etags))
+	for key, etag := range c.etags {
+		if strings.HasPrefix(key, opts.Prefix) {
+			ch <- minio.ObjectInfo{Key: key, ETag: etag, LastModified: time.Unix(1, 0)}
+		}
+	}
+	close(ch)
+	return ch
+}
+
+// TestBuildCache_Incremental tests that builds after the first reload only what changed.
+func TestBuildCache_Incremental(t *testing.T) {
+	adapter := &incrementalAdapter{
+		mockAdapter: mockAdapter{
+			dbIndex:    map[string]DBItem{"A": "A", "B": "B"},
+			gdIndex:    map[string]GDItem{"A": "A", "B": "B"},
+			mismatches: map[string][]string{},
+		},
+		checksums: map[string]uint32{"A": 1, "B": 2},
+	}
+
+	client := &listingClient{Client: new(mocks.Client), etags: map[string]string{
+		"gamedata.json": "gd1",
+		"s/A.nitro":     "a1",
+		"s/B.nitro":     "b1",
+		"s/readme.txt":  "r1",
+	}}
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	spec := &Spec{
+		Adapter:            adapter,
+		StoragePrefix:      "s/",
+		StorageExtension:   ".nitro",
+		GamedataObjectName: "gamedata.json",
+		Snapshots:          NewFileSnapshotStore(t.TempDir()),
+	}
+
+	// First build loads everything and saves a snapshot
+	cache, err := BuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	require.NotNil(t, cache.Delta)
+	assert.False(t, cache.Delta.Incremental)
+	assert.NoError(t, cache.Delta.SnapshotErr)
+	assert.Equal(t, map[string]struct{}{"A": {}, "B": {}}, cache.StorageSet)
+	assert.Equal(t, 1, adapter.gdLoads)
+
+	// Nothing changed: no DB rows or gamedata are loaded
+	cache, err = BuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, &Delta{Incremental: true}, cache.Delta)
+	assert.Empty(t, adapter.loaded)
+	assert.Equal(t, 1, adapter.gdLoads)
+	assert.Equal(t, 1, adapter.reused)
+	assert.Equal(t, map[string]DBItem{"A": "A", "B": "B"}, cache.DBIndex)
+
+	// B changed, C was added and A removed in the DB; A's bundle changed and B's was removed
+	adapter.dbIndex = map[string]DBItem{"B": "B2", "C": "C"}
+	adapter.checksums = map[string]uint32{"B": 3, "C": 4}
+	client.etags["s/A.nitro"] = "a2"
+	delete(client.etags, "s/B.nitro")
+	client.etags["gamedata.json"] = "gd2"
+
+	cache, err = BuildCache(context.Background(), spec, nil, client, "")
+	require.NoError(t, err)
+	assert.Equal(t, &Delta{
+		Incremental:      true,
+		DBChanged:        2,
+		DBRemoved:        1,
+		GamedataReloaded: true,
+		StorageChanged:   1,
+		StorageRemoved:   1,
+	}, cache.Delta)
+	assert.Equal(t, []string{"B", "C"}, adapter.loaded)
+	assert.Equal(t, map[string]DBItem{"B": "B2", "C": "C"}, cache.DBIndex)
+	assert.Equal(t, map[string]struct{}{"A": {}}, cache.StorageSet)
+	assert.Equal(t, 2, adapter.gdLoads)
+}
+
+// TestFileSnapshotStore tests saving, loading and rejecting snapshots.
+func TestFileSnapshotStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileSnapshotStore(filepath.Join(dir, "nested"))
+
+	_, err := store.Load("key")
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	snapshot := &Snapshot{
+		Version:        SnapshotVersion,
+		CacheKey:       "key",
+		DBIndex:        map[string]DBItem{"1": "one"},
+		DBChecksums:    map[string]uint32{"1": 42},
+		StorageObjects: map[string]ObjectState{"s/1.nitro": {ETag: "e", Key: "1", OK: true}},
+	}
+	require.NoError(t, store.Save(snapshot))
+
+	loaded, err := store.Load("key")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.DBIndex, loaded.DBIndex)
+	assert.Equal(t, snapshot.StorageObjects, loaded.StorageObjects)
+
+	// Other keys and format versions are ignored
+	_, err = store.Load("other")
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	snapshot.Version = SnapshotVersion + 1
+	require.NoError(t, store.Save(snapshot))
+	_, err = store.Load("key")
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	// Corrupt files are reported
+	require.NoError(t, os.WriteFile(store.path("key"), []byte("garbage"), 0o644))
+	_, err = store.Load("key")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNoSnapshot))
+}
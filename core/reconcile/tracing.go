@@ -0,0 +1,53 @@
+package reconcile
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName names the tracer of the reconcile spans.
+const tracerName = "asset-manager/core/reconcile"
+
+// tracer holds the process-wide tracer of the reconcile spans.
+var tracer atomic.Pointer[trace.Tracer]
+
+// SetTracerProvider sets the provider of the spans recorded for cache builds, their
+// load phases, plans and applies. Nil disables tracing.
+func SetTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		tracer.Store(nil)
+		return
+	}
+	t := tp.Tracer(tracerName)
+	tracer.Store(&t)
+}
+
+// startSpan starts a span named reconcile.<name> for the adapter of spec.
+func startSpan(ctx context.Context, name string, spec *Spec) (context.Context, trace.Span) {
+	t := tracer.Load()
+	if t == nil {
+		return noop.Tracer{}.Start(ctx, "reconcile."+name)
+	}
+	return (*t).Start(ctx, "reconcile."+name, trace.WithAttributes(
+		attribute.String("reconcile.adapter", spec.Adapter.Name()),
+	))
+}
+
+// startPhaseSpan starts the span of a load or apply phase.
+func startPhaseSpan(ctx context.Context, phase Phase, spec *Spec) (context.Context, trace.Span) {
+	return startSpan(ctx, string(phase), spec)
+}
+
+// endSpan ends a span, recording err.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
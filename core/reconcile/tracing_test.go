@@ -0,0 +1,49 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracing tests that plans record spans for the build and each load phase.
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { SetTracerProvider(nil) })
+
+	adapter := &mockAdapter{
+		dbIndex:    map[string]DBItem{"A": "A"},
+		gdIndex:    map[string]GDItem{"A": "A"},
+		storageSet: map[string]struct{}{"A": {}},
+		mismatches: map[string][]string{},
+	}
+	spec := &Spec{Adapter: adapter, StoragePrefix: "tracing/"}
+	t.Cleanup(func() { InvalidateCache(spec) })
+
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "").Return(true, nil)
+
+	_, err := ReconcileWithPlan(context.Background(), spec, nil, client, "", ReconcileOptions{})
+	require.NoError(t, err)
+
+	parents := make(map[string]string)
+	ids := make(map[string]string)
+	for _, span := range recorder.Ended() {
+		ids[span.Name()] = span.SpanContext().SpanID().String()
+		parents[span.Name()] = span.Parent().SpanID().String()
+	}
+
+	require.Len(t, ids, 5)
+	assert.Equal(t, ids["reconcile.plan"], parents["reconcile.build"])
+	for _, phase := range []Phase{PhaseLoadDB, PhaseLoadGamedata, PhaseLoadStorage} {
+		assert.Equal(t, ids["reconcile.build"], parents["reconcile."+string(phase)], phase)
+	}
+}
@@ -51,6 +51,14 @@ type Spec struct {
 	// If zero, caching is disabled.
 	CacheTTL time.Duration
 
+	// MaxStale is how long after expiring a cache may still be served by GetOrBuildCache
+	// while it is rebuilt in the background. If zero, requests wait for the rebuild.
+	MaxStale time.Duration
+
+	// RefreshInterval is how often StartRefresher rebuilds the cache in the background.
+	// If zero, caches are only rebuilt on request.
+	RefreshInterval time.Duration
+
 	// StoragePrefix is the prefix under which to list storage objects.
 	StoragePrefix string
 
@@ -67,6 +75,28 @@ type Spec struct {
 
 	// ServerProfile is the emulator-specific configuration (e.g., "arcturus", "comet").
 	ServerProfile string
+
+	// CacheName separates the cache of this spec from other specs over the same sources,
+	// e.g. a long-lived listing cache from one-off scans that rebuild it on every run.
+	// If empty, the spec shares the cache of its sources.
+	CacheName string
+
+	// LinkStorage lets a StorageLinker adapter rewrite storage keys to match DB entities
+	// missing in gamedata. It changes which entities storage objects match, so it is only
+	// enabled for runs creating gamedata from DB rows. Linked specs have their own cache.
+	LinkStorage bool
+
+	// Progress is notified while indices load and plans apply.
+	// If nil, progress is not reported.
+	Progress ProgressObserver
+
+	// OnAction is notified with the outcome of each action executed by ApplyPlan.
+	// If nil, action results are not reported.
+	OnAction ActionObserver
+
+	// Snapshots persists the indices of each build so the next build of an Incremental
+	// adapter only reloads what changed. If nil, every build reloads all sources.
+	Snapshots SnapshotStore
 }
 
 // CacheKey returns a unique key for caching based on spec parameters.
@@ -77,6 +107,12 @@ func (s *Spec) CacheKey() string {
 	for _, path := range s.GamedataPaths {
 		key += "|" + path
 	}
+	if s.CacheName != "" {
+		key += "|" + s.CacheName
+	}
+	if s.LinkStorage {
+		key += "|linked"
+	}
 	return key
 }
 
@@ -100,6 +136,10 @@ const (
 	ActionDeleteStorage ActionType = "delete_storage"
 	// ActionSyncDB syncs database fields from gamedata.
 	ActionSyncDB ActionType = "sync_db"
+	// ActionCreateDB creates a missing database entity from gamedata.
+	ActionCreateDB ActionType = "create_db"
+	// ActionCreateGamedata creates a missing gamedata entity from the database.
+	ActionCreateGamedata ActionType = "create_gamedata"
 )
 
 // Action represents a planned mutation operation.
@@ -113,9 +153,14 @@ type Action struct {
 	// Reason explains why this action is needed.
 	Reason string `json:"reason"`
 
-	// GDItem stores the gamedata source for sync actions.
-	// Only populated for ActionSyncDB.
+	// GDItem stores the gamedata source for sync and create actions.
+	// Only populated for ActionSyncDB and ActionCreateDB.
 	GDItem GDItem `json:"-"`
+
+	// DBItem stores the database row as captured in the DB index.
+	// Populated for ActionDeleteDB and ActionSyncDB so the mutation can be journaled,
+	// and for ActionCreateGamedata as the source of the new entity.
+	DBItem DBItem `json:"-"`
 }
 
 // ReconcilePlan contains reconciliation results and planned actions.
@@ -128,6 +173,16 @@ type ReconcilePlan struct {
 
 	// Summary provides aggregate counts.
 	Summary PlanSummary `json:"summary"`
+
+	// CacheBuilt is the build time of the cache the plan was computed from.
+	// Used by CheckPlanFresh to detect plans computed from outdated indices.
+	CacheBuilt time.Time `json:"cache_built"`
+
+	// CacheGeneration is the Generation of that cache, compared by CheckPlanFresh too.
+	CacheGeneration int `json:"cache_generation"`
+
+	// Delta describes what an incremental build of that cache reloaded; nil for full builds.
+	Delta *Delta `json:"delta,omitempty"`
 }
 
 // PlanSummary provides aggregate statistics for a reconcile plan.
@@ -152,6 +207,20 @@ type PlanSummary struct {
 
 	// SyncActions counts planned sync (update) actions.
 	SyncActions int `json:"sync_actions"`
+
+	// CreateActions counts planned create (repair) actions.
+	CreateActions int `json:"create_actions"`
+
+	// SharedStorageKept counts storage deletions left out of the plan because other
+	// entities still use the object (see StorageSharer).
+	SharedStorageKept int `json:"shared_storage_kept,omitempty"`
+
+	// BlockedReason explains why the plan exceeds the safety thresholds.
+	// Empty if the plan is within limits.
+	BlockedReason string `json:"blocked_reason,omitempty"`
+
+	// Forced indicates the plan exceeds the safety thresholds but was overridden.
+	Forced bool `json:"forced,omitempty"`
 }
 
 // ReconcileOptions controls reconcile behavior for purge/sync operations.
@@ -162,10 +231,36 @@ type ReconcileOptions struct {
 	// DoPurge enables deletion of entities missing in any store.
 	DoPurge bool
 
+	// PurgeFrom restricts purge actions to the given sources.
+	// If empty, entities are deleted from every source they exist in.
+	PurgeFrom []Source
+
+	// PurgePresence restricts purge actions to entities matching one of the patterns.
+	// If empty, every incomplete entity is purged.
+	PurgePresence []Presence
+
 	// DoSync enables syncing of mismatched fields from gamedata to DB.
 	DoSync bool
 
+	// DoCreateDB enables creating DB entities present in both gamedata and storage.
+	// Creation takes precedence over purge for those entities.
+	DoCreateDB bool
+
+	// DoCreateGamedata enables creating gamedata entities present in both DB and storage.
+	// Creation takes precedence over purge for those entities.
+	DoCreateGamedata bool
+
 	// Confirmed indicates user has confirmed destructive actions.
 	// If false, mutations will not execute regardless of DryRun.
 	Confirmed bool
+
+	// Journal records every executed mutation so it can be undone later.
+	// If nil, mutations are not journaled.
+	Journal *Journal
+
+	// Safety holds the guard rails applied to purge actions.
+	Safety Config
+
+	// Force overrides the safety thresholds.
+	Force bool
 }
@@ -1,13 +1,57 @@
 package server
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
 // Config holds configuration for the HTTP server.
 type Config struct {
 	// Port is the port where the server will listen.
 	Port string `mapstructure:"port" default:"8080"`
-	// ApiKey is the secret key required to access the API.
+	// ApiKey is a secret key granted every scope, logged as "default".
 	ApiKey string `mapstructure:"api_key" default:""`
+	// ApiKeys are named keys with scopes, as "name:secret:scope,scope" entries separated
+	// by ';' (see auth.ParseKeys).
+	ApiKeys string `mapstructure:"api_keys" default:""`
+	// ApiKeysFile is a JSON file of named keys with scopes (see auth.LoadKeysFile).
+	ApiKeysFile string `mapstructure:"api_keys_file" default:""`
 	// Emulator specifies the emulator type (arcturus, plusemu, comet).
 	Emulator string `mapstructure:"emulator" default:"arcturus"`
+	// ShutdownTimeoutSeconds is how long shutdown waits for in-flight requests, reconcile
+	// applies and jobs before interrupting them.
+	ShutdownTimeoutSeconds int `mapstructure:"shutdown_timeout_seconds" default:"30"`
+	// ProxyHeader is the header a reverse proxy sets to the client IP (e.g. X-Real-IP),
+	// used for logs and the auth lockout. Empty uses the connection address.
+	ProxyHeader string `mapstructure:"proxy_header" default:""`
+	// TrustedProxies are the proxy IPs or CIDR ranges, separated by ',', whose ProxyHeader
+	// is trusted. Required with ProxyHeader, as any client could forge the header otherwise.
+	TrustedProxies string `mapstructure:"trusted_proxies" default:""`
+}
+
+// TrustedProxyList returns the trusted proxies. It fails if ProxyHeader is set without
+// any, or if they are set without a ProxyHeader.
+func (c Config) TrustedProxyList() ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	switch {
+	case c.ProxyHeader != "" && len(proxies) == 0:
+		return nil, errors.New("SERVER_PROXY_HEADER requires SERVER_TRUSTED_PROXIES")
+	case c.ProxyHeader == "" && len(proxies) > 0:
+		return nil, errors.New("SERVER_TRUSTED_PROXIES requires SERVER_PROXY_HEADER")
+	}
+	return proxies, nil
+}
+
+// ShutdownTimeout returns the shutdown deadline as a duration.
+func (c Config) ShutdownTimeout() time.Duration {
+	return time.Duration(c.ShutdownTimeoutSeconds) * time.Second
 }
 
 const (
@@ -26,3 +26,18 @@ func TestIsValidEmulator(t *testing.T) {
 		})
 	}
 }
+
+func TestTrustedProxyList(t *testing.T) {
+	proxies, err := Config{ProxyHeader: "X-Real-IP", TrustedProxies: "10.0.0.1, 172.16.0.0/12,"}.TrustedProxyList()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "172.16.0.0/12"}, proxies)
+
+	proxies, err = Config{}.TrustedProxyList()
+	assert.NoError(t, err)
+	assert.Empty(t, proxies)
+
+	_, err = Config{ProxyHeader: "X-Real-IP"}.TrustedProxyList()
+	assert.Error(t, err)
+	_, err = Config{TrustedProxies: "10.0.0.1"}.TrustedProxyList()
+	assert.Error(t, err)
+}
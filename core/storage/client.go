@@ -30,6 +30,8 @@ type Client interface {
 	// RemoveObjects deletes multiple objects from a bucket efficiently.
 	// objectsCh is a channel of object names to delete.
 	RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError
+	// CopyObject performs a server-side copy of an object.
+	CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
 }
 
 // NewClient creates a new Minio client based on the configuration.
@@ -73,11 +75,27 @@ func NewClient(cfg Config) (Client, error) {
 	// But ListBuckets or similar would verify. We rely on operation-level timeouts from Context for the rest.
 	// The transport timeouts ensure we don't hang on connection setup.
 
-	return &minioClientWrapper{Client: minioClient}, nil
+	return &minioClientWrapper{Client: minioClient, transport: transport}, nil
 }
 
 type minioClientWrapper struct {
 	*minio.Client
+	transport *http.Transport
+}
+
+// Close releases the idle connections of the client. Calls in flight are not interrupted.
+func (c *minioClientWrapper) Close() error {
+	c.transport.CloseIdleConnections()
+	return nil
+}
+
+// Close releases the connections held by a client, if it holds any (see io.Closer).
+// Wrapped clients are closed through their wrappers.
+func Close(client Client) error {
+	if closer, ok := client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 func (c *minioClientWrapper) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
@@ -1,5 +1,7 @@
 package storage
 
+import "time"
+
 // Config holds configuration for the storage provider.
 type Config struct {
 	// Endpoint is the URL of the storage service.
@@ -16,4 +18,23 @@ type Config struct {
 	Region string `mapstructure:"region" default:""`
 	// TimeoutSeconds is the connection timeout in seconds.
 	TimeoutSeconds int `mapstructure:"timeout_seconds" default:"30"`
+	// TrashEnabled copies purged objects to the trash before removing them.
+	TrashEnabled bool `mapstructure:"trash_enabled" default:"false"`
+	// TrashRetentionHours is how long trashed objects are kept before they may be emptied.
+	TrashRetentionHours int `mapstructure:"trash_retention_hours" default:"168"`
+	// NotifyToken is the auth token of the bucket notification webhook (POST /storage/events).
+	// If empty, the webhook is disabled.
+	NotifyToken string `mapstructure:"notify_token" default:""`
+	// SlowCallMillis logs storage calls slower than this many milliseconds (0 disables).
+	SlowCallMillis int `mapstructure:"slow_call_millis" default:"1000"`
+}
+
+// TrashRetention returns the trash retention period as a duration.
+func (c Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionHours) * time.Hour
+}
+
+// SlowCallThreshold returns the slow call threshold as a duration.
+func (c Config) SlowCallThreshold() time.Duration {
+	return time.Duration(c.SlowCallMillis) * time.Millisecond
 }
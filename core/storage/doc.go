@@ -7,7 +7,8 @@
 // # Client Interface
 //
 // The Client interface abstracts the underlying storage provider, making it easier
-// to mock storage interactions for unit testing (as seen in core/storage/mocks).
+// to mock storage interactions for unit testing (as seen in core/storage/mocks, which
+// also provides Memory, an in-memory bucket).
 //
 // # Operations
 //
@@ -16,6 +17,22 @@
 //   - PutObject: Uploads content (with size and options).
 //   - GetObject: Retrieves content as a stream.
 //   - ListObjects: Lists objects in a bucket (supports prefix/recursive).
+//   - CopyObject: Copies an object server-side (used for quarantine and restore).
+//
+// # Trash
+//
+// Trash implements soft deletion: objects are copied to .trash/<timestamp>-<suffix>/<original key>
+// before being removed, and can be listed, restored or permanently emptied once their
+// retention period has expired.
+//
+// # Instrumentation
+//
+// InstrumentedClient decorates any Client, reporting every call (latency, bytes
+// transferred, objects listed or removed, error) to CallObservers such as the metrics
+// collector or SlowCallLogger, which logs calls slower than STORAGE_SLOW_CALL_MILLIS
+// with the ID of the request (e.g. its Ray ID). Wrap composes decorators:
+//
+//	client = storage.Wrap(client, storage.Instrument(observers...))
 //
 // # Usage
 //
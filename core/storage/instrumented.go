@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// Wrapper decorates a storage client, e.g. with instrumentation or retries.
+type Wrapper func(Client) Client
+
+// Wrap applies the wrappers to a client in order: the last wrapper is the outermost,
+// so it sees every call first.
+func Wrap(client Client, wrappers ...Wrapper) Client {
+	for _, wrap := range wrappers {
+		client = wrap(client)
+	}
+	return client
+}
+
+// Call describes a finished storage client call.
+type Call struct {
+	// Method is the storage.Client method name (e.g. GetObject).
+	Method string
+	// Bucket is the bucket of the call. For CopyObject, the destination bucket.
+	Bucket string
+	// Object is the object key, if the call targets a single object.
+	Object string
+	// Duration is the call latency. Listings, removals and downloads are timed until
+	// fully consumed (channel closed or object closed).
+	Duration time.Duration
+	// Bytes is the number of bytes uploaded, downloaded or copied.
+	Bytes int64
+	// Objects is the number of objects listed or removed.
+	Objects int
+	// Err is the error of the call, or the last error of a listing or removal.
+	Err error
+}
+
+// CallObserver is notified of every call of an instrumented client.
+// Implementations must be safe for concurrent use.
+type CallObserver interface {
+	// ObserveCall is called once per finished call, with the context of the call.
+	ObserveCall(ctx context.Context, call Call)
+}
+
+// CallObserverFunc adapts a function to a CallObserver.
+type CallObserverFunc func(ctx context.Context, call Call)
+
+// ObserveCall implements CallObserver.
+func (f CallObserverFunc) ObserveCall(ctx context.Context, call Call) {
+	f(ctx, call)
+}
+
+// Instrument returns a Wrapper reporting every call to the observers.
+func Instrument(observers ...CallObserver) Wrapper {
+	return func(client Client) Client {
+		return NewInstrumentedClient(client, observers...)
+	}
+}
+
+// InstrumentedClient decorates any Client, timing every call and reporting it with
+// the bytes transferred and its error to the observers.
+type InstrumentedClient struct {
+	Client
+	observers []CallObserver
+}
+
+// NewInstrumentedClient wraps a client, reporting its calls to the observers.
+func NewInstrumentedClient(client Client, observers ...CallObserver) *InstrumentedClient {
+	return &InstrumentedClient{Client: client, observers: observers}
+}
+
+// observe reports a finished call to every observer.
+func (c *InstrumentedClient) observe(ctx context.Context, start time.Time, call Call) {
+	call.Duration = time.Since(start)
+	for _, o := range c.observers {
+		o.ObserveCall(ctx, call)
+	}
+}
+
+// BucketExists implements Client.
+func (c *InstrumentedClient) BucketExists(ctx context.Context, bucketName string) (bool, error) {
+	start := time.Now()
+	exists, err := c.Client.BucketExists(ctx, bucketName)
+	c.observe(ctx, start, Call{Method: "BucketExists", Bucket: bucketName, Err: err})
+	return exists, err
+}
+
+// MakeBucket implements Client.
+func (c *InstrumentedClient) MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error {
+	start := time.Now()
+	err := c.Client.MakeBucket(ctx, bucketName, opts)
+	c.observe(ctx, start, Call{Method: "MakeBucket", Bucket: bucketName, Err: err})
+	return err
+}
+
+// PutObject implements Client.
+func (c *InstrumentedClient) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	start := time.Now()
+	info, err := c.Client.PutObject(ctx, bucketName, objectName, reader, objectSize, opts)
+	c.observe(ctx, start, Call{Method: "PutObject", Bucket: bucketName, Object: objectName, Bytes: info.Size, Err: err})
+	return info, err
+}
+
+// GetObject implements Client. The call is reported when the object is closed, with
+// the bytes read from it.
+func (c *InstrumentedClient) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	start := time.Now()
+	call := Call{Method: "GetObject", Bucket: bucketName, Object: objectName}
+
+	obj, err := c.Client.GetObject(ctx, bucketName, objectName, opts)
+	if err != nil {
+		call.Err = err
+		c.observe(ctx, start, call)
+		return nil, err
+	}
+
+	return &countingReader{ReadCloser: obj, done: func(bytes int64, err error) {
+		call.Bytes = bytes
+		call.Err = err
+		c.observe(ctx, start, call)
+	}}, nil
+}
+
+// ListObjects implements Client. The listing is reported once the channel closes.
+func (c *InstrumentedClient) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	start := time.Now()
+	in := c.Client.ListObjects(ctx, bucketName, opts)
+	out := make(chan minio.ObjectInfo)
+
+	go func() {
+		defer close(out)
+		call := Call{Method: "ListObjects", Bucket: bucketName, Object: opts.Prefix}
+		for obj := range in {
+			if obj.Err != nil {
+				call.Err = obj.Err
+			} else {
+				call.Objects++
+			}
+			// Consumers that stop reading cancel the context; keep draining
+			select {
+			case out <- obj:
+			case <-ctx.Done():
+			}
+		}
+		c.observe(ctx, start, call)
+	}()
+
+	return out
+}
+
+// RemoveObject implements Client.
+func (c *InstrumentedClient) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	start := time.Now()
+	err := c.Client.RemoveObject(ctx, bucketName, objectName, opts)
+	c.observe(ctx, start, Call{Method: "RemoveObject", Bucket: bucketName, Object: objectName, Err: err})
+	return err
+}
+
+// RemoveObjects implements Client. The removal is reported once the error channel
+// closes, with the number of objects sent for removal.
+func (c *InstrumentedClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	start := time.Now()
+
+	var mu sync.Mutex
+	sent := 0
+	counted := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(counted)
+		for obj := range objectsCh {
+			mu.Lock()
+			sent++
+			mu.Unlock()
+			select {
+			case counted <- obj:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	in := c.Client.RemoveObjects(ctx, bucketName, counted, opts)
+	out := make(chan minio.RemoveObjectError)
+
+	go func() {
+		defer close(out)
+		call := Call{Method: "RemoveObjects", Bucket: bucketName}
+		for removeErr := range in {
+			if removeErr.Err != nil {
+				call.Err = removeErr.Err
+			}
+			select {
+			case out <- removeErr:
+			case <-ctx.Done():
+			}
+		}
+		mu.Lock()
+		call.Objects = sent
+		mu.Unlock()
+		c.observe(ctx, start, call)
+	}()
+
+	return out
+}
+
+// CopyObject implements Client.
+func (c *InstrumentedClient) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	start := time.Now()
+	info, err := c.Client.CopyObject(ctx, dst, src)
+	c.observe(ctx, start, Call{Method: "CopyObject", Bucket: dst.Bucket, Object: dst.Object, Bytes: info.Size, Err: err})
+	return info, err
+}
+
+// Close implements io.Closer, closing the wrapped client.
+func (c *InstrumentedClient) Close() error {
+	return Close(c.Client)
+}
+
+// countingReader counts the bytes read from an object and reports them once on Close.
+type countingReader struct {
+	io.ReadCloser
+	bytes   int64
+	readErr error
+	once    sync.Once
+	done    func(bytes int64, err error)
+}
+
+// Read implements io.Reader.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.bytes += int64(n)
+	if err != nil && err != io.EOF {
+		r.readErr = err
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *countingReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(func() {
+		if r.readErr != nil {
+			r.done(r.bytes, r.readErr)
+			return
+		}
+		r.done(r.bytes, err)
+	})
+	return err
+}
+
+// SlowCallLogger returns an observer logging calls slower than threshold with the ID of
+// the request that made them, as returned by requestID (e.g. rayid.FromContext). A nil
+// requestID logs no ID, and a threshold of 0 logs nothing.
+func SlowCallLogger(l *zap.Logger, threshold time.Duration, requestID func(context.Context) string) CallObserver {
+	return CallObserverFunc(func(ctx context.Context, call Call) {
+		if threshold <= 0 || call.Duration < threshold {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", call.Method),
+			zap.String("bucket", call.Bucket),
+			zap.Duration("duration", call.Duration),
+		}
+		if call.Object != "" {
+			fields = append(fields, zap.String("object", call.Object))
+		}
+		if call.Bytes > 0 {
+			fields = append(fields, zap.Int64("bytes", call.Bytes))
+		}
+		if call.Objects > 0 {
+			fields = append(fields, zap.Int("objects", call.Objects))
+		}
+		if requestID != nil {
+			if rid := requestID(ctx); rid != "" {
+				fields = append(fields, zap.String("ray_id", rid))
+			}
+		}
+		if call.Err != nil {
+			fields = append(fields, zap.Error(call.Err))
+		}
+
+		l.Warn("Slow storage call", fields...)
+	})
+}
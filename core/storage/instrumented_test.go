@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// callRecorder collects observed calls.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *callRecorder) ObserveCall(_ context.Context, call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *callRecorder) get() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("PutObject", mock.Anything, "assets", "a.nitro", mock.Anything, int64(4), mock.Anything).
+		Return(minio.UploadInfo{Size: 4}, nil)
+	client.On("GetObject", mock.Anything, "assets", "a.nitro", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("data")), nil)
+	client.On("BucketExists", mock.Anything, "down").Return(false, errors.New("unreachable"))
+
+	rec := &callRecorder{}
+	store := NewInstrumentedClient(client, rec)
+	ctx := context.Background()
+
+	_, err := store.PutObject(ctx, "assets", "a.nitro", strings.NewReader("data"), 4, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	// Downloads are reported on close with the bytes read
+	obj, err := store.GetObject(ctx, "assets", "a.nitro", minio.GetObjectOptions{})
+	require.NoError(t, err)
+	assert.Len(t, rec.get(), 1)
+	data, err := io.ReadAll(obj)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	require.NoError(t, obj.Close())
+	require.NoError(t, obj.Close())
+
+	_, err = store.BucketExists(ctx, "down")
+	require.Error(t, err)
+
+	calls := rec.get()
+	require.Len(t, calls, 3)
+	assert.Equal(t, Call{Method: "PutObject", Bucket: "assets", Object: "a.nitro", Bytes: 4, Duration: calls[0].Duration}, calls[0])
+	assert.Equal(t, "GetObject", calls[1].Method)
+	assert.Equal(t, int64(4), calls[1].Bytes)
+	assert.Equal(t, "BucketExists", calls[2].Method)
+	assert.EqualError(t, calls[2].Err, "unreachable")
+}
+
+// lockedClient fails to remove one object.
+type lockedClient struct {
+	mocks.Client
+	locked string
+}
+
+func (c *lockedClient) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	errs := make(chan minio.RemoveObjectError)
+	go func() {
+		defer close(errs)
+		for obj := range objectsCh {
+			if obj.Key == c.locked {
+				errs <- minio.RemoveObjectError{ObjectName: obj.Key, Err: errors.New("locked")}
+			}
+		}
+	}()
+	return errs
+}
+
+func TestInstrumentedClient_Listings(t *testing.T) {
+	listing := make(chan minio.ObjectInfo, 3)
+	listing <- minio.ObjectInfo{Key: "a"}
+	listing <- minio.ObjectInfo{Key: "b"}
+	listing <- minio.ObjectInfo{Err: errors.New("denied")}
+	close(listing)
+
+	client := &lockedClient{locked: "b"}
+	client.On("ListObjects", mock.Anything, "assets", mock.Anything).Return((<-chan minio.ObjectInfo)(listing))
+
+	rec := &callRecorder{}
+	store := NewInstrumentedClient(client, rec)
+	ctx := context.Background()
+
+	var keys []string
+	for obj := range store.ListObjects(ctx, "assets", minio.ListObjectsOptions{Prefix: "furniture/"}) {
+		keys = append(keys, obj.Key)
+	}
+	assert.Equal(t, []string{"a", "b", ""}, keys)
+
+	objects := make(chan minio.ObjectInfo, 2)
+	objects <- minio.ObjectInfo{Key: "a"}
+	objects <- minio.ObjectInfo{Key: "b"}
+	close(objects)
+	var failed []string
+	for removeErr := range store.RemoveObjects(ctx, "assets", objects, minio.RemoveObjectsOptions{}) {
+		failed = append(failed, removeErr.ObjectName)
+	}
+	assert.Equal(t, []string{"b"}, failed)
+
+	// Reported once the channels close
+	require.Eventually(t, func() bool { return len(rec.get()) == 2 }, time.Second, time.Millisecond)
+	calls := rec.get()
+	assert.Equal(t, "ListObjects", calls[0].Method)
+	assert.Equal(t, "furniture/", calls[0].Object)
+	assert.Equal(t, 2, calls[0].Objects)
+	assert.EqualError(t, calls[0].Err, "denied")
+	assert.Equal(t, "RemoveObjects", calls[1].Method)
+	assert.Equal(t, 2, calls[1].Objects)
+	assert.EqualError(t, calls[1].Err, "locked")
+}
+
+func TestWrap(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+
+	var order []string
+	named := func(name string) Wrapper {
+		return Instrument(CallObserverFunc(func(context.Context, Call) { order = append(order, name) }))
+	}
+
+	// The outermost wrapper finishes last
+	store := Wrap(client, named("inner"), named("outer"))
+	_, err := store.BucketExists(context.Background(), "assets")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inner", "outer"}, order)
+}
+
+func TestSlowCallLogger(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	type rayKey struct{}
+	rayID := func(ctx context.Context) string {
+		rid, _ := ctx.Value(rayKey{}).(string)
+		return rid
+	}
+	slow := SlowCallLogger(zap.New(core), 100*time.Millisecond, rayID)
+
+	ctx := context.WithValue(context.Background(), rayKey{}, "ray-123")
+	slow.ObserveCall(ctx, Call{Method: "GetObject", Bucket: "assets", Object: "a.nitro", Duration: 10 * time.Millisecond})
+	slow.ObserveCall(ctx, Call{Method: "GetObject", Bucket: "assets", Object: "b.nitro", Duration: time.Second, Bytes: 1024})
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, "Slow storage call", entry.Message)
+	fields := entry.ContextMap()
+	assert.Equal(t, "ray-123", fields["ray_id"])
+	assert.Equal(t, "b.nitro", fields["object"])
+	assert.Equal(t, int64(1024), fields["bytes"])
+
+	// A zero threshold disables it
+	SlowCallLogger(zap.New(core), 0, rayID).ObserveCall(ctx, Call{Method: "GetObject", Duration: time.Hour})
+	assert.Equal(t, 1, logs.Len())
+}
@@ -60,3 +60,8 @@ func (m *Client) RemoveObjects(ctx context.Context, bucketName string, objectsCh
 	close(ch)
 	return ch
 }
+
+func (m *Client) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	args := m.Called(ctx, dst, src)
+	return args.Get(0).(minio.UploadInfo), args.Error(1)
+}
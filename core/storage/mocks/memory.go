@@ -0,0 +1,135 @@
+package mocks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Memory is an in-memory implementation of storage.Client for tests that need objects
+// to behave like a bucket rather than scripted expectations. Bucket names are ignored.
+type Memory struct {
+	mu sync.Mutex
+	// Objects holds the stored objects by key. Tests may read and seed it directly
+	// while no operation is running.
+	Objects map[string][]byte
+	// RemoveErrs fails the removal of the given keys with the given errors.
+	RemoveErrs map[string]error
+}
+
+// NewMemory creates an in-memory client holding the given objects, each containing
+// its own key.
+func NewMemory(keys ...string) *Memory {
+	m := &Memory{Objects: make(map[string][]byte)}
+	for _, key := range keys {
+		m.Objects[key] = []byte(key)
+	}
+	return m
+}
+
+func (m *Memory) BucketExists(ctx context.Context, bucketName string) (bool, error) {
+	return true, nil
+}
+
+func (m *Memory) MakeBucket(ctx context.Context, bucketName string, opts minio.MakeBucketOptions) error {
+	return nil
+}
+
+func (m *Memory) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+	m.mu.Lock()
+	m.Objects[objectName] = data
+	m.mu.Unlock()
+	return minio.UploadInfo{Key: objectName, Size: int64(len(data))}, nil
+}
+
+func (m *Memory) GetObject(ctx context.Context, bucketName, objectName string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.Objects[objectName]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", objectName)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// ListObjects lists the objects under opts.Prefix in key order. Without opts.Recursive,
+// keys below the next "/" are grouped into a single prefix entry, like MinIO.
+func (m *Memory) ListObjects(ctx context.Context, bucketName string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	var infos []minio.ObjectInfo
+	for key, data := range m.Objects {
+		rest, ok := strings.CutPrefix(key, opts.Prefix)
+		if !ok {
+			continue
+		}
+		if !opts.Recursive {
+			if dir, _, nested := strings.Cut(rest, "/"); nested {
+				prefix := opts.Prefix + dir + "/"
+				if _, dup := seen[prefix]; !dup {
+					seen[prefix] = struct{}{}
+					infos = append(infos, minio.ObjectInfo{Key: prefix})
+				}
+				continue
+			}
+		}
+		infos = append(infos, minio.ObjectInfo{Key: key, Size: int64(len(data))})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+
+	ch := make(chan minio.ObjectInfo, len(infos))
+	for _, info := range infos {
+		ch <- info
+	}
+	close(ch)
+	return ch
+}
+
+func (m *Memory) RemoveObject(ctx context.Context, bucketName, objectName string, opts minio.RemoveObjectOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err, ok := m.RemoveErrs[objectName]; ok {
+		return err
+	}
+	delete(m.Objects, objectName)
+	return nil
+}
+
+func (m *Memory) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan minio.ObjectInfo, opts minio.RemoveObjectsOptions) <-chan minio.RemoveObjectError {
+	var errs []minio.RemoveObjectError
+	for obj := range objectsCh {
+		if err := m.RemoveObject(ctx, bucketName, obj.Key, minio.RemoveObjectOptions{}); err != nil {
+			errs = append(errs, minio.RemoveObjectError{ObjectName: obj.Key, Err: err})
+		}
+	}
+
+	ch := make(chan minio.RemoveObjectError, len(errs))
+	for _, err := range errs {
+		ch <- err
+	}
+	close(ch)
+	return ch
+}
+
+func (m *Memory) CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.Objects[src.Object]
+	if !ok {
+		return minio.UploadInfo{}, fmt.Errorf("object %s not found", src.Object)
+	}
+	m.Objects[dst.Object] = data
+	return minio.UploadInfo{Key: dst.Object, Size: int64(len(data))}, nil
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// ObjectEvent is an object creation or removal reported by a bucket notification.
+type ObjectEvent struct {
+	// Name is the S3 event name (e.g. s3:ObjectCreated:Put).
+	Name string `json:"name"`
+	// Bucket is the bucket of the object.
+	Bucket string `json:"bucket"`
+	// Key is the decoded object key.
+	Key string `json:"key"`
+	// Removed is true if the object was removed, false if it was created or replaced.
+	Removed bool `json:"removed"`
+}
+
+// ParseNotification parses the standard S3 event JSON posted by bucket notification
+// webhooks (MinIO, S3). Events other than object creation and removal are skipped.
+func ParseNotification(body []byte) ([]ObjectEvent, error) {
+	var info notification.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+
+	events := make([]ObjectEvent, 0, len(info.Records))
+	for _, record := range info.Records {
+		var removed bool
+		switch {
+		case strings.HasPrefix(record.EventName, "s3:ObjectCreated:"):
+		case strings.HasPrefix(record.EventName, "s3:ObjectRemoved:"):
+			removed = true
+		default:
+			continue
+		}
+
+		// Keys are URL-encoded in event records
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object key %q: %w", record.S3.Object.Key, err)
+		}
+
+		events = append(events, ObjectEvent{
+			Name:    record.EventName,
+			Bucket:  record.S3.Bucket.Name,
+			Key:     key,
+			Removed: removed,
+		})
+	}
+	return events, nil
+}
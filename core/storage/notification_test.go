@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotification(t *testing.T) {
+	body := `{
+		"EventName": "s3:ObjectCreated:Put",
+		"Key": "assets/bundled/furniture/chair.nitro",
+		"Records": [
+			{"eventName": "s3:ObjectCreated:Put", "s3": {"bucket": {"name": "assets"}, "object": {"key": "bundled/furniture/chair+polyfon.nitro"}}},
+			{"eventName": "s3:ObjectRemoved:Delete", "s3": {"bucket": {"name": "assets"}, "object": {"key": "bundled/furniture/sofa%2A1.nitro"}}},
+			{"eventName": "s3:ObjectAccessed:Get", "s3": {"bucket": {"name": "assets"}, "object": {"key": "gamedata/FurnitureData.json"}}}
+		]
+	}`
+
+	events, err := ParseNotification([]byte(body))
+	require.NoError(t, err)
+	assert.Equal(t, []ObjectEvent{
+		{Name: "s3:ObjectCreated:Put", Bucket: "assets", Key: "bundled/furniture/chair polyfon.nitro"},
+		{Name: "s3:ObjectRemoved:Delete", Bucket: "assets", Key: "bundled/furniture/sofa*1.nitro", Removed: true},
+	}, events)
+
+	_, err = ParseNotification([]byte("not json"))
+	assert.Error(t, err)
+}
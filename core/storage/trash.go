@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// TrashPrefix is the storage prefix holding soft-deleted objects.
+	TrashPrefix = ".trash"
+
+	// TrashBatchLayout is the time layout used to name trash batches. Batch names append
+	// a random suffix to the time, so purges within the same second get their own batch.
+	TrashBatchLayout = "20060102T150405Z"
+
+	// trashConcurrency bounds the number of concurrent copy operations.
+	trashConcurrency = 16
+)
+
+// TrashedObject describes a soft-deleted object held in the trash.
+type TrashedObject struct {
+	// Batch names the purge that trashed the object, starting with its timestamp.
+	Batch string `json:"batch"`
+	// Key is the original object key.
+	Key string `json:"key"`
+	// TrashKey is the object key inside the trash.
+	TrashKey string `json:"trash_key"`
+	// Size is the object size in bytes.
+	Size int64 `json:"size"`
+	// TrashedAt is the time the object was trashed.
+	TrashedAt time.Time `json:"trashed_at"`
+	// ExpiresAt is the time after which the object may be permanently removed.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Trash copies objects to .trash/<batch>/<original key> before they are removed,
+// so an overly aggressive purge can be reverted within the retention period.
+type Trash struct {
+	client    Client
+	bucket    string
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewTrash creates a trash for the given bucket with the given retention period.
+func NewTrash(client Client, bucket string, retention time.Duration) *Trash {
+	return &Trash{
+		client:    client,
+		bucket:    bucket,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Retention returns the configured retention period.
+func (t *Trash) Retention() time.Duration {
+	return t.retention
+}
+
+// Put copies the given objects into a new trash batch and returns the batch name.
+// The originals are left untouched; callers remove them once Put succeeds.
+func (t *Trash) Put(ctx context.Context, objectKeys []string) (string, error) {
+	batch := t.now().UTC().Format(TrashBatchLayout) + "-" + uuid.NewString()[:8]
+	if len(objectKeys) == 0 {
+		return batch, nil
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(trashConcurrency)
+
+	for _, key := range objectKeys {
+		g.Go(func() error {
+			_, err := t.client.CopyObject(gctx,
+				minio.CopyDestOptions{Bucket: t.bucket, Object: TrashKey(batch, key)},
+				minio.CopySrcOptions{Bucket: t.bucket, Object: key},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to trash %s: %w", key, err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+
+	return batch, nil
+}
+
+// List returns every trashed object, oldest batch first.
+func (t *Trash) List(ctx context.Context) ([]TrashedObject, error) {
+	return t.list(ctx, TrashPrefix+"/")
+}
+
+// Restore copies the objects of a batch back to their original keys and removes
+// them from the trash. If keyPrefix is not empty, only objects whose original key
+// starts with it are restored. Returns the number of restored objects.
+func (t *Trash) Restore(ctx context.Context, batch, keyPrefix string) (int, error) {
+	objects, err := t.list(ctx, TrashPrefix+"/"+batch+"/")
+	if err != nil {
+		return 0, err
+	}
+
+	var selected []TrashedObject
+	for _, obj := range objects {
+		if strings.HasPrefix(obj.Key, keyPrefix) {
+			selected = append(selected, obj)
+		}
+	}
+
+	if len(selected) == 0 {
+		return 0, fmt.Errorf("no trashed objects found in batch %s", batch)
+	}
+
+	var (
+		mu       sync.Mutex
+		restored []TrashedObject
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(trashConcurrency)
+
+	for _, obj := range selected {
+		g.Go(func() error {
+			_, err := t.client.CopyObject(gctx,
+				minio.CopyDestOptions{Bucket: t.bucket, Object: obj.Key},
+				minio.CopySrcOptions{Bucket: t.bucket, Object: obj.TrashKey},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", obj.Key, err)
+			}
+			mu.Lock()
+			restored = append(restored, obj)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	copyErr := g.Wait()
+
+	if _, err := t.remove(ctx, restored); err != nil {
+		return len(restored), err
+	}
+
+	return len(restored), copyErr
+}
+
+// Empty permanently removes trashed objects whose retention period has expired.
+// If all is true, every trashed object is removed regardless of its age.
+// Returns the number of removed objects, including when some removals fail.
+func (t *Trash) Empty(ctx context.Context, all bool) (int, error) {
+	objects, err := t.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := t.now()
+	var expired []TrashedObject
+	for _, obj := range objects {
+		if all || !now.Before(obj.ExpiresAt) {
+			expired = append(expired, obj)
+		}
+	}
+
+	return t.remove(ctx, expired)
+}
+
+// list returns the trashed objects under the given prefix, oldest batch first.
+func (t *Trash) list(ctx context.Context, prefix string) ([]TrashedObject, error) {
+	var objects []TrashedObject
+
+	opts := minio.ListObjectsOptions{Prefix: prefix, Recursive: true}
+	for obj := range t.client.ListObjects(ctx, t.bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list trash: %w", obj.Err)
+		}
+
+		batch, key, ok := parseTrashKey(obj.Key)
+		if !ok {
+			continue
+		}
+
+		// The batch name starts with its time, followed by a random suffix
+		stamp, _, _ := strings.Cut(batch, "-")
+		trashedAt, err := time.Parse(TrashBatchLayout, stamp)
+		if err != nil {
+			continue
+		}
+
+		objects = append(objects, TrashedObject{
+			Batch:     batch,
+			Key:       key,
+			TrashKey:  obj.Key,
+			Size:      obj.Size,
+			TrashedAt: trashedAt,
+			ExpiresAt: trashedAt.Add(t.retention),
+		})
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].Batch != objects[j].Batch {
+			return objects[i].Batch < objects[j].Batch
+		}
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+// remove deletes the given trashed objects using the batch API and returns the number
+// of objects removed.
+func (t *Trash) remove(ctx context.Context, objects []TrashedObject) (int, error) {
+	if len(objects) == 0 {
+		return 0, nil
+	}
+
+	objectsCh := make(chan minio.ObjectInfo, len(objects))
+	for _, obj := range objects {
+		objectsCh <- minio.ObjectInfo{Key: obj.TrashKey}
+	}
+	close(objectsCh)
+
+	var errors []string
+	for err := range t.client.RemoveObjects(ctx, t.bucket, objectsCh, minio.RemoveObjectsOptions{}) {
+		if err.Err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", err.ObjectName, err.Err))
+		}
+	}
+
+	removed := len(objects) - len(errors)
+	if len(errors) > 0 {
+		return removed, fmt.Errorf("trash removal had %d errors: %v", len(errors), errors)
+	}
+
+	return removed, nil
+}
+
+// TrashKey returns the trash object key for an original key in a batch.
+func TrashKey(batch, key string) string {
+	return TrashPrefix + "/" + batch + "/" + key
+}
+
+// parseTrashKey splits a trash object key into its batch and original key.
+func parseTrashKey(objectKey string) (batch, key string, ok bool) {
+	rest, found := strings.CutPrefix(objectKey, TrashPrefix+"/")
+	if !found {
+		return "", "", false
+	}
+
+	batch, key, found = strings.Cut(rest, "/")
+	if !found || batch == "" || key == "" {
+		return "", "", false
+	}
+
+	return batch, key, true
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrash_PutAndList(t *testing.T) {
+	client := mocks.NewMemory("bundled/furniture/chair.nitro", "bundled/furniture/table.nitro")
+	trash := NewTrash(client, "bucket", 24*time.Hour)
+	trash.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	batch, err := trash.Put(context.Background(), []string{"bundled/furniture/chair.nitro", "bundled/furniture/table.nitro"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(batch, "20250102T030405Z-"), batch)
+	assert.Contains(t, client.Objects, ".trash/"+batch+"/bundled/furniture/chair.nitro")
+	assert.Contains(t, client.Objects, "bundled/furniture/chair.nitro", "Originals should be left untouched")
+
+	objects, err := trash.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "bundled/furniture/chair.nitro", objects[0].Key)
+	assert.Equal(t, batch, objects[0].Batch)
+	assert.Equal(t, time.Date(2025, 1, 3, 3, 4, 5, 0, time.UTC), objects[0].ExpiresAt)
+
+	// Purges within the same second get their own batch
+	second, err := trash.Put(context.Background(), []string{"bundled/furniture/chair.nitro"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, batch, second)
+
+	objects, err = trash.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, objects, 3)
+
+	// Missing objects fail the batch
+	_, err = trash.Put(context.Background(), []string{"bundled/furniture/missing.nitro"})
+	assert.Error(t, err)
+}
+
+func TestTrash_Restore(t *testing.T) {
+	client := mocks.NewMemory(
+		".trash/20250102T030405Z/bundled/furniture/chair.nitro",
+		".trash/20250102T030405Z/bundled/furniture/table.nitro",
+		".trash/20250102T030405Z/bundled/pets/dog.nitro",
+	)
+	trash := NewTrash(client, "bucket", 24*time.Hour)
+
+	restored, err := trash.Restore(context.Background(), "20250102T030405Z", "bundled/furniture/")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restored)
+
+	assert.Contains(t, client.Objects, "bundled/furniture/chair.nitro")
+	assert.Contains(t, client.Objects, "bundled/furniture/table.nitro")
+	assert.NotContains(t, client.Objects, ".trash/20250102T030405Z/bundled/furniture/chair.nitro")
+	assert.Contains(t, client.Objects, ".trash/20250102T030405Z/bundled/pets/dog.nitro", "Objects outside the prefix stay trashed")
+
+	_, err = trash.Restore(context.Background(), "20240101T000000Z", "")
+	assert.Error(t, err)
+}
+
+func TestTrash_Empty(t *testing.T) {
+	client := mocks.NewMemory(
+		".trash/20250101T000000Z/old.nitro",
+		".trash/20250102T120000Z/recent.nitro",
+		"bundled/furniture/kept.nitro",
+	)
+	trash := NewTrash(client, "bucket", 24*time.Hour)
+	trash.now = func() time.Time { return time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC) }
+
+	removed, err := trash.Empty(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NotContains(t, client.Objects, ".trash/20250101T000000Z/old.nitro")
+	assert.Contains(t, client.Objects, ".trash/20250102T120000Z/recent.nitro")
+
+	removed, err = trash.Empty(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Contains(t, client.Objects, "bundled/furniture/kept.nitro", "Objects outside the trash are never removed")
+}
+
+func TestTrash_Empty_PartialFailure(t *testing.T) {
+	client := mocks.NewMemory(
+		".trash/20250101T000000Z/a.nitro",
+		".trash/20250101T000000Z/b.nitro",
+		".trash/20250101T000000Z/c.nitro",
+	)
+	client.RemoveErrs = map[string]error{".trash/20250101T000000Z/b.nitro": fmt.Errorf("access denied")}
+	trash := NewTrash(client, "bucket", 24*time.Hour)
+
+	removed, err := trash.Empty(context.Background(), true)
+	assert.Error(t, err)
+	assert.Equal(t, 2, removed, "Objects removed before the failure are counted")
+	assert.Equal(t, map[string][]byte{".trash/20250101T000000Z/b.nitro": []byte(".trash/20250101T000000Z/b.nitro")}, client.Objects)
+}
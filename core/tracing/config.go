@@ -0,0 +1,16 @@
+package tracing
+
+// Config holds configuration for OpenTelemetry tracing.
+type Config struct {
+	// Enabled records spans and exports them to the OTLP endpoint.
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint string `mapstructure:"endpoint" default:"localhost:4318"`
+	// Insecure exports over plain HTTP instead of HTTPS.
+	Insecure bool `mapstructure:"insecure" default:"true"`
+	// ServiceName is the service.name resource attribute of the spans.
+	ServiceName string `mapstructure:"service_name" default:"asset-manager"`
+	// SampleRatio is the fraction of new traces recorded (0 to 1). Traces started
+	// upstream follow the sampling decision of their traceparent.
+	SampleRatio float64 `mapstructure:"sample_ratio" default:"1"`
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"errors"
+
+	"asset-manager/core/database"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// dbSpanKey holds the span of a query in the GORM statement settings.
+const dbSpanKey = "tracing:span"
+
+// InstrumentDB registers GORM callbacks recording a client span for every query
+// (create, query, update, delete, row, raw) as a child of the span carried by the
+// statement context (db.WithContext). Record-not-found results are not errors.
+func (t *Tracing) InstrumentDB(db *gorm.DB) error {
+	before := func(operation string, tx *gorm.DB) {
+		_, span := t.tracer.Start(tx.Statement.Context, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameKey.String(tx.Dialector.Name()),
+				semconv.DBOperationName(operation),
+			),
+		)
+		tx.InstanceSet(dbSpanKey, span)
+	}
+
+	after := func(operation string, tx *gorm.DB) {
+		value, ok := tx.InstanceGet(dbSpanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		defer span.End()
+
+		if tx.Statement.Table != "" {
+			span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+		}
+		span.SetAttributes(
+			semconv.DBQueryText(tx.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(tx.Error)
+			span.SetStatus(codes.Error, tx.Error.Error())
+		}
+	}
+
+	return database.RegisterCallbacks(db, "tracing", before, after)
+}
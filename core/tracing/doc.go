@@ -0,0 +1,34 @@
+// Package tracing records OpenTelemetry spans of the HTTP server, storage and database
+// and exports them over OTLP/HTTP.
+//
+// # Propagation
+//
+// Middleware continues the trace of an incoming W3C traceparent header. Without one, a
+// new trace is started whose trace ID is the Ray ID of the request, so a trace can be
+// found from the X-Ray-ID response header. The server span is carried by
+// c.UserContext() and rayid.Context, parenting every span started by the request.
+//
+// # Spans
+//
+//   - GET /furniture/:identifier: a server span per request, named by route pattern.
+//   - storage.<Method>: a client span per storage call (see storage.Instrument).
+//   - db.<operation>: a client span per GORM query made with db.WithContext.
+//   - reconcile.build, reconcile.load_*, reconcile.plan, reconcile.apply: recorded by
+//     core/reconcile once given the provider with reconcile.SetTracerProvider.
+//
+// # Configuration
+//
+// TRACING_ENABLED (default false) records spans and exports them to TRACING_ENDPOINT
+// (default localhost:4318). TRACING_SAMPLE_RATIO samples new traces.
+//
+// # Usage
+//
+//	tr, err := tracing.New(ctx, cfg.Tracing)
+//	app.Use(rayid.New(), tr.Middleware())
+//	client = storage.Wrap(client, storage.Instrument(tr))
+//	_ = tr.InstrumentDB(db)
+//	reconcile.SetTracerProvider(tr.Provider())
+//	defer tr.Shutdown(ctx)
+//
+// Tests export to memory with NewWithExporter and tracetest.NewInMemoryExporter.
+package tracing
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"asset-manager/core/middleware/rayid"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the trace of an
+// incoming W3C traceparent header or, without one, a trace whose ID is the Ray ID.
+// It must run after the rayid middleware. The span is carried by c.UserContext(),
+// and so by rayid.Context, to parent the storage, database and reconcile spans.
+func (t *Tracing) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := t.propagator.Extract(rayid.Context(c), headerCarrier{c})
+
+		ctx, span := t.tracer.Start(ctx, c.Method(), trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// Errors returned to Fiber are turned into responses after the middleware chain
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+			span.RecordError(err)
+		}
+
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRequestMethodKey.String(c.Method()),
+			semconv.HTTPRoute(route),
+			semconv.URLPath(c.Path()),
+			semconv.HTTPResponseStatusCode(status),
+			attribute.String("ray_id", rayid.Get(c)),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fiber.ErrInternalServerError.Message)
+		}
+
+		return err
+	}
+}
+
+// headerCarrier adapts the request headers to propagation.TextMapCarrier.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get implements propagation.TextMapCarrier.
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+// Set implements propagation.TextMapCarrier.
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+// Keys implements propagation.TextMapCarrier.
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+
+	"asset-manager/core/middleware/rayid"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rayIDGenerator derives the trace ID of new traces from the Ray ID carried by the
+// context, so a request without a traceparent header can be found by its X-Ray-ID.
+// Other IDs are random.
+type rayIDGenerator struct{}
+
+// NewIDs implements sdktrace.IDGenerator.
+func (g rayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if rid, err := uuid.Parse(rayid.FromContext(ctx)); err == nil {
+		if traceID := trace.TraceID(rid); traceID.IsValid() {
+			return traceID, g.NewSpanID(ctx, traceID)
+		}
+	}
+
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		binary.BigEndian.PutUint64(traceID[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(traceID[8:], rand.Uint64())
+	}
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+// NewSpanID implements sdktrace.IDGenerator.
+func (rayIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		binary.BigEndian.PutUint64(spanID[:], rand.Uint64())
+	}
+	return spanID
+}
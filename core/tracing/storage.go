@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"asset-manager/core/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ObserveCall implements storage.CallObserver, recording a client span for every
+// storage call as a child of the span carried by the call context.
+// Register it with storage.Instrument.
+func (t *Tracing) ObserveCall(ctx context.Context, call storage.Call) {
+	end := time.Now()
+	_, span := t.tracer.Start(ctx, "storage."+call.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-call.Duration)),
+		trace.WithAttributes(
+			attribute.String("storage.method", call.Method),
+			attribute.String("storage.bucket", call.Bucket),
+		),
+	)
+
+	if call.Object != "" {
+		span.SetAttributes(attribute.String("storage.object", call.Object))
+	}
+	if call.Bytes > 0 {
+		span.SetAttributes(attribute.Int64("storage.bytes", call.Bytes))
+	}
+	if call.Objects > 0 {
+		span.SetAttributes(attribute.Int("storage.objects", call.Objects))
+	}
+	if call.Err != nil {
+		span.RecordError(call.Err)
+		span.SetStatus(codes.Error, call.Err.Error())
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestTracing returns a tracer exporting to memory.
+func newTestTracing(t *testing.T) (*Tracing, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tr := NewWithExporter(Config{ServiceName: "test", SampleRatio: 1}, exporter)
+	t.Cleanup(func() { _ = tr.Shutdown(context.Background()) })
+	return tr, exporter
+}
+
+// exported flushes the tracer and returns the spans by name.
+func exported(t *testing.T, tr *Tracing, exporter *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	require.NoError(t, tr.ForceFlush(context.Background()))
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+// attr returns the value of an attribute of a span.
+func attr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// newTestApp returns an app tracing GET /furniture/:identifier, which reads one object.
+func newTestApp(tr *Tracing, client storage.Client) *fiber.App {
+	store := storage.Wrap(client, storage.Instrument(tr))
+
+	app := fiber.New()
+	app.Use(rayid.New())
+	app.Use(tr.Middleware())
+	app.Get("/furniture/:identifier", func(c *fiber.Ctx) error {
+		if _, err := store.BucketExists(rayid.Context(c), "assets"); err != nil {
+			return fiber.NewError(fiber.StatusBadGateway, err.Error())
+		}
+		return c.SendString("ok")
+	})
+	return app
+}
+
+func TestMiddleware_TraceParent(t *testing.T) {
+	tr, exporter := newTestTracing(t)
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+
+	req := httptest.NewRequest("GET", "/furniture/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := newTestApp(tr, client).Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	spans := exported(t, tr, exporter)
+	server := spans["GET /furniture/:identifier"]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, "/furniture/:identifier", attr(server, "http.route").AsString())
+	assert.Equal(t, int64(200), attr(server, "http.response.status_code").AsInt64())
+	assert.Equal(t, resp.Header.Get(rayid.HeaderKey), attr(server, "ray_id").AsString())
+
+	// Storage calls are children of the handler span
+	call := spans["storage.BucketExists"]
+	assert.Equal(t, server.SpanContext.SpanID(), call.Parent.SpanID())
+	assert.Equal(t, "assets", attr(call, "storage.bucket").AsString())
+}
+
+func TestMiddleware_RayIDFallback(t *testing.T) {
+	tr, exporter := newTestTracing(t)
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(false, errors.New("unreachable"))
+
+	resp, err := newTestApp(tr, client).Test(httptest.NewRequest("GET", "/furniture/1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 502, resp.StatusCode)
+
+	// Without a traceparent, the trace ID is the Ray ID
+	spans := exported(t, tr, exporter)
+	server := spans["GET /furniture/:identifier"]
+	rid := strings.ReplaceAll(resp.Header.Get(rayid.HeaderKey), "-", "")
+	assert.Equal(t, rid, server.SpanContext.TraceID().String())
+	assert.False(t, server.Parent.IsValid())
+	assert.Equal(t, codes.Error, server.Status.Code)
+
+	call := spans["storage.BucketExists"]
+	assert.Equal(t, codes.Error, call.Status.Code)
+	assert.Equal(t, "unreachable", call.Status.Description)
+}
+
+func TestInstrumentDB(t *testing.T) {
+	tr, exporter := newTestTracing(t)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, tr.InstrumentDB(db))
+
+	ctx, parent := tr.tracer.Start(context.Background(), "parent")
+	require.NoError(t, db.WithContext(ctx).Exec("SELECT 1").Error)
+	parent.End()
+	assert.Error(t, db.Exec("SELECT * FROM missing").Error)
+
+	var raw []tracetest.SpanStub
+	require.NoError(t, tr.ForceFlush(context.Background()))
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "db.raw" {
+			raw = append(raw, span)
+		}
+	}
+	require.Len(t, raw, 2)
+	assert.Equal(t, parent.SpanContext().SpanID(), raw[0].Parent.SpanID())
+	assert.Equal(t, "sqlite", attr(raw[0], "db.system.name").AsString())
+	assert.Equal(t, "SELECT 1", attr(raw[0], "db.query.text").AsString())
+	assert.Equal(t, codes.Error, raw[1].Status.Code)
+}
@@ -20,5 +20,13 @@
 //
 // # HTTP Endpoints
 //
+//   - GET /furniture : List reconcile results from cached indices, filtered by status
+//     (e.g. 'missing_storage'), presence, type and name/classname substring, with sorting,
+//     pagination and per-status counts.
+//   - GET /furniture/search : Search by ID, classname or name with prefix, substring and
+//     typo-tolerant (edit distance) matching, best first.
 //   - GET /furniture/:identifier : Get detailed status for a specific item (e.g. 'f_couch').
+//   - POST /reconcile/furniture/plan : Compute and store a reconcile plan (purge/sync/create options).
+//   - POST /reconcile/furniture/plan/:id/apply : Execute a stored plan once, before it expires and
+//     only if the reconcile indices were not rebuilt since planning.
 package furniture
@@ -1,7 +1,17 @@
 package furniture
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
 	"asset-manager/core/logger"
+	"asset-manager/core/middleware/auth"
+	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
+	"asset-manager/feature/furniture/models"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -19,8 +29,165 @@ func NewHandler(service *Service) *Handler {
 
 // RegisterRoutes registers the furniture routes.
 func (h *Handler) RegisterRoutes(app fiber.Router) {
+	read := auth.Require(auth.ScopeRead)
+
 	group := app.Group("/furniture")
-	group.Get("/:identifier", h.HandleGetFurnitureDetail)
+	group.Get("/", read, h.HandleListFurniture)
+	group.Get("/search", read, h.HandleSearchFurniture)
+	group.Get("/:identifier", read, h.HandleGetFurnitureDetail)
+
+	rec := app.Group("/reconcile/furniture")
+	rec.Get("/cache", read, h.HandleCacheStats)
+	rec.Post("/plan", read, h.HandlePlanReconcile)
+	rec.Post("/plan/:id/apply", auth.Require(auth.ScopeReconcileApply), h.HandleApplyReconcile)
+}
+
+// HandleCacheStats returns the metrics of the cached furniture indices.
+// @Summary Furniture Cache Stats
+// @Description Returns the age of the indices behind GET /furniture and GET /furniture/search, whether a background rebuild is running, and build counts and durations.
+// @Tags reconcile
+// @Produce json
+// @Success 200 {object} reconcile.CacheStats "Cache Stats"
+// @Router /reconcile/furniture/cache [get]
+func (h *Handler) HandleCacheStats(c *fiber.Ctx) error {
+	return c.JSON(h.service.CacheStats())
+}
+
+// HandleSearchFurniture finds furniture by ID, classname or name.
+// @Summary Search Furniture
+// @Description Matches the query against IDs, classnames and names by exact, prefix, substring and edit distance (typo-tolerant) matching, best first. Uses the same cached indices as GET /furniture.
+// @Tags furniture
+// @Produce json
+// @Param q query string true "Search text (e.g. 'chair_polyf')"
+// @Param limit query int false "Maximum hits (max 100)" default(20)
+// @Success 200 {object} models.SearchResponse "Search Hits"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 503 {object} map[string]string "Database Unavailable"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture/search [get]
+func (h *Handler) HandleSearchFurniture(c *fiber.Ctx) error {
+	const maxLimit = 100
+
+	l := logger.WithRayID(h.service.logger, c)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "q is required"})
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > maxLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxLimit)})
+	}
+
+	hits, err := h.service.SearchFurniture(rayid.Context(c), query, limit)
+	if err != nil {
+		if errors.Is(err, ErrNoDatabase) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
+		l.Error("Furniture search failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(models.SearchResponse{Query: query, Hits: hits})
+}
+
+// listSorts are the fields furniture listings can be sorted by.
+var listSorts = []string{"id", "name", "classname", "type"}
+
+// HandleListFurniture returns a filtered, paginated page of furniture reconcile results.
+// @Summary List Furniture
+// @Description Lists furniture reconcile results from the cached indices (rebuilt after RECONCILE_CACHE_TTL_SECONDS). Filters combine with AND; comma-separated values within a filter combine with OR.
+// @Tags furniture
+// @Produce json
+// @Param status query string false "Statuses: complete, mismatch, missing_db, missing_gamedata, missing_storage (comma-separated)"
+// @Param presence query string false "Exact presence patterns, e.g. 'storage' or 'db+gamedata' (comma-separated)"
+// @Param q query string false "Case-insensitive substring of the ID, name or classname"
+// @Param type query string false "Item type: 's' (floor) or 'i' (wall)"
+// @Param sort query string false "Sort field: id, name, classname or type; prefix with '-' for descending" default(id)
+// @Param page query int false "Page number (1-based)" default(1)
+// @Param limit query int false "Page size (max 500)" default(50)
+// @Success 200 {object} reconcile.ListPage "Furniture Page"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 503 {object} map[string]string "Database Unavailable"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /furniture [get]
+func (h *Handler) HandleListFurniture(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := h.service.ListFurniture(rayid.Context(c), filter)
+	if err != nil {
+		if errors.Is(err, ErrNoDatabase) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
+		l.Error("Furniture listing failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(page)
+}
+
+// parseListFilter builds a listing filter from the query string.
+func parseListFilter(c *fiber.Ctx) (reconcile.ListFilter, error) {
+	const (
+		defaultLimit = 50
+		maxLimit     = 500
+	)
+
+	filter := reconcile.ListFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Page:  c.QueryInt("page", 1),
+		Limit: c.QueryInt("limit", defaultLimit),
+	}
+
+	if filter.Page < 1 {
+		return filter, fmt.Errorf("page must be at least 1")
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		return filter, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+
+	if raw := c.Query("status"); raw != "" {
+		statuses, err := reconcile.ParseStatuses(strings.Split(raw, ","))
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = statuses
+	}
+
+	if raw := c.Query("presence"); raw != "" {
+		for _, pattern := range strings.Split(raw, ",") {
+			p, err := reconcile.ParsePresence(pattern)
+			if err != nil {
+				return filter, err
+			}
+			filter.Presence = append(filter.Presence, p)
+		}
+	}
+
+	switch itemType := c.Query("type"); itemType {
+	case "":
+	case "s", "i":
+		filter.Metadata = map[string]string{"type": itemType}
+	default:
+		return filter, fmt.Errorf("unknown type %q (expected s or i)", itemType)
+	}
+
+	sortField := c.Query("sort", "id")
+	if strings.HasPrefix(sortField, "-") {
+		filter.Desc = true
+		sortField = strings.TrimPrefix(sortField, "-")
+	}
+	if !slices.Contains(listSorts, sortField) {
+		return filter, fmt.Errorf("unknown sort %q (expected %s)", sortField, strings.Join(listSorts, ", "))
+	}
+	filter.Sort = sortField
+
+	return filter, nil
 }
 
 // HandleGetFurnitureDetail returns a detailed report for a single furniture item.
@@ -31,6 +198,7 @@ func (h *Handler) RegisterRoutes(app fiber.Router) {
 // @Produce json
 // @Param identifier path string true "Furniture Identifier (e.g. 'f_couch')"
 // @Success 200 {object} models.FurnitureDetailReport "Furniture Detail"
+// @Failure 503 {object} map[string]string "Database Unavailable"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /furniture/{identifier} [get]
 func (h *Handler) HandleGetFurnitureDetail(c *fiber.Ctx) error {
@@ -39,6 +207,9 @@ func (h *Handler) HandleGetFurnitureDetail(c *fiber.Ctx) error {
 
 	report, err := h.service.GetFurnitureDetail(c.Context(), identifier)
 	if err != nil {
+		if errors.Is(err, ErrNoDatabase) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
 		l.Error("Furniture detail check failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -47,3 +218,114 @@ func (h *Handler) HandleGetFurnitureDetail(c *fiber.Ctx) error {
 
 	return c.JSON(report)
 }
+
+// HandlePlanReconcile computes a furniture reconcile plan and stores it for confirmation.
+// @Summary Plan Furniture Reconcile
+// @Description Computes the actions 'reconcile furniture' would execute with the given options. Nothing is mutated; apply the returned plan_id to execute it before it expires.
+// @Tags reconcile
+// @Accept json
+// @Produce json
+// @Param request body models.ReconcilePlanRequest true "Reconcile options"
+// @Success 200 {object} models.ReconcilePlanResponse "Stored Plan"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 503 {object} map[string]string "Database Unavailable"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reconcile/furniture/plan [post]
+func (h *Handler) HandlePlanReconcile(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+
+	var req models.ReconcilePlanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+		}
+	}
+
+	stored, err := h.service.PlanReconcile(rayid.Context(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidPlanRequest):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, ErrNoDatabase):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		default:
+			l.Error("Reconcile planning failed", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	l.Info("Reconcile plan stored",
+		zap.String("plan_id", stored.ID),
+		zap.Int("actions", len(stored.Plan.Actions)),
+		zap.String("blocked_reason", stored.Plan.Summary.BlockedReason),
+	)
+
+	return c.JSON(models.ReconcilePlanResponse{
+		PlanID:    stored.ID,
+		ExpiresAt: stored.ExpiresAt,
+		Summary:   stored.Plan.Summary,
+		Actions:   stored.Plan.Actions,
+	})
+}
+
+// HandleApplyReconcile executes a stored furniture reconcile plan.
+// @Summary Apply Furniture Reconcile Plan
+// @Description Executes a plan returned by POST /reconcile/furniture/plan. A plan can be applied once, and is refused if it expired or the indices were rebuilt since planning. Mutations are journaled for 'reconcile undo'.
+// @Tags reconcile
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} models.ReconcileApplyResponse "Apply Result"
+// @Failure 404 {object} map[string]string "Plan Not Found"
+// @Failure 409 {object} map[string]string "Stale Plan"
+// @Failure 403 {object} map[string]string "Requires reconcile:apply Scope"
+// @Failure 410 {object} map[string]string "Plan Expired"
+// @Failure 422 {object} map[string]string "Safety Threshold Exceeded"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Shutting Down or Interrupted by Shutdown"
+// @Router /reconcile/furniture/plan/{id}/apply [post]
+func (h *Handler) HandleApplyReconcile(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.service.logger, c)
+	id := c.Params("id")
+
+	// The request context is cancelled as soon as the server shuts down; the run must
+	// instead drain within the shutdown deadline (see reconcile.Drain), so only its
+	// values (Ray ID, span) are kept
+	ctx := context.WithoutCancel(rayid.Context(c))
+
+	resp, err := h.service.ApplyReconcile(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, reconcile.ErrPlanNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, reconcile.ErrStalePlan):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, reconcile.ErrPlanExpired):
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, reconcile.ErrSafetyThreshold):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, reconcile.ErrShuttingDown):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		l.Error("Reconcile apply failed", zap.String("plan_id", id), zap.Error(err))
+		body := fiber.Map{"error": err.Error()}
+		if resp != nil {
+			body["executed"] = resp.Executed
+			body["journal_id"] = resp.JournalID
+		}
+		// Stopped midway by a shutdown: the executed actions can be undone with the journal
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, reconcile.ErrInterrupted) {
+			status = fiber.StatusServiceUnavailable
+		}
+		return c.Status(status).JSON(body)
+	}
+
+	l.Info("Reconcile plan applied",
+		zap.String("plan_id", id),
+		zap.Int("executed", resp.Executed),
+		zap.String("journal_id", resp.JournalID),
+	)
+
+	return c.JSON(resp)
+}
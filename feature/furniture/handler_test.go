@@ -1,18 +1,33 @@
 package furniture
 
 import (
+	"asset-manager/core/middleware/auth"
+	"asset-manager/core/middleware/rayid"
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
 	"asset-manager/core/storage/mocks"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func setupTestApp(h *Handler) (*fiber.App, *mocks.Client, *zap.Logger) {
 	app := fiber.New()
+	app.Use(auth.WithKey(auth.Key{Name: "test", Scopes: []string{auth.ScopeAll}}))
 	h.RegisterRoutes(app)
 	return app, nil, nil
 }
@@ -36,3 +51,172 @@ func TestHandler_HandleGetFurnitureDetail(t *testing.T) {
 	// It should fail because BucketExists fails, returning 500
 	assert.Equal(t, 500, resp.StatusCode)
 }
+
+func TestHandler_ReconcilePlanFlow(t *testing.T) {
+	mockClient := new(mocks.Client)
+	db, _ := setupMockDB(t)
+	svc := NewService(mockClient, "test-bucket", zap.NewNop(), db, "arcturus")
+	app, _, _ := setupTestApp(NewHandler(svc))
+
+	// Invalid scope is rejected before touching any source
+	req := httptest.NewRequest("POST", "/reconcile/furniture/plan", strings.NewReader(`{"purge":true,"purge_from":["bundle"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	// Unknown plans cannot be applied
+	resp, err = app.Test(httptest.NewRequest("POST", "/reconcile/furniture/plan/unknown/apply", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	// Stale plans are refused without being consumed, so retrying reports the same error
+	stale := svc.plans.Save(&reconcile.ReconcilePlan{CacheBuilt: time.Now()}, reconcile.ReconcileOptions{})
+	for range 2 {
+		resp, err = app.Test(httptest.NewRequest("POST", "/reconcile/furniture/plan/"+stale.ID+"/apply", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	}
+
+	// Planning without a database is unavailable
+	app, _, _ = setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), nil, "arcturus")))
+	resp, err = app.Test(httptest.NewRequest("POST", "/reconcile/furniture/plan", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+}
+
+func TestHandler_HandleListFurniture_Validation(t *testing.T) {
+	mockClient := new(mocks.Client)
+	db, _ := setupMockDB(t)
+	app, _, _ := setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), db, "arcturus")))
+
+	// Invalid filters are rejected before touching any source
+	for _, query := range []string{"status=broken", "presence=bundle", "type=x", "sort=size", "page=0", "limit=1000"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/furniture?"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, query)
+	}
+
+	// Listing without a database is unavailable
+	app, _, _ = setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), nil, "arcturus")))
+	resp, err := app.Test(httptest.NewRequest("GET", "/furniture?status=missing_storage&sort=-name", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+}
+
+func TestHandler_HandleSearchFurniture_Validation(t *testing.T) {
+	mockClient := new(mocks.Client)
+	db, _ := setupMockDB(t)
+	app, _, _ := setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), db, "arcturus")))
+
+	for _, query := range []string{"", "q=+", "q=chair&limit=0", "q=chair&limit=101"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/furniture/search?"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, query)
+	}
+
+	// Searching without a database is unavailable
+	app, _, _ = setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), nil, "arcturus")))
+	resp, err := app.Test(httptest.NewRequest("GET", "/furniture/search?q=chair", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+}
+
+func TestHandler_HandleCacheStats(t *testing.T) {
+	mockClient := new(mocks.Client)
+	app, _, _ := setupTestApp(NewHandler(NewService(mockClient, "test-bucket", zap.NewNop(), nil, "arcturus")))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/furniture/cache", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var stats reconcile.CacheStats
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Contains(t, stats.Key, "bundled/furniture")
+}
+
+// blockingAdapter loads empty indices and blocks DB deletions until released.
+type blockingAdapter struct {
+	*furnitureAdp.FurnitureAdapter
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]reconcile.DBItem, error) {
+	return map[string]reconcile.DBItem{}, nil
+}
+
+func (a *blockingAdapter) LoadGamedataIndex(ctx context.Context, client storage.Client, bucket, objectName string, paths []string) (map[string]reconcile.GDItem, error) {
+	return map[string]reconcile.GDItem{}, nil
+}
+
+func (a *blockingAdapter) LoadStorageSet(ctx context.Context, client storage.Client, bucket, prefix, extension string) (map[string]struct{}, error) {
+	return map[string]struct{}{}, nil
+}
+
+func (a *blockingAdapter) DeleteDBBatch(ctx context.Context, keys []string) error {
+	close(a.started)
+	<-a.release
+	return ctx.Err()
+}
+
+// TestHandler_ApplyReconcile_Shutdown tests that an HTTP apply in flight when the server
+// shuts down is not cancelled with the request, and completes within the deadline.
+func TestHandler_ApplyReconcile_Shutdown(t *testing.T) {
+	mockClient := new(mocks.Client)
+	mockClient.On("BucketExists", mock.Anything, "test-bucket").Return(true, nil)
+	mockClient.On("PutObject", mock.Anything, "test-bucket", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(minio.UploadInfo{}, nil)
+
+	db, _ := setupMockDB(t)
+	svc := NewService(mockClient, "test-bucket", zap.NewNop(), db, "arcturus")
+	adapter := &blockingAdapter{FurnitureAdapter: svc.adapter, started: make(chan struct{}), release: make(chan struct{})}
+	svc.spec.Adapter = adapter
+	t.Cleanup(func() { reconcile.InvalidateCache(svc.spec) })
+
+	cache, err := reconcile.GetOrBuildCache(context.Background(), svc.spec, db, mockClient, "test-bucket")
+	require.NoError(t, err)
+	stored := svc.plans.Save(&reconcile.ReconcilePlan{
+		CacheBuilt: cache.Built,
+		Actions:    []reconcile.Action{{Type: reconcile.ActionDeleteDB, Key: "1"}},
+	}, reconcile.ReconcileOptions{})
+
+	// The Ray ID middleware makes the request context the server's, done on shutdown
+	app := fiber.New()
+	app.Use(rayid.New())
+	app.Use(auth.WithKey(auth.Key{Name: "test", Scopes: []string{auth.ScopeAll}}))
+	NewHandler(svc).RegisterRoutes(app)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := http.Post("http://"+ln.Addr().String()+"/reconcile/furniture/plan/"+stored.ID+"/apply", "application/json", nil)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+	<-adapter.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- app.ShutdownWithContext(ctx) }()
+
+	// Release the apply once the server stopped accepting connections
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, time.Millisecond)
+	close(adapter.release)
+
+	assert.Equal(t, 200, <-responses)
+	assert.NoError(t, <-shutdown)
+}
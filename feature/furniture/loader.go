@@ -1,6 +1,11 @@
 package furniture
 
 import (
+	"context"
+
+	"asset-manager/core/events"
+	"asset-manager/core/jobs"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 
 	"github.com/gofiber/fiber/v2"
@@ -36,3 +41,43 @@ func (f *Feature) Load(app fiber.Router) error {
 	f.handler.RegisterRoutes(app)
 	return nil
 }
+
+// SetReconcileConfig configures the plan/apply reconcile flow (see Service.SetReconcileConfig).
+func (f *Feature) SetReconcileConfig(safety reconcile.Config, plans reconcile.PlanConfig, trash *storage.Trash) {
+	f.service.SetReconcileConfig(safety, plans, trash)
+}
+
+// SetCacheConfig configures the reconcile index caches (see Service.SetCacheConfig).
+func (f *Feature) SetCacheConfig(cfg reconcile.CacheConfig, snapshots reconcile.SnapshotConfig) {
+	f.service.SetCacheConfig(cfg, snapshots)
+}
+
+// SetDB swaps in the database connection (see Service.SetDB).
+func (f *Feature) SetDB(db *gorm.DB) {
+	f.service.SetDB(db)
+}
+
+// StartCacheRefresher keeps the listing indices warm (see Service.StartCacheRefresher).
+func (f *Feature) StartCacheRefresher(ctx context.Context) {
+	f.service.StartCacheRefresher(ctx)
+}
+
+// HandleStorageEvents implements notify.Sink (see Service.HandleStorageEvents).
+func (f *Feature) HandleStorageEvents(events []storage.ObjectEvent) {
+	f.service.HandleStorageEvents(events)
+}
+
+// SetEvents publishes the progress of reconcile runs to the hub.
+func (f *Feature) SetEvents(hub *events.Hub) {
+	f.service.SetEvents(hub)
+}
+
+// RegisterJobs registers the furniture job types with the job manager.
+func (f *Feature) RegisterJobs(m *jobs.Manager) {
+	m.Register("reconcile_furniture", func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		ctx = reconcile.WithProgress(ctx, func(phase reconcile.Phase, processed, total int) {
+			report(processed, total, string(phase))
+		})
+		return f.service.ReconcileSummary(ctx)
+	})
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"asset-manager/core/reconcile"
+)
+
+// ReconcilePlanRequest selects the actions of a furniture reconcile plan.
+// Fields mirror the flags of the 'reconcile furniture' command.
+type ReconcilePlanRequest struct {
+	// Purge plans deletion of incomplete items.
+	Purge bool `json:"purge"`
+	// PurgeFrom restricts purge to the given sources (db, gamedata, storage).
+	PurgeFrom []string `json:"purge_from,omitempty"`
+	// PurgePresence restricts purge to items matching one of the presence patterns (e.g. "storage", "db+gamedata").
+	PurgePresence []string `json:"purge_presence,omitempty"`
+	// Sync plans updating mismatched DB fields from gamedata.
+	Sync bool `json:"sync"`
+	// CreateMissingDB plans creating DB rows for items present in gamedata and storage.
+	CreateMissingDB bool `json:"create_missing_db"`
+	// CreateMissingGamedata plans creating gamedata entries for items present in DB and storage.
+	CreateMissingGamedata bool `json:"create_missing_gamedata"`
+	// Force overrides the reconcile safety thresholds.
+	Force bool `json:"force"`
+}
+
+// ReconcilePlanResponse is a stored plan awaiting confirmation.
+type ReconcilePlanResponse struct {
+	// PlanID identifies the plan in the apply request.
+	PlanID string `json:"plan_id"`
+	// ExpiresAt is the time after which the plan can no longer be applied.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Summary provides aggregate counts.
+	Summary reconcile.PlanSummary `json:"summary"`
+	// Actions lists the planned mutations.
+	Actions []reconcile.Action `json:"actions"`
+}
+
+// ReconcileApplyResponse reports the outcome of applying a stored plan.
+type ReconcileApplyResponse struct {
+	// PlanID is the applied plan.
+	PlanID string `json:"plan_id"`
+	// Executed is the number of actions executed.
+	Executed int `json:"executed"`
+	// JournalID identifies the journal to pass to 'reconcile undo', if any mutation was recorded.
+	JournalID string `json:"journal_id,omitempty"`
+	// Summary provides aggregate counts of the applied plan.
+	Summary reconcile.PlanSummary `json:"summary"`
+}
+
+// SearchResponse lists the furniture matching a search query, best first.
+type SearchResponse struct {
+	// Query is the searched text.
+	Query string `json:"query"`
+	// Hits are the matching items with how they matched.
+	Hits []reconcile.SearchHit `json:"hits"`
+}
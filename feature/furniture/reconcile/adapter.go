@@ -2,9 +2,12 @@ package reconcile
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +21,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// progressInterval is the number of rows or objects processed between progress reports.
+const progressInterval = 1000
+
 // FurnitureAdapter implements the reconcile.Adapter interface for furniture assets.
 type FurnitureAdapter struct {
 	// classnameToID maps classnames to IDs for storage key resolution
@@ -36,8 +42,14 @@ type FurnitureAdapter struct {
 	serverProfile string
 	gamedataObj   string
 
+	// trash receives copies of purged storage objects (optional)
+	trash *storage.Trash
+
 	// batchConcurrency allows overriding worker count (default 50)
 	batchConcurrency int
+
+	// enrichFromBundles makes gamedata creation read model dimensions from the bundles
+	enrichFromBundles bool
 }
 
 // NewAdapter creates a new furniture adapter.
@@ -65,6 +77,20 @@ func (a *FurnitureAdapter) SetMutationContext(db *gorm.DB, client storage.Client
 	}
 }
 
+// SetTrash enables soft deletion: purged storage objects are copied to the trash
+// before being removed, and journals point at those copies instead of quarantining
+// their own. Pass nil to disable it.
+func (a *FurnitureAdapter) SetTrash(trash *storage.Trash) {
+	a.trash = trash
+}
+
+// SetBundleEnrichment makes CreateGamedataBatch download the .nitro bundle of each
+// created entry to take its model dimensions. Disabled by default, as it downloads
+// every bundle; entries are then built from the DB rows only.
+func (a *FurnitureAdapter) SetBundleEnrichment(enabled bool) {
+	a.enrichFromBundles = enabled
+}
+
 // SetBatchConcurrency sets the number of concurrent workers for batch operations.
 // Set to 1 for sequential execution (useful for SQLite tests).
 func (a *FurnitureAdapter) SetBatchConcurrency(n int) {
@@ -115,11 +141,9 @@ type FurnitureData struct {
 
 // LoadDBIndex loads all furniture items from the database.
 func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]reconcile.DBItem, error) {
-	index := make(map[string]reconcile.DBItem)
-
 	// Handle nil DB
 	if db == nil {
-		return index, nil
+		return make(map[string]reconcile.DBItem), nil
 	}
 
 	profile := GetProfileByName(serverProfile)
@@ -135,6 +159,13 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 	}
 	defer dbRows.Close()
 
+	return scanDBItems(ctx, dbRows, profile)
+}
+
+// scanDBItems parses furniture rows into DB items keyed by sprite_id.
+func scanDBItems(ctx context.Context, dbRows *sql.Rows, profile ServerProfile) (map[string]reconcile.DBItem, error) {
+	index := make(map[string]reconcile.DBItem)
+
 	// Get column names
 	columns, err := dbRows.Columns()
 	if err != nil {
@@ -142,7 +173,12 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 	}
 
 	// Parse rows into DBItem
+	rows := 0
 	for dbRows.Next() {
+		if rows++; rows%progressInterval == 0 {
+			reconcile.ReportProgress(ctx, reconcile.PhaseLoadDB, rows, 0)
+		}
+
 		// Create a map to scan into
 		values := make([]any, len(columns))
 		valuePtrs := make([]any, len(columns))
@@ -210,6 +246,10 @@ func (a *FurnitureAdapter) LoadDBIndex(ctx context.Context, db *gorm.DB, serverP
 		index[key] = item
 	}
 
+	if err := dbRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
 	return index, nil
 }
 
@@ -240,8 +280,14 @@ func (a *FurnitureAdapter) LoadGamedataIndex(ctx context.Context, client storage
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	total := len(furniData.RoomItemTypes.FurniType) + len(furniData.WallItemTypes.FurniType)
+	processed := 0
+
 	// Process room items
 	for _, item := range furniData.RoomItemTypes.FurniType {
+		if processed++; processed%progressInterval == 0 {
+			reconcile.ReportProgress(ctx, reconcile.PhaseLoadGamedata, processed, total)
+		}
 		if item.ID > 0 && item.ClassName != "" {
 			item.Type = "s" // Floor item
 			key := strconv.Itoa(item.ID)
@@ -254,6 +300,9 @@ func (a *FurnitureAdapter) LoadGamedataIndex(ctx context.Context, client storage
 
 	// Process wall items
 	for _, item := range furniData.WallItemTypes.FurniType {
+		if processed++; processed%progressInterval == 0 {
+			reconcile.ReportProgress(ctx, reconcile.PhaseLoadGamedata, processed, total)
+		}
 		if item.ID > 0 && item.ClassName != "" {
 			item.Type = "i" // Wall item
 			key := strconv.Itoa(item.ID)
@@ -299,10 +348,14 @@ func (a *FurnitureAdapter) LoadStorageSet(ctx context.Context, client storage.Cl
 		Recursive: true,
 	}
 
+	listed := 0
 	for obj := range client.ListObjects(ctx, bucket, opts) {
 		if obj.Err != nil {
 			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
 		}
+		if listed++; listed%progressInterval == 0 {
+			reconcile.ReportProgress(ctx, reconcile.PhaseLoadStorage, listed, 0)
+		}
 
 		// Extract key from object
 		if key, ok := a.ExtractStorageKey(obj.Key, prefix, extension); ok {
@@ -378,6 +431,92 @@ func (a *FurnitureAdapter) ExtractStorageKey(objectKey, prefix, extension string
 	return relPathNoExt, true
 }
 
+// LinkStorageKeys maps storage objects of items missing in gamedata to their DB key.
+// Without a gamedata entry ExtractStorageKey cannot resolve the classname to an ID,
+// so the bundle is keyed by its file name. The DB item_name is used instead, with
+// color variants (classname*N) sharing the base bundle. Returns each linked key's bundle.
+func (a *FurnitureAdapter) LinkStorageKeys(dbIndex map[string]reconcile.DBItem, gdIndex map[string]reconcile.GDItem, storageSet map[string]struct{}) (map[string]struct{}, map[string]string) {
+	linked := maps.Clone(storageSet)
+	links := make(map[string]string)
+	for key, item := range dbIndex {
+		if _, ok := gdIndex[key]; ok {
+			continue
+		}
+
+		bundle := bundleName(item.(DBItem).ItemName)
+		if bundle == "" {
+			continue
+		}
+		if _, ok := storageSet[bundle]; !ok {
+			continue
+		}
+
+		linked[key] = struct{}{}
+		links[key] = bundle
+	}
+
+	for _, bundle := range links {
+		delete(linked, bundle)
+	}
+
+	return linked, links
+}
+
+// UseStorageLinks maps linked keys to their bundle, so mutations find their storage object.
+func (a *FurnitureAdapter) UseStorageLinks(links map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, bundle := range links {
+		a.idToClassname[key] = bundle
+	}
+}
+
+// SharedStorage returns the entities using each bundle also used by another entity than
+// its storage set key, e.g. color variants (classname*N) sharing their base bundle.
+func (a *FurnitureAdapter) SharedStorage(dbIndex map[string]reconcile.DBItem, gdIndex map[string]reconcile.GDItem, storageSet map[string]struct{}) map[string][]string {
+	users := make(map[string]map[string]struct{})
+	use := func(classname, key string) {
+		bundle := bundleName(classname)
+		if bundle == "" {
+			return
+		}
+		if users[bundle] == nil {
+			users[bundle] = make(map[string]struct{})
+		}
+		users[bundle][key] = struct{}{}
+	}
+	for key, item := range dbIndex {
+		use(item.(DBItem).ItemName, key)
+	}
+	for key, item := range gdIndex {
+		use(item.(GDItem).ClassName, key)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	shared := make(map[string][]string)
+	for key := range storageSet {
+		bundle := key
+		if classname, ok := a.idToClassname[key]; ok {
+			bundle = bundleName(classname)
+		}
+		others := maps.Clone(users[bundle])
+		delete(others, key)
+		if len(others) > 0 {
+			shared[key] = slices.Sorted(maps.Keys(users[bundle]))
+		}
+	}
+	return shared
+}
+
+// bundleName returns the bundle file name for a classname, stripping the color index.
+func bundleName(classname string) string {
+	base, _, _ := strings.Cut(classname, "*")
+	return base
+}
+
 // ResolveName returns the display name for an entity.
 func (a *FurnitureAdapter) ResolveName(dbItem reconcile.DBItem, gdItem reconcile.GDItem) string {
 	if dbItem != nil {
@@ -389,7 +528,7 @@ func (a *FurnitureAdapter) ResolveName(dbItem reconcile.DBItem, gdItem reconcile
 	return ""
 }
 
-// GetMetadata returns classname for furniture.
+// GetMetadata returns the classname and item type for furniture.
 func (a *FurnitureAdapter) GetMetadata(dbItem reconcile.DBItem, gdItem reconcile.GDItem) map[string]string {
 	meta := make(map[string]string)
 
@@ -404,9 +543,35 @@ func (a *FurnitureAdapter) GetMetadata(dbItem reconcile.DBItem, gdItem reconcile
 	if val != "" {
 		meta["classname"] = val
 	}
+
+	// "s" for floor items, "i" for wall items
+	itemType := ""
+	if gdItem != nil {
+		itemType = gdItem.(GDItem).Type
+	}
+	if itemType == "" && dbItem != nil {
+		itemType = dbItem.(DBItem).Type
+	}
+	if itemType != "" {
+		meta["type"] = itemType
+	}
 	return meta
 }
 
+// SearchTerms returns the gamedata and DB classnames and names of a furniture item.
+func (a *FurnitureAdapter) SearchTerms(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
+	var terms []string
+	if gdItem != nil {
+		gd := gdItem.(GDItem)
+		terms = append(terms, gd.ClassName, gd.Name)
+	}
+	if dbItem != nil {
+		db := dbItem.(DBItem)
+		terms = append(terms, db.ItemName, db.PublicName)
+	}
+	return terms
+}
+
 // CompareFields compares DB and gamedata items and returns mismatch descriptions.
 func (a *FurnitureAdapter) CompareFields(dbItem reconcile.DBItem, gdItem reconcile.GDItem) []string {
 	db := dbItem.(DBItem)
@@ -0,0 +1,85 @@
+package reconcile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// bundleAsset is the subset of the asset JSON embedded in a .nitro bundle
+// used to enrich generated gamedata entries.
+type bundleAsset struct {
+	Name      string `json:"name"`
+	LogicType string `json:"logicType"`
+	Logic     struct {
+		Model struct {
+			Dimensions struct {
+				X int     `json:"x"`
+				Y int     `json:"y"`
+				Z float64 `json:"z"`
+			} `json:"dimensions"`
+		} `json:"model"`
+	} `json:"logic"`
+}
+
+// readBundleAsset extracts the asset JSON from a .nitro bundle.
+//
+// A bundle is a big-endian archive: a uint16 file count, then for each file a
+// uint16 name length, the name, a uint32 data length and the zlib-compressed data.
+func readBundleAsset(data []byte) (*bundleAsset, error) {
+	r := bytes.NewReader(data)
+
+	var count uint16
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("failed to read file count: %w", err)
+	}
+
+	for i := 0; i < int(count); i++ {
+		var nameLen uint16
+		if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil {
+			return nil, fmt.Errorf("failed to read name length: %w", err)
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("failed to read name: %w", err)
+		}
+
+		var dataLen uint32
+		if err := binary.Read(r, binary.BigEndian, &dataLen); err != nil {
+			return nil, fmt.Errorf("failed to read data length: %w", err)
+		}
+		if int64(dataLen) > int64(r.Len()) {
+			return nil, fmt.Errorf("file %s exceeds bundle size", name)
+		}
+		compressed := make([]byte, dataLen)
+		if _, err := io.ReadFull(r, compressed); err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", name, err)
+		}
+
+		if !strings.HasSuffix(string(name), ".json") {
+			continue
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", name, err)
+		}
+		raw, err := io.ReadAll(zr)
+		zr.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", name, err)
+		}
+
+		var asset bundleAsset
+		if err := json.Unmarshal(raw, &asset); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return &asset, nil
+	}
+
+	return nil, fmt.Errorf("bundle has no asset JSON")
+}
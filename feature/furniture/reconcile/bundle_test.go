@@ -0,0 +1,150 @@
+package reconcile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildBundle builds a .nitro bundle holding the given files.
+func buildBundle(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint16(len(files)))
+	for name, content := range files {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		_, err := zw.Write([]byte(content))
+		assert.NoError(t, err)
+		assert.NoError(t, zw.Close())
+
+		binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+		buf.WriteString(name)
+		binary.Write(&buf, binary.BigEndian, uint32(compressed.Len()))
+		buf.Write(compressed.Bytes())
+	}
+	return buf.Bytes()
+}
+
+func TestReadBundleAsset(t *testing.T) {
+	data := buildBundle(t, map[string]string{
+		"chair.json": `{"name": "chair", "logicType": "furniture_basic", "logic": {"model": {"dimensions": {"x": 2, "y": 3, "z": 1.5}}}}`,
+	})
+
+	asset, err := readBundleAsset(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "chair", asset.Name)
+	assert.Equal(t, 2, asset.Logic.Model.Dimensions.X)
+	assert.Equal(t, 3, asset.Logic.Model.Dimensions.Y)
+
+	_, err = readBundleAsset(buildBundle(t, map[string]string{"chair.png": "png"}))
+	assert.Error(t, err)
+
+	_, err = readBundleAsset([]byte{0x00})
+	assert.Error(t, err)
+}
+
+func TestLinkStorageKeys(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, nil, "", "bundled/furniture", "arcturus", "")
+
+	dbIndex := map[string]reconcile.DBItem{
+		"100": DBItem{SpriteID: 100, ItemName: "chair"},
+		"101": DBItem{SpriteID: 101, ItemName: "chair*1"},
+		"200": DBItem{SpriteID: 200, ItemName: "table"},
+	}
+	gdIndex := map[string]reconcile.GDItem{"200": GDItem{ID: 200, ClassName: "table"}}
+	storageSet := map[string]struct{}{"chair": {}, "200": {}, "orphan": {}}
+
+	linked, links := adapter.LinkStorageKeys(dbIndex, gdIndex, storageSet)
+
+	assert.Equal(t, map[string]struct{}{"100": {}, "101": {}, "200": {}, "orphan": {}}, linked)
+	assert.Equal(t, map[string]string{"100": "chair", "101": "chair"}, links)
+	assert.Equal(t, map[string]struct{}{"chair": {}, "200": {}, "orphan": {}}, storageSet, "The storage set is not modified")
+	assert.Equal(t, "bundled/furniture/101.nitro", adapter.storageObjectKey("101"), "Links are only used once adopted")
+
+	adapter.UseStorageLinks(links)
+	assert.Equal(t, "bundled/furniture/chair.nitro", adapter.storageObjectKey("101"), "Color variants share the base bundle")
+}
+
+func TestSharedStorage(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, nil, "", "bundled/furniture", "arcturus", "")
+
+	// chair*3 is only in the DB and linked to chair.nitro, also used by chair*1
+	dbIndex := map[string]reconcile.DBItem{
+		"1": DBItem{SpriteID: 1, ItemName: "chair*1"},
+		"3": DBItem{SpriteID: 3, ItemName: "chair*3"},
+		"5": DBItem{SpriteID: 5, ItemName: "lamp"},
+	}
+	gdIndex := map[string]reconcile.GDItem{
+		"1": GDItem{ID: 1, ClassName: "chair*1"},
+		"5": GDItem{ID: 5, ClassName: "lamp"},
+	}
+	adapter.idToClassname["1"] = "chair*1"
+	adapter.idToClassname["5"] = "lamp"
+	storageSet, links := adapter.LinkStorageKeys(dbIndex, gdIndex, map[string]struct{}{"chair": {}, "5": {}, "sofa": {}})
+	adapter.UseStorageLinks(links)
+
+	shared := adapter.SharedStorage(dbIndex, gdIndex, storageSet)
+	assert.Equal(t, map[string][]string{"3": {"1", "3"}}, shared, "Bundles used by one entity are not shared")
+}
+
+func TestCreateGamedataBatch(t *testing.T) {
+	client := mocks.NewMemory()
+	client.Objects["gamedata/FurnitureData.json"] = []byte(journalGamedata)
+	client.Objects["bundled/furniture/sofa.nitro"] = buildBundle(t, map[string]string{
+		"sofa.json": `{"name": "sofa", "logic": {"model": {"dimensions": {"x": 3, "y": 1}}}}`,
+	})
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, "bucket", "bundled/furniture", "arcturus", "gamedata/FurnitureData.json")
+	adapter.SetBundleEnrichment(true)
+	adapter.idToClassname["10"] = "sofa"
+
+	actions := []reconcile.Action{
+		{Type: reconcile.ActionCreateGamedata, Key: "10", DBItem: DBItem{SpriteID: 10, ItemName: "sofa", PublicName: "Sofa", Width: 1, Length: 1, CanSit: true, Type: "s"}},
+		{Type: reconcile.ActionCreateGamedata, Key: "11", DBItem: DBItem{SpriteID: 11, ItemName: "flag", PublicName: "Flag", Type: "i"}},
+		{Type: reconcile.ActionCreateGamedata, Key: "1", DBItem: DBItem{SpriteID: 1, ItemName: "chair", Type: "s"}},
+	}
+
+	created, err := adapter.CreateGamedataBatch(context.Background(), actions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10", "11"}, created, "Existing IDs are not reported")
+
+	var furniData FurnitureData
+	assert.NoError(t, json.Unmarshal(client.Objects["gamedata/FurnitureData.json"], &furniData))
+	assert.Len(t, furniData.RoomItemTypes.FurniType, 3, "Existing IDs should be skipped")
+	assert.Len(t, furniData.WallItemTypes.FurniType, 2)
+
+	sofa := furniData.RoomItemTypes.FurniType[2]
+	assert.Equal(t, 10, sofa.ID)
+	assert.Equal(t, "Sofa", sofa.Name)
+	assert.Equal(t, 3, sofa.XDim, "Dimensions should come from the bundle")
+	assert.True(t, sofa.CanSitOn)
+	assert.Equal(t, "flag", furniData.WallItemTypes.FurniType[1].ClassName)
+
+	// Undo removes the created entries
+	entries := []reconcile.JournalEntry{
+		{Type: reconcile.ActionCreateGamedata, Key: "10"},
+		{Type: reconcile.ActionCreateGamedata, Key: "11"},
+	}
+	assert.NoError(t, adapter.RestoreGamedata(context.Background(), entries))
+	assert.NoError(t, json.Unmarshal(client.Objects["gamedata/FurnitureData.json"], &furniData))
+	assert.Len(t, furniData.RoomItemTypes.FurniType, 2)
+	assert.Len(t, furniData.WallItemTypes.FurniType, 1)
+
+	// Without enrichment, bundles are not read
+	adapter.SetBundleEnrichment(false)
+	_, err = adapter.CreateGamedataBatch(context.Background(), actions[:1])
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(client.Objects["gamedata/FurnitureData.json"], &furniData))
+	assert.Equal(t, 1, furniData.RoomItemTypes.FurniType[2].XDim, "Dimensions should come from the DB row")
+}
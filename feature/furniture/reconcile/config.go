@@ -1,5 +1,7 @@
 package reconcile
 
+import "maps"
+
 // ServerProfile defines emulator-specific database schema mappings.
 type ServerProfile struct {
 	// TableName is the name of the furniture table in the database.
@@ -7,6 +9,31 @@ type ServerProfile struct {
 
 	// Columns maps logical field names to actual database column names.
 	Columns map[string]string
+
+	// Defaults maps logical field names to the values used when creating rows
+	// for fields gamedata does not provide.
+	Defaults map[string]any
+}
+
+// merge returns the profile with the fields it leaves unset taken from base. Defaults
+// are merged per field, so a profile only lists the defaults that differ; the table
+// and columns describe a schema and are only taken from base when unset as a whole.
+func (p ServerProfile) merge(base ServerProfile) ServerProfile {
+	merged := base
+	if p.TableName != "" {
+		merged.TableName = p.TableName
+	}
+	if p.Columns != nil {
+		merged.Columns = p.Columns
+	}
+
+	merged.Defaults = maps.Clone(base.Defaults)
+	if merged.Defaults == nil {
+		merged.Defaults = make(map[string]any, len(p.Defaults))
+	}
+	maps.Copy(merged.Defaults, p.Defaults)
+
+	return merged
 }
 
 // Column name constants for logical field references.
@@ -46,6 +73,11 @@ func ArcturusProfile() ServerProfile {
 			ColType:        "type",
 			ColInteraction: "interaction_type",
 		},
+		Defaults: map[string]any{
+			ColStackHeight: 1.0,
+			ColCanStack:    true,
+			ColInteraction: "default",
+		},
 	}
 }
 
@@ -68,6 +100,11 @@ func CometProfile() ServerProfile {
 			ColType:        "type",
 			ColInteraction: "interaction_type",
 		},
+		Defaults: map[string]any{
+			ColStackHeight: "1", // Comet uses varchar
+			ColCanStack:    "1", // Comet uses enum('0','1')
+			ColInteraction: "default",
+		},
 	}
 }
 
@@ -90,18 +127,20 @@ func PlusProfile() ServerProfile {
 			ColInteraction: "interaction_type",
 			ColIsRare:      "is_rare",
 		},
+		Defaults: map[string]any{
+			ColIsRare: false,
+		},
 	}
 }
 
-// GetProfileByName returns the appropriate server profile for a given emulator name.
+// GetProfileByName returns the appropriate server profile for a given emulator name,
+// merged over the Arcturus profile for the fields it leaves unset.
 func GetProfileByName(emulator string) ServerProfile {
 	switch emulator {
-	case "arcturus":
-		return ArcturusProfile()
 	case "comet":
-		return CometProfile()
+		return CometProfile().merge(ArcturusProfile())
 	case "plus":
-		return PlusProfile()
+		return PlusProfile().merge(ArcturusProfile())
 	default:
 		// Default to Arcturus
 		return ArcturusProfile()
@@ -0,0 +1,120 @@
+package reconcile
+
+// Incremental build support implementing reconcile.Incremental interface
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/utils"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	// Snapshots store the indices as interface values
+	gob.Register(DBItem{})
+	gob.Register(GDItem{})
+}
+
+// checksumFields are the logical fields loaded into DBItem, hence the ones whose
+// changes must be detected by DBChecksums.
+var checksumFields = []string{
+	ColID, ColSpriteID, ColItemName, ColPublicName, ColWidth, ColLength,
+	ColCanSit, ColCanWalk, ColCanLay, ColType,
+}
+
+// DBChecksums returns a CRC32 of the loaded columns of every furniture row, by sprite_id.
+// Rows sharing a sprite_id are combined with XOR. Only keys and checksums are transferred.
+func (a *FurnitureAdapter) DBChecksums(ctx context.Context, db *gorm.DB, serverProfile string) (map[string]uint32, error) {
+	checksums := make(map[string]uint32)
+
+	// Handle nil DB
+	if db == nil {
+		return checksums, nil
+	}
+
+	profile := GetProfileByName(serverProfile)
+
+	columns := make([]string, 0, len(checksumFields))
+	for _, field := range checksumFields {
+		if col, ok := profile.Columns[field]; ok {
+			columns = append(columns, col)
+		}
+	}
+
+	spriteCol := profile.Columns[ColSpriteID]
+	query := fmt.Sprintf(
+		"SELECT %s, BIT_XOR(CRC32(CONCAT_WS('|', %s))) FROM %s GROUP BY %s",
+		spriteCol, strings.Join(columns, ", "), profile.TableName, spriteCol,
+	)
+
+	rows, err := db.WithContext(ctx).Raw(query).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum %s: %w", profile.TableName, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var spriteID, sum any
+		if err := rows.Scan(&spriteID, &sum); err != nil {
+			return nil, fmt.Errorf("failed to scan checksum: %w", err)
+		}
+		checksums[strconv.Itoa(utils.ToInt(spriteID))] = uint32(utils.ToInt(sum))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+
+	return checksums, nil
+}
+
+// LoadDBItems loads the furniture rows of the given sprite_ids.
+func (a *FurnitureAdapter) LoadDBItems(ctx context.Context, db *gorm.DB, serverProfile string, keys []string) (map[string]reconcile.DBItem, error) {
+	if db == nil || len(keys) == 0 {
+		return make(map[string]reconcile.DBItem), nil
+	}
+
+	profile := GetProfileByName(serverProfile)
+
+	spriteIDs := make([]int, 0, len(keys))
+	for _, key := range keys {
+		spriteID, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", key, err)
+		}
+		spriteIDs = append(spriteIDs, spriteID)
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN ?", profile.TableName, profile.Columns[ColSpriteID])
+	dbRows, err := db.WithContext(ctx).Raw(query, spriteIDs).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", profile.TableName, err)
+	}
+	defer dbRows.Close()
+
+	return scanDBItems(ctx, dbRows, profile)
+}
+
+// UseGamedataIndex restores the classname mapping from a gamedata index reused from a snapshot.
+func (a *FurnitureAdapter) UseGamedataIndex(index map[string]reconcile.GDItem) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, item := range index {
+		classname := item.(GDItem).ClassName
+		a.classnameToID[classname] = key
+		a.idToClassname[key] = classname
+	}
+
+	select {
+	case <-a.mappingReady:
+		// already closed
+	default:
+		close(a.mappingReady)
+	}
+}
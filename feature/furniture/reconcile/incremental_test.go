@@ -0,0 +1,80 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"testing"
+
+	"asset-manager/core/reconcile"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFurnitureAdapter_DBChecksums(t *testing.T) {
+	db, mock := setupMockDB(t)
+	adapter := NewAdapter()
+
+	mock.ExpectQuery(`SELECT sprite_id, BIT_XOR\(CRC32\(CONCAT_WS\('\|', id, sprite_id, item_name, public_name, width, length, allow_sit, allow_walk, allow_lay, type\)\)\) FROM items_base GROUP BY sprite_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"sprite_id", "checksum"}).
+			AddRow(100, uint64(3735928559)).
+			AddRow([]byte("200"), []byte("7")))
+
+	checksums, err := adapter.DBChecksums(context.Background(), db, "arcturus")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint32{"100": 3735928559, "200": 7}, checksums)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFurnitureAdapter_LoadDBItems(t *testing.T) {
+	db, mock := setupMockDB(t)
+	adapter := NewAdapter()
+
+	mock.ExpectQuery(`SELECT \* FROM items_base WHERE sprite_id IN \(\?,\?\)`).
+		WithArgs(100, 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sprite_id", "item_name", "public_name", "type"}).
+			AddRow(1, 100, "chair", "Chair", "s").
+			AddRow(2, 200, "poster", "Poster", "i"))
+
+	items, err := adapter.LoadDBItems(context.Background(), db, "arcturus", []string{"100", "200"})
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "poster", items["200"].(DBItem).ItemName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = adapter.LoadDBItems(context.Background(), db, "arcturus", []string{"chair"})
+	assert.Error(t, err)
+}
+
+func TestFurnitureAdapter_UseGamedataIndex(t *testing.T) {
+	adapter := NewAdapter()
+	adapter.UseGamedataIndex(map[string]reconcile.GDItem{"100": GDItem{ID: 100, ClassName: "chair"}})
+
+	select {
+	case <-adapter.mappingReady:
+	default:
+		t.Fatal("mappingReady channel should be closed")
+	}
+
+	key, ok := adapter.ExtractStorageKey("bundled/furniture/chair.nitro", "bundled/furniture", ".nitro")
+	assert.True(t, ok)
+	assert.Equal(t, "100", key)
+	assert.Equal(t, "chair", adapter.idToClassname["100"])
+}
+
+func TestSnapshot_GobFurnitureItems(t *testing.T) {
+	snapshot := reconcile.Snapshot{
+		DBIndex: map[string]reconcile.DBItem{"100": DBItem{SpriteID: 100, ItemName: "chair"}},
+		GDIndex: map[string]reconcile.GDItem{"100": GDItem{ID: 100, ClassName: "chair", Type: "s"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(&snapshot))
+
+	var decoded reconcile.Snapshot
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	assert.Equal(t, snapshot.DBIndex, decoded.DBIndex)
+	assert.Equal(t, snapshot.GDIndex, decoded.GDIndex)
+}
@@ -0,0 +1,462 @@
+package reconcile
+
+// Journal methods implementing reconcile.Journaler interface
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// sectionRoom identifies the roomitemtypes array in FurnitureData.json.
+	sectionRoom = "room"
+	// sectionWall identifies the wallitemtypes array in FurnitureData.json.
+	sectionWall = "wall"
+)
+
+// rawFurnitureData mirrors FurnitureData but keeps every entry as raw JSON,
+// so entries round-trip without losing fields the adapter does not model.
+type rawFurnitureData struct {
+	RoomItemTypes struct {
+		FurniType []json.RawMessage `json:"furnitype"`
+	} `json:"roomitemtypes"`
+	WallItemTypes struct {
+		FurniType []json.RawMessage `json:"furnitype"`
+	} `json:"wallitemtypes"`
+}
+
+// gamedataSnapshot is the journaled form of a removed gamedata entry.
+type gamedataSnapshot struct {
+	// Section is the array the entry was removed from ("room" or "wall").
+	Section string `json:"section"`
+	// Item is the raw gamedata entry.
+	Item json.RawMessage `json:"item"`
+}
+
+// rawEntryID extracts the numeric ID from a raw gamedata entry.
+func rawEntryID(raw json.RawMessage) (int, error) {
+	var entry struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return 0, err
+	}
+	return entry.ID, nil
+}
+
+// readRawGamedata downloads and parses FurnitureData.json keeping raw entries.
+func (a *FurnitureAdapter) readRawGamedata(ctx context.Context) (*rawFurnitureData, error) {
+	reader, err := a.client.GetObject(ctx, a.bucket, a.gamedataObj, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gamedata: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gamedata: %w", err)
+	}
+
+	var furniData rawFurnitureData
+	if err := json.Unmarshal(data, &furniData); err != nil {
+		return nil, fmt.Errorf("failed to parse gamedata: %w", err)
+	}
+
+	return &furniData, nil
+}
+
+// writeGamedata marshals and uploads FurnitureData.json.
+func (a *FurnitureAdapter) writeGamedata(ctx context.Context, furniData any) error {
+	newData, err := json.MarshalIndent(furniData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal gamedata: %w", err)
+	}
+
+	_, err = a.client.PutObject(
+		ctx,
+		a.bucket,
+		a.gamedataObj,
+		io.NopCloser(bytes.NewReader(newData)),
+		int64(len(newData)),
+		minio.PutObjectOptions{ContentType: "application/json"},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write gamedata: %w", err)
+	}
+
+	return nil
+}
+
+// CaptureGamedata returns the raw FurnitureData.json entries for the given keys.
+// Keys without a gamedata entry are skipped.
+func (a *FurnitureAdapter) CaptureGamedata(ctx context.Context, keys []string) ([]reconcile.JournalEntry, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	wanted := make(map[int]string, len(keys))
+	for _, key := range keys {
+		if id, err := strconv.Atoi(key); err == nil {
+			wanted[id] = key
+		}
+	}
+
+	furniData, err := a.readRawGamedata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]reconcile.JournalEntry, 0, len(wanted))
+	capture := func(section string, items []json.RawMessage) error {
+		for _, raw := range items {
+			id, err := rawEntryID(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse gamedata entry: %w", err)
+			}
+			key, ok := wanted[id]
+			if !ok {
+				continue
+			}
+			data, err := json.Marshal(gamedataSnapshot{Section: section, Item: raw})
+			if err != nil {
+				return fmt.Errorf("failed to marshal gamedata entry %s: %w", key, err)
+			}
+			entries = append(entries, reconcile.JournalEntry{
+				Type: reconcile.ActionDeleteGamedata,
+				Key:  key,
+				Data: data,
+			})
+		}
+		return nil
+	}
+
+	if err := capture(sectionRoom, furniData.RoomItemTypes.FurniType); err != nil {
+		return nil, err
+	}
+	if err := capture(sectionWall, furniData.WallItemTypes.FurniType); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// QuarantineStorage copies the .nitro files for the given keys under quarantinePrefix,
+// preserving their original object key as the suffix. With a trash set, the files are
+// put in the trash instead, so purged objects are copied once and expire with it.
+func (a *FurnitureAdapter) QuarantineStorage(ctx context.Context, keys []string, quarantinePrefix string) ([]reconcile.JournalEntry, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if a.trash != nil {
+		return a.trashStorage(ctx, keys)
+	}
+
+	entries := make([]reconcile.JournalEntry, len(keys))
+	err := a.runConcurrent(len(keys), func(i int) error {
+		objectKey := a.storageObjectKey(keys[i])
+		quarantineKey := quarantinePrefix + "/" + objectKey
+
+		_, err := a.client.CopyObject(ctx,
+			minio.CopyDestOptions{Bucket: a.bucket, Object: quarantineKey},
+			minio.CopySrcOptions{Bucket: a.bucket, Object: objectKey},
+		)
+		if err != nil {
+			return fmt.Errorf("quarantine failed for %s: %w", objectKey, err)
+		}
+
+		entries[i] = reconcile.JournalEntry{
+			Type:          reconcile.ActionDeleteStorage,
+			Key:           keys[i],
+			Object:        objectKey,
+			QuarantineKey: quarantineKey,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// RestoreDB re-inserts deleted rows, reverts synced rows and removes created rows
+// using server-aware mapping.
+func (a *FurnitureAdapter) RestoreDB(ctx context.Context, entries []reconcile.JournalEntry) error {
+	if a.db == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	profile := GetProfileByName(a.serverProfile)
+	spriteCol := profile.Columns[ColSpriteID]
+
+	for _, entry := range entries {
+		// Created rows have no snapshot: undoing them removes the row
+		if entry.Type == reconcile.ActionCreateDB {
+			if err := a.DeleteDB(ctx, entry.Key); err != nil {
+				return fmt.Errorf("failed to remove created DB row for %s: %w", entry.Key, err)
+			}
+			continue
+		}
+
+		if len(entry.Data) == 0 {
+			return fmt.Errorf("journal entry %s has no DB snapshot", entry.Key)
+		}
+
+		var item DBItem
+		if err := json.Unmarshal(entry.Data, &item); err != nil {
+			return fmt.Errorf("failed to parse DB snapshot for %s: %w", entry.Key, err)
+		}
+
+		row := dbItemColumns(profile, item)
+
+		switch entry.Type {
+		case reconcile.ActionDeleteDB:
+			var count int64
+			if err := a.db.WithContext(ctx).Table(profile.TableName).Where(spriteCol+" = ?", item.SpriteID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check DB row for %s: %w", entry.Key, err)
+			}
+			if count > 0 {
+				continue
+			}
+
+			if item.ID > 0 {
+				row[profile.Columns[ColID]] = item.ID
+			}
+			if err := a.db.WithContext(ctx).Table(profile.TableName).Create(row).Error; err != nil {
+				return fmt.Errorf("failed to restore DB row for %s: %w", entry.Key, err)
+			}
+
+		case reconcile.ActionSyncDB:
+			result := a.db.WithContext(ctx).Table(profile.TableName).Where(spriteCol+" = ?", item.SpriteID).Updates(row)
+			if result.Error != nil {
+				return fmt.Errorf("failed to revert DB row for %s: %w", entry.Key, result.Error)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RestoreGamedata re-appends removed entries to FurnitureData.json and removes
+// created ones in one write.
+func (a *FurnitureAdapter) RestoreGamedata(ctx context.Context, entries []reconcile.JournalEntry) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	furniData, err := a.readRawGamedata(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[int]struct{})
+	for _, items := range [][]json.RawMessage{furniData.RoomItemTypes.FurniType, furniData.WallItemTypes.FurniType} {
+		for _, raw := range items {
+			if id, err := rawEntryID(raw); err == nil {
+				existing[id] = struct{}{}
+			}
+		}
+	}
+
+	// Created entries are removed rather than restored
+	created := make(map[int]struct{})
+	for _, entry := range entries {
+		if entry.Type != reconcile.ActionCreateGamedata {
+			continue
+		}
+		if id, err := strconv.Atoi(entry.Key); err == nil {
+			created[id] = struct{}{}
+			delete(existing, id)
+		}
+	}
+	if len(created) > 0 {
+		furniData.RoomItemTypes.FurniType = withoutRawEntries(furniData.RoomItemTypes.FurniType, created)
+		furniData.WallItemTypes.FurniType = withoutRawEntries(furniData.WallItemTypes.FurniType, created)
+	}
+
+	for _, entry := range entries {
+		if entry.Type == reconcile.ActionCreateGamedata {
+			continue
+		}
+
+		var snapshot gamedataSnapshot
+		if err := json.Unmarshal(entry.Data, &snapshot); err != nil {
+			return fmt.Errorf("failed to parse gamedata snapshot for %s: %w", entry.Key, err)
+		}
+
+		id, err := rawEntryID(snapshot.Item)
+		if err != nil {
+			return fmt.Errorf("failed to parse gamedata snapshot for %s: %w", entry.Key, err)
+		}
+		if _, ok := existing[id]; ok {
+			continue
+		}
+		existing[id] = struct{}{}
+
+		if snapshot.Section == sectionWall {
+			furniData.WallItemTypes.FurniType = append(furniData.WallItemTypes.FurniType, snapshot.Item)
+		} else {
+			furniData.RoomItemTypes.FurniType = append(furniData.RoomItemTypes.FurniType, snapshot.Item)
+		}
+	}
+
+	return a.writeGamedata(ctx, furniData)
+}
+
+// withoutRawEntries returns the raw entries whose ID is not in ids.
+func withoutRawEntries(items []json.RawMessage, ids map[int]struct{}) []json.RawMessage {
+	kept := make([]json.RawMessage, 0, len(items))
+	for _, raw := range items {
+		if id, err := rawEntryID(raw); err == nil {
+			if _, ok := ids[id]; ok {
+				continue
+			}
+		}
+		kept = append(kept, raw)
+	}
+	return kept
+}
+
+// trashStorage puts the .nitro files for the given keys in the trash and returns one
+// journal entry per object, pointing at its trashed copy.
+func (a *FurnitureAdapter) trashStorage(ctx context.Context, keys []string) ([]reconcile.JournalEntry, error) {
+	objectKeys := make([]string, len(keys))
+	for i, key := range keys {
+		objectKeys[i] = a.storageObjectKey(key)
+	}
+
+	batch, err := a.trash.Put(ctx, objectKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move objects to trash: %w", err)
+	}
+
+	entries := make([]reconcile.JournalEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = reconcile.JournalEntry{
+			Type:          reconcile.ActionDeleteStorage,
+			Key:           key,
+			Object:        objectKeys[i],
+			QuarantineKey: storage.TrashKey(batch, objectKeys[i]),
+		}
+	}
+	return entries, nil
+}
+
+// RestoreStorage copies quarantined (or trashed) .nitro files back to their original
+// keys and removes the copies.
+func (a *FurnitureAdapter) RestoreStorage(ctx context.Context, entries []reconcile.JournalEntry) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	return a.runConcurrent(len(entries), func(i int) error {
+		entry := entries[i]
+		_, err := a.client.CopyObject(ctx,
+			minio.CopyDestOptions{Bucket: a.bucket, Object: entry.Object},
+			minio.CopySrcOptions{Bucket: a.bucket, Object: entry.QuarantineKey},
+		)
+		if err != nil {
+			return fmt.Errorf("restore failed for %s: %w", entry.Object, err)
+		}
+
+		if err := a.client.RemoveObject(ctx, a.bucket, entry.QuarantineKey, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to remove quarantined copy %s: %w", entry.QuarantineKey, err)
+		}
+		return nil
+	})
+}
+
+// dbItemColumns maps a normalized DB item onto the profile's column names.
+func dbItemColumns(profile ServerProfile, item DBItem) map[string]any {
+	row := map[string]any{
+		profile.Columns[ColSpriteID]:   item.SpriteID,
+		profile.Columns[ColItemName]:   item.ItemName,
+		profile.Columns[ColPublicName]: item.PublicName,
+		profile.Columns[ColWidth]:      item.Width,
+		profile.Columns[ColLength]:     item.Length,
+	}
+
+	if col, ok := profile.Columns[ColCanSit]; ok {
+		row[col] = item.CanSit
+	}
+	if col, ok := profile.Columns[ColCanWalk]; ok {
+		row[col] = item.CanWalk
+	}
+	if col, ok := profile.Columns[ColCanLay]; ok {
+		row[col] = item.CanLay
+	}
+	if col, ok := profile.Columns[ColType]; ok && item.Type != "" {
+		row[col] = item.Type
+	}
+
+	return row
+}
+
+// runConcurrent runs fn for indices [0, n) on the adapter's worker pool
+// and aggregates any errors.
+func (a *FurnitureAdapter) runConcurrent(n int, fn func(i int) error) error {
+	if n == 0 {
+		return nil
+	}
+
+	numWorkers := 50
+	if a.batchConcurrency > 0 {
+		numWorkers = a.batchConcurrency
+	}
+	if numWorkers > n {
+		numWorkers = n
+	}
+
+	indexCh := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexCh <- i
+	}
+	close(indexCh)
+
+	errorCh := make(chan error, n)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				if err := fn(i); err != nil {
+					errorCh <- err
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errorCh)
+
+	var errors []string
+	for err := range errorCh {
+		errors = append(errors, err.Error())
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("batch had %d errors: %v", len(errors), errors)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/reconcile"
+	"asset-manager/core/storage"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const journalGamedata = `{
+  "roomitemtypes": {"furnitype": [
+    {"id": 1, "classname": "chair", "name": "Chair", "revision": 7, "category": "chairs"},
+    {"id": 2, "classname": "table", "name": "Table"}
+  ]},
+  "wallitemtypes": {"furnitype": [
+    {"id": 3, "classname": "poster", "name": "Poster", "description": "A poster"}
+  ]}
+}`
+
+func TestCaptureAndRestoreGamedata(t *testing.T) {
+	client := mocks.NewMemory()
+	client.Objects["gamedata/FurnitureData.json"] = []byte(journalGamedata)
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, "bucket", "bundled/furniture", "arcturus", "gamedata/FurnitureData.json")
+
+	entries, err := adapter.CaptureGamedata(context.Background(), []string{"1", "3", "99"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2, "Unknown keys should be skipped")
+
+	// Delete and restore
+	assert.NoError(t, adapter.DeleteGamedataBatch(context.Background(), []string{"1", "3"}))
+	assert.NoError(t, adapter.RestoreGamedata(context.Background(), entries))
+
+	var restored rawFurnitureData
+	assert.NoError(t, json.Unmarshal(client.Objects["gamedata/FurnitureData.json"], &restored))
+	assert.Len(t, restored.RoomItemTypes.FurniType, 2)
+	assert.Len(t, restored.WallItemTypes.FurniType, 1)
+
+	// Fields not modelled by GDItem survive the round-trip
+	assert.Contains(t, string(restored.RoomItemTypes.FurniType[1]), `"category": "chairs"`)
+	assert.Contains(t, string(restored.WallItemTypes.FurniType[0]), `"description": "A poster"`)
+
+	// Restoring twice does not duplicate entries
+	assert.NoError(t, adapter.RestoreGamedata(context.Background(), entries))
+	assert.NoError(t, json.Unmarshal(client.Objects["gamedata/FurnitureData.json"], &restored))
+	assert.Len(t, restored.RoomItemTypes.FurniType, 2)
+}
+
+func TestQuarantineAndRestoreStorage(t *testing.T) {
+	client := mocks.NewMemory()
+	client.Objects["bundled/furniture/chair.nitro"] = []byte("nitro")
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, "bucket", "bundled/furniture", "arcturus", "")
+	adapter.idToClassname["1"] = "chair"
+
+	entries, err := adapter.QuarantineStorage(context.Background(), []string{"1"}, ".reconcile/quarantine/j1")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "bundled/furniture/chair.nitro", entries[0].Object)
+	assert.Equal(t, ".reconcile/quarantine/j1/bundled/furniture/chair.nitro", entries[0].QuarantineKey)
+	assert.Contains(t, client.Objects, entries[0].QuarantineKey)
+
+	// Simulate the purge, then restore
+	delete(client.Objects, "bundled/furniture/chair.nitro")
+	assert.NoError(t, adapter.RestoreStorage(context.Background(), entries))
+
+	assert.Equal(t, []byte("nitro"), client.Objects["bundled/furniture/chair.nitro"])
+	assert.NotContains(t, client.Objects, entries[0].QuarantineKey, "Quarantined copy should be removed")
+}
+
+func TestRestoreDB(t *testing.T) {
+	db := setupTestDB(t, "db_restore")
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, "", "", "arcturus", "")
+
+	db.Exec("INSERT INTO items_base (id, sprite_id, item_name, public_name, width) VALUES (2, 200, 'synced', 'Synced', 2)")
+
+	deleted, _ := json.Marshal(DBItem{ID: 1, SpriteID: 100, ItemName: "chair", PublicName: "Chair", Width: 1, Length: 1, CanSit: true, Type: "s"})
+	original, _ := json.Marshal(DBItem{ID: 2, SpriteID: 200, ItemName: "table", PublicName: "Table", Width: 1, Length: 1, Type: "s"})
+
+	entries := []reconcile.JournalEntry{
+		{Type: reconcile.ActionDeleteDB, Key: "100", Data: deleted},
+		{Type: reconcile.ActionSyncDB, Key: "200", Data: original},
+	}
+
+	assert.NoError(t, adapter.RestoreDB(context.Background(), entries))
+
+	var rows []struct {
+		ID         int
+		SpriteID   int
+		ItemName   string
+		PublicName string
+		Width      int
+		AllowSit   bool
+	}
+	db.Table("items_base").Order("id").Find(&rows)
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 100, rows[0].SpriteID)
+	assert.Equal(t, "chair", rows[0].ItemName)
+	assert.True(t, rows[0].AllowSit)
+	assert.Equal(t, "table", rows[1].ItemName, "Synced row should be reverted")
+	assert.Equal(t, 1, rows[1].Width)
+
+	// Restoring a deletion twice is a no-op
+	assert.NoError(t, adapter.RestoreDB(context.Background(), entries[:1]))
+	var count int64
+	db.Table("items_base").Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestDeleteStorage_MovesToTrash(t *testing.T) {
+	client := mocks.NewMemory()
+	client.Objects["bundled/furniture/chair.nitro"] = []byte("nitro")
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, "bucket", "bundled/furniture", "arcturus", "")
+	adapter.SetTrash(storage.NewTrash(client, "bucket", time.Hour))
+	adapter.idToClassname["1"] = "chair"
+
+	assert.NoError(t, adapter.DeleteStorage(context.Background(), "1"))
+	assert.NotContains(t, client.Objects, "bundled/furniture/chair.nitro")
+
+	var trashed []string
+	for key := range client.Objects {
+		if strings.HasPrefix(key, storage.TrashPrefix+"/") {
+			trashed = append(trashed, key)
+		}
+	}
+	assert.Len(t, trashed, 1)
+	assert.True(t, strings.HasSuffix(trashed[0], "/bundled/furniture/chair.nitro"))
+	assert.Equal(t, []byte("nitro"), client.Objects[trashed[0]])
+}
+
+func TestQuarantineStorage_UsesTrash(t *testing.T) {
+	client := mocks.NewMemory()
+	client.Objects["bundled/furniture/chair.nitro"] = []byte("nitro")
+
+	adapter := NewAdapter()
+	adapter.SetMutationContext(nil, client, "bucket", "bundled/furniture", "arcturus", "")
+	adapter.SetTrash(storage.NewTrash(client, "bucket", time.Hour))
+	adapter.idToClassname["1"] = "chair"
+
+	entries, err := adapter.QuarantineStorage(context.Background(), []string{"1"}, ".reconcile/quarantine/j1")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.True(t, strings.HasPrefix(entries[0].QuarantineKey, storage.TrashPrefix+"/"), "The journal points at the trashed copy")
+
+	// The purge does not copy the object again
+	assert.NoError(t, adapter.DeleteQuarantinedStorage(context.Background(), entries))
+	assert.Equal(t, []string{entries[0].QuarantineKey}, slices.Collect(maps.Keys(client.Objects)))
+
+	assert.NoError(t, adapter.RestoreStorage(context.Background(), entries))
+	assert.Equal(t, map[string][]byte{"bundled/furniture/chair.nitro": []byte("nitro")}, client.Objects)
+}
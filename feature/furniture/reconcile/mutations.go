@@ -11,6 +11,7 @@ import (
 	"strconv"
 
 	"asset-manager/core/reconcile"
+	"asset-manager/feature/furniture/models"
 
 	"github.com/minio/minio-go/v7"
 )
@@ -137,6 +138,13 @@ func (a *FurnitureAdapter) DeleteStorage(ctx context.Context, key string) error
 	// Build object key
 	objectKey := fmt.Sprintf("%s/%s.nitro", a.storagePrefix, classname)
 
+	// Soft delete: keep a copy in the trash before removal
+	if a.trash != nil {
+		if _, err := a.trash.Put(ctx, []string{objectKey}); err != nil {
+			return fmt.Errorf("failed to move %s to trash: %w", objectKey, err)
+		}
+	}
+
 	// Delete object
 	err := a.client.RemoveObject(ctx, a.bucket, objectKey, minio.RemoveObjectOptions{})
 	if err != nil {
@@ -205,6 +213,114 @@ func (a *FurnitureAdapter) SyncDBFromGamedata(ctx context.Context, key string, g
 	return nil
 }
 
+// CreateDBFromGamedata inserts a new DB row from the gamedata item using server-aware
+// mapping. Fields gamedata does not provide use the server profile defaults.
+// If a row with the same sprite_id already exists, it is left untouched and false is returned.
+func (a *FurnitureAdapter) CreateDBFromGamedata(ctx context.Context, key string, gdItem reconcile.GDItem) (bool, error) {
+	if a.db == nil {
+		return false, fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	profile := GetProfileByName(a.serverProfile)
+	gd, ok := gdItem.(GDItem)
+	if !ok {
+		return false, fmt.Errorf("no gamedata item for key %s", key)
+	}
+
+	// Convert key to sprite_id
+	spriteID, err := strconv.Atoi(key)
+	if err != nil {
+		return false, fmt.Errorf("invalid key %s: %w", key, err)
+	}
+
+	var count int64
+	if err := a.db.WithContext(ctx).Table(profile.TableName).Where(profile.Columns[ColSpriteID]+" = ?", spriteID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check DB row for %s: %w", key, err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if err := a.db.WithContext(ctx).Table(profile.TableName).Create(gdItemColumns(profile, spriteID, gd)).Error; err != nil {
+		return false, fmt.Errorf("failed to create DB row for %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// CreateGamedataFromDB appends a new FurnitureData.json entry built from the DB row.
+// Returns false if an entry with the same ID already exists.
+func (a *FurnitureAdapter) CreateGamedataFromDB(ctx context.Context, key string, dbItem reconcile.DBItem) (bool, error) {
+	created, err := a.CreateGamedataBatch(ctx, []reconcile.Action{{Type: reconcile.ActionCreateGamedata, Key: key, DBItem: dbItem}})
+	return len(created) > 0, err
+}
+
+// gamedataEntry builds a FurnitureData.json entry from a DB row. Floor items get their
+// dimensions and flags from the row, overridden by the bundle model dimensions when available.
+// Returns the section the entry belongs to ("room" or "wall").
+func gamedataEntry(item DBItem, asset *bundleAsset) (string, models.FurnitureItem) {
+	entry := models.FurnitureItem{
+		ID:          item.SpriteID,
+		ClassName:   item.ItemName,
+		Revision:    1,
+		Category:    "other",
+		Name:        item.PublicName,
+		Description: item.PublicName,
+	}
+	entry.PartColors.Color = []string{}
+
+	if item.Type == "i" {
+		return sectionWall, entry
+	}
+
+	entry.XDim = item.Width
+	entry.YDim = item.Length
+	entry.CanSitOn = item.CanSit
+	entry.CanStandOn = item.CanWalk
+	entry.CanLayOn = item.CanLay
+
+	if asset != nil {
+		if dims := asset.Logic.Model.Dimensions; dims.X > 0 && dims.Y > 0 {
+			entry.XDim = dims.X
+			entry.YDim = dims.Y
+		}
+	}
+
+	return sectionRoom, entry
+}
+
+// gdItemColumns builds a new DB row from a gamedata item, filling the fields
+// gamedata does not provide with the server profile defaults.
+func gdItemColumns(profile ServerProfile, spriteID int, gd GDItem) map[string]any {
+	// Same limits as SyncDBFromGamedata
+	const maxNameLen = 110
+
+	itemType := gd.Type
+	if itemType == "" {
+		itemType = "s"
+	}
+
+	row := dbItemColumns(profile, DBItem{
+		SpriteID:   spriteID,
+		ItemName:   truncateStr(gd.ClassName, maxNameLen),
+		PublicName: truncateStr(gd.Name, maxNameLen),
+		Width:      gd.XDim,
+		Length:     gd.YDim,
+		CanSit:     gd.CanSitOn,
+		CanWalk:    gd.CanStandOn,
+		CanLay:     gd.CanLayOn,
+		Type:       itemType,
+	})
+
+	for field, value := range profile.Defaults {
+		if col, ok := profile.Columns[field]; ok {
+			row[col] = value
+		}
+	}
+
+	return row
+}
+
 // truncateStr truncates a string to the specified length.
 func truncateStr(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"sync"
 
@@ -25,34 +26,46 @@ func (a *FurnitureAdapter) DeleteStorageBatch(ctx context.Context, keys []string
 		return nil
 	}
 
-	// Build object info channel for batch deletion
-	objectsCh := make(chan minio.ObjectInfo, len(keys))
-
+	objectKeys := make([]string, 0, len(keys))
 	for _, key := range keys {
-		var classname string
-
-		// Check if key is numeric (ID) or already a classname/relPath
-		if _, err := strconv.Atoi(key); err == nil {
-			// Key is an ID, likely a mapped item.
-			a.mu.RLock()
-			cn, ok := a.idToClassname[key]
-			a.mu.RUnlock()
-
-			if ok {
-				// It's a mapped item, use the classname
-				classname = cn
-			} else {
-				// Numeric key but not mapped?
-				// This happens if ExtractStorageKey returns a numeric filename (e.g. 00011.nitro)
-				// which is an orphan. We treat it as the relative path.
-				classname = key
-			}
-		} else {
-			// Key is already a classname/relPath (storage orphan)
-			classname = key
+		objectKeys = append(objectKeys, a.storageObjectKey(key))
+	}
+
+	// Soft delete: keep a copy in the trash before removal
+	if a.trash != nil {
+		if _, err := a.trash.Put(ctx, objectKeys); err != nil {
+			return fmt.Errorf("failed to move objects to trash: %w", err)
 		}
+	}
+
+	return a.removeObjects(ctx, objectKeys)
+}
+
+// DeleteQuarantinedStorage removes the .nitro files QuarantineStorage already copied,
+// to the quarantine prefix or the trash, without trashing them again.
+func (a *FurnitureAdapter) DeleteQuarantinedStorage(ctx context.Context, entries []reconcile.JournalEntry) error {
+	if a.client == nil {
+		return fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
 
-		objectKey := fmt.Sprintf("%s/%s.nitro", a.storagePrefix, classname)
+	objectKeys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		objectKeys = append(objectKeys, entry.Object)
+	}
+
+	return a.removeObjects(ctx, objectKeys)
+}
+
+// removeObjects deletes the given storage objects using the batch API.
+func (a *FurnitureAdapter) removeObjects(ctx context.Context, objectKeys []string) error {
+	if len(objectKeys) == 0 {
+		return nil
+	}
+
+	// Build object info channel for batch deletion
+	objectsCh := make(chan minio.ObjectInfo, len(objectKeys))
+
+	for _, objectKey := range objectKeys {
 		objectsCh <- minio.ObjectInfo{Key: objectKey}
 	}
 	close(objectsCh)
@@ -75,6 +88,34 @@ func (a *FurnitureAdapter) DeleteStorageBatch(ctx context.Context, keys []string
 	return nil
 }
 
+// storageObjectKey resolves the storage object key for an entity key.
+func (a *FurnitureAdapter) storageObjectKey(key string) string {
+	var classname string
+
+	// Check if key is numeric (ID) or already a classname/relPath
+	if _, err := strconv.Atoi(key); err == nil {
+		// Key is an ID, likely a mapped item.
+		a.mu.RLock()
+		cn, ok := a.idToClassname[key]
+		a.mu.RUnlock()
+
+		if ok {
+			// It's a mapped item, use the classname
+			classname = cn
+		} else {
+			// Numeric key but not mapped?
+			// This happens if ExtractStorageKey returns a numeric filename (e.g. 00011.nitro)
+			// which is an orphan. We treat it as the relative path.
+			classname = key
+		}
+	} else {
+		// Key is already a classname/relPath (storage orphan)
+		classname = key
+	}
+
+	return fmt.Sprintf("%s/%s.nitro", a.storagePrefix, classname)
+}
+
 // DeleteDBBatch deletes multiple DB rows efficiently using IN clause.
 func (a *FurnitureAdapter) DeleteDBBatch(ctx context.Context, keys []string) error {
 	if a.db == nil {
@@ -179,6 +220,171 @@ func (a *FurnitureAdapter) SyncDBBatch(ctx context.Context, actions []reconcile.
 	return nil
 }
 
+// createBatchSize is the number of rows per multi-row INSERT and per IN lookup.
+const createBatchSize = 500
+
+// CreateDBBatch inserts multiple DB rows from gamedata: the sprite IDs already in the
+// table are looked up with IN queries, then the others are inserted with multi-row
+// INSERTs in one transaction. Returns the keys of the rows inserted; rows that already
+// exist are skipped.
+func (a *FurnitureAdapter) CreateDBBatch(ctx context.Context, actions []reconcile.Action) ([]string, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	profile := GetProfileByName(a.serverProfile)
+	spriteCol := profile.Columns[ColSpriteID]
+
+	spriteIDs := make([]int, len(actions))
+	for i, action := range actions {
+		spriteID, err := strconv.Atoi(action.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", action.Key, err)
+		}
+		spriteIDs[i] = spriteID
+	}
+
+	// Look up the rows already present
+	existing := make(map[int]struct{}, len(spriteIDs))
+	for chunk := range slices.Chunk(spriteIDs, createBatchSize) {
+		var found []int
+		err := a.db.WithContext(ctx).
+			Table(profile.TableName).
+			Where(spriteCol+" IN ?", chunk).
+			Pluck(spriteCol, &found).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing DB rows: %w", err)
+		}
+		for _, id := range found {
+			existing[id] = struct{}{}
+		}
+	}
+
+	rows := make([]map[string]any, 0, len(actions))
+	created := make([]string, 0, len(actions))
+	for i, action := range actions {
+		if _, ok := existing[spriteIDs[i]]; ok {
+			continue
+		}
+		gd, ok := action.GDItem.(GDItem)
+		if !ok {
+			return nil, fmt.Errorf("no gamedata item for key %s", action.Key)
+		}
+		existing[spriteIDs[i]] = struct{}{}
+		rows = append(rows, gdItemColumns(profile, spriteIDs[i], gd))
+		created = append(created, action.Key)
+	}
+
+	if len(rows) == 0 {
+		return created, nil
+	}
+
+	// The batches run in one transaction, so a failure inserts nothing
+	if err := a.db.WithContext(ctx).Table(profile.TableName).CreateInBatches(rows, createBatchSize).Error; err != nil {
+		return nil, fmt.Errorf("failed to batch create DB rows: %w", err)
+	}
+
+	return created, nil
+}
+
+// CreateGamedataBatch appends entries built from DB rows to FurnitureData.json in one write.
+// With SetBundleEnrichment, each entry is enriched with the model dimensions embedded in
+// its .nitro bundle when the bundle can be read. Rows whose ID already exists in gamedata
+// are skipped. Returns the keys of the entries appended.
+func (a *FurnitureAdapter) CreateGamedataBatch(ctx context.Context, actions []reconcile.Action) ([]string, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("mutation context not set, call SetMutationContext first")
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	// Read bundles concurrently; enrichment is best effort
+	assets := make([]*bundleAsset, len(actions))
+	if a.enrichFromBundles {
+		_ = a.runConcurrent(len(actions), func(i int) error {
+			assets[i] = a.readBundle(ctx, actions[i].Key)
+			return nil
+		})
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	furniData, err := a.readRawGamedata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[int]struct{})
+	for _, items := range [][]json.RawMessage{furniData.RoomItemTypes.FurniType, furniData.WallItemTypes.FurniType} {
+		for _, raw := range items {
+			if id, err := rawEntryID(raw); err == nil {
+				existing[id] = struct{}{}
+			}
+		}
+	}
+
+	created := make([]string, 0, len(actions))
+	for i, action := range actions {
+		item, ok := action.DBItem.(DBItem)
+		if !ok {
+			return nil, fmt.Errorf("no DB item for key %s", action.Key)
+		}
+		if _, ok := existing[item.SpriteID]; ok {
+			continue
+		}
+		existing[item.SpriteID] = struct{}{}
+
+		section, entry := gamedataEntry(item, assets[i])
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal gamedata entry %s: %w", action.Key, err)
+		}
+
+		if section == sectionWall {
+			furniData.WallItemTypes.FurniType = append(furniData.WallItemTypes.FurniType, raw)
+		} else {
+			furniData.RoomItemTypes.FurniType = append(furniData.RoomItemTypes.FurniType, raw)
+		}
+		created = append(created, action.Key)
+	}
+
+	if len(created) == 0 {
+		return created, nil
+	}
+	if err := a.writeGamedata(ctx, furniData); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// readBundle downloads and parses the .nitro bundle for a key.
+// Returns nil if the bundle cannot be read.
+func (a *FurnitureAdapter) readBundle(ctx context.Context, key string) *bundleAsset {
+	reader, err := a.client.GetObject(ctx, a.bucket, a.storageObjectKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil
+	}
+
+	asset, err := readBundleAsset(data)
+	if err != nil {
+		return nil
+	}
+	return asset
+}
+
 // DeleteGamedataBatch removes multiple items from FurnitureData.json in one write.
 func (a *FurnitureAdapter) DeleteGamedataBatch(ctx context.Context, keys []string) error {
 	if a.client == nil {
@@ -120,3 +120,75 @@ func TestSyncDBBatch_Concurrency(t *testing.T) {
 		assert.Equal(t, expected, res.PublicName, "Row %d should be updated", i)
 	}
 }
+
+func TestCreateDBBatch(t *testing.T) {
+	db := setupTestDB(t, "db_create")
+	adapter := NewAdapter()
+	adapter.SetMutationContext(db, nil, "", "", "arcturus", "")
+	adapter.SetBatchConcurrency(1)
+
+	db.Exec("INSERT INTO items_base (id, sprite_id, item_name) VALUES (1, 200, 'existing')")
+
+	actions := []reconcile.Action{
+		{Type: reconcile.ActionCreateDB, Key: "100", GDItem: GDItem{ID: 100, ClassName: "chair", Name: "Chair", XDim: 1, YDim: 2, CanSitOn: true, Type: "s"}},
+		{Type: reconcile.ActionCreateDB, Key: "200", GDItem: GDItem{ID: 200, ClassName: "table", Name: "Table"}},
+		{Type: reconcile.ActionCreateDB, Key: "300", GDItem: GDItem{ID: 300, ClassName: "lamp", Name: "Lamp", Type: "i"}},
+	}
+
+	created, err := adapter.CreateDBBatch(context.Background(), actions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"100", "300"}, created, "Only inserted rows are reported")
+
+	var rows []struct {
+		SpriteID        int
+		ItemName        string
+		PublicName      string
+		Length          int
+		StackHeight     float64
+		AllowStack      bool
+		AllowSit        bool
+		Type            string
+		InteractionType string
+	}
+	db.Table("items_base").Order("sprite_id").Find(&rows)
+
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "chair", rows[0].ItemName)
+	assert.Equal(t, "Chair", rows[0].PublicName)
+	assert.Equal(t, 2, rows[0].Length)
+	assert.True(t, rows[0].AllowSit)
+	assert.True(t, rows[0].AllowStack)
+	assert.Equal(t, 1.0, rows[0].StackHeight)
+	assert.Equal(t, "default", rows[0].InteractionType)
+	assert.Equal(t, "existing", rows[1].ItemName, "Existing rows are left untouched")
+	assert.Equal(t, "lamp", rows[2].ItemName)
+	assert.Equal(t, "i", rows[2].Type)
+
+	// Undo removes the created row
+	entries := []reconcile.JournalEntry{{Type: reconcile.ActionCreateDB, Key: "100"}}
+	assert.NoError(t, adapter.RestoreDB(context.Background(), entries))
+
+	var count int64
+	db.Table("items_base").Where("sprite_id = ?", 100).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGDItemColumns_ProfileDefaults(t *testing.T) {
+	// A profile setting a single default keeps the base table, columns and other defaults
+	base := ArcturusProfile()
+	profile := ServerProfile{Defaults: map[string]any{ColInteraction: "wired"}}.merge(base)
+	assert.Equal(t, "items_base", profile.TableName)
+	assert.Equal(t, "default", base.Defaults[ColInteraction], "The base profile is left untouched")
+
+	row := gdItemColumns(profile, 100, GDItem{ClassName: "chair", Name: "Chair"})
+	assert.Equal(t, "wired", row["interaction_type"])
+	assert.Equal(t, 1.0, row["stack_height"])
+	assert.Equal(t, true, row["allow_stack"])
+	assert.Equal(t, "s", row["type"])
+
+	// Plus only lists the defaults that differ from the base
+	row = gdItemColumns(GetProfileByName("plus"), 100, GDItem{ClassName: "chair"})
+	assert.Equal(t, 1.0, row["stack_height"])
+	assert.Equal(t, false, row["is_rare"])
+	assert.NotContains(t, row, "allow_lay", "Columns are not merged into a profile's schema")
+}
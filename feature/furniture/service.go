@@ -2,36 +2,338 @@ package furniture
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"asset-manager/core/database"
+	"asset-manager/core/events"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 	"asset-manager/feature/furniture/integrity"
 	"asset-manager/feature/furniture/models"
+	furnitureAdp "asset-manager/feature/furniture/reconcile"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// eventSource names furniture reconcile runs in published events.
+const eventSource = "reconcile_furniture"
+
+var (
+	// ErrNoDatabase is returned by DB-dependent operations while the database is unavailable.
+	ErrNoDatabase = database.ErrUnavailable
+	// ErrInvalidPlanRequest is returned when a reconcile plan request has invalid options.
+	ErrInvalidPlanRequest = errors.New("invalid plan request")
+)
+
 // Service handles furniture operations.
 type Service struct {
 	client   storage.Client
 	bucket   string
 	logger   *zap.Logger
-	db       *gorm.DB
 	emulator string
+
+	// db is swapped in by SetDB once the database connects
+	dbMu sync.RWMutex
+	db   *gorm.DB
+
+	// Two-step reconcile flow. The adapter is shared between planning and applying
+	// since mutations rely on the classname mapping loaded while planning.
+	reconcileMu sync.Mutex
+	adapter     *furnitureAdp.FurnitureAdapter
+	spec        *reconcile.Spec
+	linkedSpec  *reconcile.Spec
+	safety      reconcile.Config
+	plans       *reconcile.PlanStore
+
+	// listSpec serves listings from cached indices, read-only
+	listSpec *reconcile.Spec
+
+	// events receives live progress of reconcile runs (optional)
+	events *events.Hub
 }
 
 // NewService creates a new furniture service.
 func NewService(client storage.Client, bucket string, logger *zap.Logger, db *gorm.DB, emulator string) *Service {
+	adapter := furnitureAdp.NewAdapter()
+	adapter.SetMutationContext(db, client, bucket, "bundled/furniture", emulator, "gamedata/FurnitureData.json")
+
+	spec := &reconcile.Spec{
+		Adapter:            adapter,
+		CacheTTL:           0, // Always plan from fresh indices
+		StoragePrefix:      "bundled/furniture",
+		StorageExtension:   ".nitro",
+		GamedataPaths:      []string{}, // Not used, loads full JSON
+		GamedataObjectName: "gamedata/FurnitureData.json",
+		ServerProfile:      emulator,
+	}
+
+	// Creating gamedata needs the bundles of DB rows missing in gamedata linked to them
+	linkedSpec := *spec
+	linkedSpec.LinkStorage = true
+
 	return &Service{
-		client:   client,
-		bucket:   bucket,
-		logger:   logger,
-		db:       db,
-		emulator: emulator,
+		client:     client,
+		bucket:     bucket,
+		logger:     logger,
+		db:         db,
+		emulator:   emulator,
+		adapter:    adapter,
+		spec:       spec,
+		linkedSpec: &linkedSpec,
+		listSpec: &reconcile.Spec{
+			Adapter:            furnitureAdp.NewAdapter(),
+			CacheTTL:           time.Minute,
+			StoragePrefix:      "bundled/furniture",
+			StorageExtension:   ".nitro",
+			GamedataPaths:      []string{"roomitemtypes.furnitype", "wallitemtypes.furnitype"},
+			GamedataObjectName: "gamedata/FurnitureData.json",
+			ServerProfile:      emulator,
+			CacheName:          "listing", // Not rebuilt by integrity checks and jobs
+		},
+		plans: reconcile.NewPlanStore(reconcile.PlanConfig{TTLMinutes: 15}.TTL()),
 	}
 }
 
+// SetReconcileConfig sets the safety thresholds and plan expiry of the reconcile flow.
+// If trash is not nil, purged storage objects are moved to it.
+func (s *Service) SetReconcileConfig(safety reconcile.Config, plans reconcile.PlanConfig, trash *storage.Trash) {
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	s.safety = safety
+	s.plans = reconcile.NewPlanStore(plans.TTL())
+	if trash != nil {
+		s.adapter.SetTrash(trash)
+	}
+}
+
+// SetCacheConfig sets the expiry and refresh of the listing indices and where index
+// snapshots are kept.
+func (s *Service) SetCacheConfig(cfg reconcile.CacheConfig, snapshots reconcile.SnapshotConfig) {
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	s.listSpec.CacheTTL = cfg.TTL()
+	s.listSpec.MaxStale = cfg.MaxStale()
+	s.listSpec.RefreshInterval = cfg.RefreshInterval()
+	s.spec.Snapshots = snapshots.Store()
+	s.linkedSpec.Snapshots = s.spec.Snapshots
+	s.listSpec.Snapshots = s.spec.Snapshots
+}
+
+// SetDB swaps in the database connection, e.g. once database.Manager reconnects.
+// It waits for a running plan or apply to finish.
+func (s *Service) SetDB(db *gorm.DB) {
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	s.dbMu.Lock()
+	s.db = db
+	s.dbMu.Unlock()
+	s.adapter.SetMutationContext(db, s.client, s.bucket, "bundled/furniture", s.emulator, "gamedata/FurnitureData.json")
+}
+
+// database returns the database connection, or nil while it is unavailable.
+func (s *Service) database() *gorm.DB {
+	s.dbMu.RLock()
+	defer s.dbMu.RUnlock()
+	return s.db
+}
+
+// StartCacheRefresher rebuilds the listing indices in the background on the configured
+// refresh interval until ctx is cancelled. Does nothing without a database.
+func (s *Service) StartCacheRefresher(ctx context.Context) {
+	db := s.database()
+	if db == nil {
+		return
+	}
+	reconcile.StartRefresher(ctx, s.listSpec, db, s.client, s.bucket, func(err error) {
+		s.logger.Warn("Background furniture cache refresh failed", zap.Error(err))
+	})
+}
+
+// HandleStorageEvents patches the cached indices after bundles were uploaded or removed,
+// and drops them when the gamedata changed (see reconcile.ApplyObjectEvents).
+func (s *Service) HandleStorageEvents(events []storage.ObjectEvent) {
+	for _, spec := range []*reconcile.Spec{s.listSpec, s.spec, s.linkedSpec} {
+		result := reconcile.ApplyObjectEvents(spec, events)
+		if result.StorageAdded > 0 || result.StorageRemoved > 0 || result.GamedataInvalidated {
+			s.logger.Info("Furniture cache updated from bucket events",
+				zap.String("cache_key", spec.CacheKey()),
+				zap.Int("storage_added", result.StorageAdded),
+				zap.Int("storage_removed", result.StorageRemoved),
+				zap.Bool("gamedata_invalidated", result.GamedataInvalidated),
+			)
+		}
+	}
+}
+
+// CacheStats returns the metrics of the listing indices cache.
+func (s *Service) CacheStats() reconcile.CacheStats {
+	return reconcile.Stats(s.listSpec)
+}
+
 // GetFurnitureDetail returns detailed integrity info for a single furniture item.
 func (s *Service) GetFurnitureDetail(ctx context.Context, identifier string) (*models.FurnitureDetailReport, error) {
-	return integrity.CheckFurnitureItem(ctx, s.client, s.bucket, s.db, s.emulator, identifier)
+	db := s.database()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	return integrity.CheckFurnitureItem(ctx, s.client, s.bucket, db, s.emulator, identifier)
+}
+
+// ListFurniture returns a filtered page of furniture reconcile results.
+// Results come from indices cached for the listing cache TTL.
+func (s *Service) ListFurniture(ctx context.Context, filter reconcile.ListFilter) (*reconcile.ListPage, error) {
+	db := s.database()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	filter.SearchMetadata = []string{"classname"}
+	return reconcile.ListResults(ctx, s.listSpec, db, s.client, s.bucket, filter)
+}
+
+// SearchFurniture finds furniture by ID, classname or name, tolerating typos.
+// Results come from the same cached indices as ListFurniture.
+func (s *Service) SearchFurniture(ctx context.Context, query string, limit int) ([]reconcile.SearchHit, error) {
+	db := s.database()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	return reconcile.SearchResults(ctx, s.listSpec, db, s.client, s.bucket, query, limit)
+}
+
+// ReconcileSummary builds a dry-run reconcile plan for all furniture and returns its summary.
+func (s *Service) ReconcileSummary(ctx context.Context) (_ *reconcile.PlanSummary, err error) {
+	ctx = s.events.Observe(ctx, eventSource)
+	defer func() { s.events.Done(ctx, eventSource, err) }()
+
+	db := s.database()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	plan, err := integrity.ReconcileFurnitureWithPlan(ctx, s.client, s.bucket, db, s.emulator)
+	if err != nil {
+		return nil, err
+	}
+	return &plan.Summary, nil
+}
+
+// SetEvents publishes the progress of reconcile runs to the hub.
+func (s *Service) SetEvents(hub *events.Hub) {
+	s.events = hub
+}
+
+// PlanReconcile computes a reconcile plan and stores it until it is applied or expires.
+func (s *Service) PlanReconcile(ctx context.Context, req models.ReconcilePlanRequest) (_ *reconcile.StoredPlan, err error) {
+	ctx = s.events.Observe(ctx, eventSource)
+	defer func() { s.events.Done(ctx, eventSource, err) }()
+
+	if s.database() == nil {
+		return nil, ErrNoDatabase
+	}
+
+	sources, err := reconcile.ParseSources(req.PurgeFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: purge_from: %v", ErrInvalidPlanRequest, err)
+	}
+
+	presence := make([]reconcile.Presence, 0, len(req.PurgePresence))
+	for _, pattern := range req.PurgePresence {
+		p, err := reconcile.ParsePresence(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: purge_presence: %v", ErrInvalidPlanRequest, err)
+		}
+		presence = append(presence, p)
+	}
+
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	opts := reconcile.ReconcileOptions{
+		DoPurge:          req.Purge,
+		PurgeFrom:        sources,
+		PurgePresence:    presence,
+		DoSync:           req.Sync,
+		DoCreateDB:       req.CreateMissingDB,
+		DoCreateGamedata: req.CreateMissingGamedata,
+		Safety:           s.safety,
+		Force:            req.Force,
+	}
+
+	plan, err := reconcile.ReconcileWithPlan(ctx, s.reconcileSpec(opts), s.database(), s.client, s.bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.plans.Save(plan, opts), nil
+}
+
+// reconcileSpec returns the spec plans run with opts are computed from and applied to.
+func (s *Service) reconcileSpec(opts reconcile.ReconcileOptions) *reconcile.Spec {
+	if opts.DoCreateGamedata {
+		return s.linkedSpec
+	}
+	return s.spec
+}
+
+// ApplyReconcile executes a stored plan once, journaling every mutation.
+// Returns ErrPlanNotFound, ErrPlanExpired or ErrStalePlan if the plan can no longer be applied,
+// ErrSafetyThreshold if it exceeds the guard rails without force, and ErrShuttingDown during shutdown.
+func (s *Service) ApplyReconcile(ctx context.Context, id string) (_ *models.ReconcileApplyResponse, err error) {
+	ctx = s.events.Observe(ctx, eventSource)
+	defer func() { s.events.Done(ctx, eventSource, err) }()
+
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	stored, err := s.plans.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse plans computed from indices that were rebuilt in between. The plan is only
+	// consumed once it is applied, so a refusal can be retried and reports the same error.
+	spec := s.reconcileSpec(stored.Options)
+	if err := reconcile.CheckPlanFresh(spec, stored.Plan); err != nil {
+		return nil, err
+	}
+	if _, err := s.plans.Take(id); err != nil {
+		return nil, err
+	}
+
+	// Sync and create write gamedata names into the DB; make sure they fit
+	if stored.Options.DoSync || stored.Options.DoCreateDB {
+		if err := s.adapter.Prepare(ctx, s.database()); err != nil {
+			return nil, fmt.Errorf("failed to prepare schema: %w", err)
+		}
+	}
+
+	opts := stored.Options
+	opts.Confirmed = true
+	opts.Journal = reconcile.NewJournal(spec)
+
+	executed, err := reconcile.ApplyPlan(ctx, spec, s.database(), s.client, s.bucket, stored.Plan, opts)
+
+	// The sources changed: other pending plans must be recomputed
+	reconcile.InvalidateCache(s.spec)
+	reconcile.InvalidateCache(s.linkedSpec)
+	reconcile.InvalidateCache(s.listSpec)
+
+	resp := &models.ReconcileApplyResponse{
+		PlanID:   stored.ID,
+		Executed: executed,
+		Summary:  stored.Plan.Summary,
+	}
+	if len(opts.Journal.Entries) > 0 {
+		resp.JournalID = opts.Journal.ID
+	}
+
+	return resp, err
 }
@@ -0,0 +1,25 @@
+// Package health provides liveness and readiness endpoints reporting the status of the
+// service dependencies.
+//
+// # Checks
+//
+//   - storage (required): BucketExists on the configured bucket.
+//   - database (optional): a ping of the emulator database, down while unavailable
+//     (see database.Manager).
+//   - schema (optional): the emulator schema matches the expected models (see
+//     feature/integrity/checks), skipped while the database is unavailable.
+//
+// Checks run concurrently, each bounded by a timeout, and report their status
+// (ok, down, skipped) and latency; errors are logged only. The report also lists the
+// loaded features. A failed optional check degrades the overall status; a failed
+// required check makes the service down. Reports are reused for a few seconds, so
+// probes cannot be used to flood the dependencies.
+//
+// # HTTP Endpoints
+//
+//   - GET /healthz : Liveness. Always 200, without checking the dependencies.
+//   - GET /readyz  : Readiness. The report; 503 while a required check is down.
+//
+// Probes cannot send the API key header, so the endpoints are registered before the
+// API key middleware.
+package health
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler handles the health endpoints.
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the health routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Get("/healthz", h.HandleHealth)
+	app.Get("/readyz", h.HandleReady)
+}
+
+// HandleHealth reports that the process serves requests, for liveness probes.
+// It does not check the dependencies, so their outages do not restart the process.
+// @Summary Liveness Check
+// @Description Always 200 while the process serves requests. Dependencies are reported by /readyz.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *Handler) HandleHealth(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": StatusOK})
+}
+
+// HandleReady reports whether the service can serve traffic.
+// @Summary Readiness Check
+// @Description Reports storage reachability, database ping, emulator schema match and loaded features with per-dependency status and latency, refreshed at most every few seconds. Returns 503 while a required dependency (storage) is down; optional dependencies (database, schema) only degrade the status. Check errors are logged, not returned.
+// @Tags health
+// @Produce json
+// @Success 200 {object} Report
+// @Failure 503 {object} Report
+// @Router /readyz [get]
+func (h *Handler) HandleReady(c *fiber.Ctx) error {
+	report := h.service.Report(c.UserContext())
+	if !report.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+	return c.JSON(report)
+}
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"asset-manager/core/database"
+	"asset-manager/core/storage/mocks"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// staticFeatures lists fixed feature names.
+type staticFeatures []string
+
+func (f staticFeatures) Loaded() []string {
+	return f
+}
+
+// unavailable is a database that never connected.
+func unavailable() (*gorm.DB, error) {
+	return nil, fmt.Errorf("%w: connection refused", database.ErrUnavailable)
+}
+
+// get requests a health endpoint and decodes its report.
+func get(t *testing.T, app *fiber.App, path string) (int, Report) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	return resp.StatusCode, report
+}
+
+func TestHandler_Healthy(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	service := NewService(staticFeatures{"integrity", "furniture"}, checkTimeout,
+		StorageCheck(client, "assets"),
+		DatabaseCheck(func() (*gorm.DB, error) { return db, nil }),
+	)
+	app := fiber.New()
+	NewHandler(service).RegisterRoutes(app)
+
+	status, report := get(t, app, "/readyz")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["storage"].Status)
+	assert.True(t, report.Checks["storage"].Required)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+	assert.Equal(t, []string{"furniture", "integrity"}, report.Features)
+}
+
+func TestHandler_Degraded(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(true, nil)
+
+	// Optional database: unavailable degrades, schema is skipped
+	core, logs := observer.New(zap.WarnLevel)
+	feature := NewFeature(client, "assets", unavailable, "arcturus", staticFeatures{}, zap.New(core))
+	app := fiber.New()
+	require.NoError(t, feature.Load(app))
+
+	status, report := get(t, app, "/readyz")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusDown, report.Checks["database"].Status)
+	assert.Equal(t, StatusSkipped, report.Checks["schema"].Status)
+	assert.Empty(t, report.Features)
+
+	// Errors are logged, not served
+	body := getBody(t, app, "/readyz")
+	assert.NotContains(t, body, "connection refused")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "database", logs.All()[0].ContextMap()["check"])
+	assert.Equal(t, "database unavailable: connection refused", logs.All()[0].ContextMap()["error"])
+}
+
+func TestHandler_Down(t *testing.T) {
+	client := new(mocks.Client)
+	client.On("BucketExists", mock.Anything, "assets").Return(false, errors.New("connection refused"))
+
+	feature := NewFeature(client, "assets", unavailable, "arcturus", nil, zap.NewNop())
+	app := fiber.New()
+	require.NoError(t, feature.Load(app))
+
+	// Required storage is down: not ready, but still alive
+	status, report := get(t, app, "/readyz")
+	assert.Equal(t, 503, status)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusDown, report.Checks["storage"].Status)
+
+	status, report = get(t, app, "/healthz")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Empty(t, report.Checks, "Liveness does not check dependencies")
+}
+
+func TestService_ReportCached(t *testing.T) {
+	runs := 0
+	service := NewService(nil, checkTimeout, Check{Name: "counter", Run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}})
+	now := time.Unix(1000, 0)
+	service.now = func() time.Time { return now }
+	service.SetCacheTTL(5 * time.Second)
+
+	first := service.Report(context.Background())
+	assert.Same(t, first, service.Report(context.Background()))
+	assert.Equal(t, 1, runs)
+
+	now = now.Add(5 * time.Second)
+	assert.NotSame(t, first, service.Report(context.Background()))
+	assert.Equal(t, 2, runs)
+}
+
+// getBody requests a health endpoint and returns its raw body.
+func getBody(t *testing.T, app *fiber.App, path string) string {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
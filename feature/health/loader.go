@@ -0,0 +1,52 @@
+package health
+
+import (
+	"time"
+
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// checkTimeout bounds each dependency check.
+	checkTimeout = 5 * time.Second
+	// reportTTL is how long /readyz serves the same report.
+	reportTTL = 5 * time.Second
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	handler *Handler
+}
+
+// NewFeature creates a new Health feature checking the storage bucket, the optional
+// emulator database and its schema, and listing the features loaded by the lister.
+// Failed checks are logged to logger.
+func NewFeature(client storage.Client, bucket string, getDB DBFunc, emulator string, features FeatureLister, logger *zap.Logger) *Feature {
+	service := NewService(features, checkTimeout,
+		StorageCheck(client, bucket),
+		DatabaseCheck(getDB),
+		SchemaCheck(getDB, emulator),
+	)
+	service.SetCacheTTL(reportTTL)
+	service.SetLogger(logger)
+	return &Feature{handler: NewHandler(service)}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "health"
+}
+
+// IsEnabled checks if the feature is enabled.
+func (f *Feature) IsEnabled() bool {
+	return true
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
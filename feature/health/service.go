@@ -0,0 +1,249 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"asset-manager/core/storage"
+	"asset-manager/feature/integrity/checks"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrSkipped is returned by a check that cannot run, e.g. the schema check without a database.
+var ErrSkipped = errors.New("skipped")
+
+// Status values of checks and reports.
+const (
+	// StatusOK means the dependency (or every dependency) is healthy.
+	StatusOK = "ok"
+	// StatusDown means the dependency (or a required dependency) failed its check.
+	StatusDown = "down"
+	// StatusSkipped means the check could not run.
+	StatusSkipped = "skipped"
+	// StatusDegraded means an optional dependency failed its check.
+	StatusDegraded = "degraded"
+)
+
+// Check is a dependency probed by the health endpoints.
+type Check struct {
+	// Name identifies the dependency in the report.
+	Name string
+	// Required dependencies make the service unready when down.
+	Required bool
+	// Run probes the dependency. It returns ErrSkipped if it cannot run.
+	Run func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a check.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMS float64 `json:"latency_ms"`
+	// Error is logged, never served: the endpoints are public and errors can reveal
+	// hosts, bucket names or the schema
+	Error string `json:"-"`
+}
+
+// Report is the body of the health endpoints.
+type Report struct {
+	Status   string                 `json:"status"`
+	Checks   map[string]CheckResult `json:"checks"`
+	Features []string               `json:"features"`
+}
+
+// Ready reports whether every required dependency is up.
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// FeatureLister lists the loaded features (see loader.Manager).
+type FeatureLister interface {
+	Loaded() []string
+}
+
+// Service runs the dependency checks.
+type Service struct {
+	checks   []Check
+	features FeatureLister
+	timeout  time.Duration
+	ttl      time.Duration
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	cached *Report
+	ran    time.Time
+	now    func() time.Time
+}
+
+// NewService creates a service running the checks, each bounded by timeout.
+func NewService(features FeatureLister, timeout time.Duration, checks ...Check) *Service {
+	return &Service{checks: checks, features: features, timeout: timeout, logger: zap.NewNop(), now: time.Now}
+}
+
+// SetCacheTTL makes Report reuse the last report for ttl, so frequent or concurrent
+// probes do not hit the dependencies each time. Zero disables caching.
+func (s *Service) SetCacheTTL(ttl time.Duration) {
+	s.ttl = ttl
+}
+
+// SetLogger sets the logger receiving the errors of failed checks.
+func (s *Service) SetLogger(logger *zap.Logger) {
+	s.logger = logger
+}
+
+// Report returns the last report if it is younger than the cache TTL, or runs the
+// checks. Concurrent callers wait for a single run.
+func (s *Service) Report(ctx context.Context) *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.now().Sub(s.ran) < s.ttl {
+		return s.cached
+	}
+
+	report := s.Run(ctx)
+	for _, name := range slices.Sorted(maps.Keys(report.Checks)) {
+		if result := report.Checks[name]; result.Status == StatusDown {
+			s.logger.Warn("Health check failed",
+				zap.String("check", name),
+				zap.Bool("required", result.Required),
+				zap.String("error", result.Error))
+		}
+	}
+	s.cached, s.ran = report, s.now()
+	return report
+}
+
+// Run runs every check concurrently and aggregates the results.
+func (s *Service) Run(ctx context.Context) *Report {
+	results := make([]CheckResult, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(s.checks)), Features: []string{}}
+	for i, check := range s.checks {
+		result := results[i]
+		report.Checks[check.Name] = result
+		if result.Status != StatusDown {
+			continue
+		}
+		if check.Required {
+			report.Status = StatusDown
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	if s.features != nil {
+		report.Features = append(report.Features, s.features.Loaded()...)
+		sort.Strings(report.Features)
+	}
+	return report
+}
+
+// run runs a single check within the timeout.
+func (s *Service) run(ctx context.Context, check Check) CheckResult {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		Required:  check.Required,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	switch {
+	case errors.Is(err, ErrSkipped):
+		result.Status = StatusSkipped
+	case err != nil:
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// StorageCheck probes the bucket with BucketExists. Storage is required.
+func StorageCheck(client storage.Client, bucket string) Check {
+	return Check{Name: "storage", Required: true, Run: func(ctx context.Context) error {
+		exists, err := client.BucketExists(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("storage unreachable: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("bucket %s does not exist", bucket)
+		}
+		return nil
+	}}
+}
+
+// DBFunc returns the live database connection, or why it is unavailable
+// (see database.Manager.Get).
+type DBFunc func() (*gorm.DB, error)
+
+// DatabaseCheck pings the emulator database. The database is optional, so a failure
+// degrades the service without making it unready.
+func DatabaseCheck(getDB DBFunc) Check {
+	return Check{Name: "database", Run: func(ctx context.Context) error {
+		db, err := getDB()
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database handle: %w", err)
+		}
+		return sqlDB.PingContext(ctx)
+	}}
+}
+
+// SchemaCheck verifies the emulator schema matches the models of the emulator.
+// It is skipped while the database is unavailable.
+func SchemaCheck(getDB DBFunc, emulator string) Check {
+	return Check{Name: "schema", Run: func(ctx context.Context) error {
+		db, err := getDB()
+		if err != nil {
+			return ErrSkipped
+		}
+		report, err := checks.CheckServerIntegrity(db.WithContext(ctx), emulator)
+		if err != nil {
+			return err
+		}
+		if report.Matched {
+			return nil
+		}
+
+		problems := append([]string(nil), report.Errors...)
+		for table, t := range report.Tables {
+			for _, col := range t.MissingColumns {
+				problems = append(problems, fmt.Sprintf("%s: missing column %s", table, col))
+			}
+			for _, mismatch := range t.TypeMismatches {
+				problems = append(problems, fmt.Sprintf("%s: %s", table, mismatch))
+			}
+		}
+		sort.Strings(problems)
+		return fmt.Errorf("schema does not match emulator %s: %s", emulator, strings.Join(problems, "; "))
+	}}
+}
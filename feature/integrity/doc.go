@@ -18,4 +18,6 @@
 //   - GET /integrity/gamedata : Runs gamedata check.
 //   - GET /integrity/bundled : Runs bundle check (supports ?fix=true).
 //   - GET /integrity/server : Runs server schema check.
+//
+// Every endpoint requires the "read" API key scope; ?fix=true also requires "integrity:fix".
 package integrity
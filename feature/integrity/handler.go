@@ -1,7 +1,12 @@
 package integrity
 
 import (
+	"errors"
+
+	"asset-manager/core/database"
 	"asset-manager/core/logger"
+	"asset-manager/core/middleware/auth"
+	"asset-manager/core/middleware/rayid"
 	"asset-manager/feature/integrity/checks"
 
 	"github.com/gofiber/fiber/v2"
@@ -22,7 +27,8 @@ func NewHandler(service *Service) *Handler {
 
 // RegisterRoutes registers the integrity routes.
 func (h *Handler) RegisterRoutes(app fiber.Router) {
-	group := app.Group("/integrity")
+	// ?fix=true additionally requires auth.ScopeIntegrityFix, checked by the handlers
+	group := app.Group("/integrity", auth.Require(auth.ScopeRead))
 	group.Get("/", h.HandleIntegrityCheck)
 	group.Get("/structure", h.HandleStructureCheck)
 	group.Get("/bundled", h.HandleBundleCheck)
@@ -33,7 +39,7 @@ func (h *Handler) RegisterRoutes(app fiber.Router) {
 
 // HandleIntegrityCheck triggers all integrity checks.
 // @Summary Run All Integrity Checks
-// @Description Performs all available integrity checks (Structure, Bundled, GameData, Furniture, Server). This operation may take a long time.
+// @Description Performs all available integrity checks (Structure, Bundled, GameData, Furniture, Server). This operation may take a long time; prefer submitting an "integrity" job via POST /jobs.
 // @Tags integrity
 // @Accept json
 // @Produce json
@@ -44,45 +50,7 @@ func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 	l.Info("Triggering all integrity checks")
 
-	ctx := c.Context()
-	report := make(map[string]any)
-
-	// Structure
-	if missing, err := h.service.CheckStructure(ctx); err != nil {
-		report["structure"] = map[string]any{"status": "error", "error": err.Error()}
-	} else {
-		report["structure"] = map[string]any{"status": "ok", "missing": missing}
-	}
-
-	// Bundled
-	if missing, err := h.service.CheckBundled(ctx); err != nil {
-		report["bundled"] = map[string]any{"status": "error", "error": err.Error()}
-	} else {
-		report["bundled"] = map[string]any{"status": "ok", "missing": missing}
-	}
-
-	// GameData
-	if missing, err := h.service.CheckGameData(ctx); err != nil {
-		report["gamedata"] = map[string]any{"status": "error", "error": err.Error()}
-	} else {
-		report["gamedata"] = map[string]any{"status": "ok", "missing": missing}
-	}
-
-	// Server
-	if srvReport, err := h.service.CheckServer(); err != nil {
-		report["server"] = map[string]any{"status": "error", "error": err.Error()}
-	} else {
-		report["server"] = srvReport
-	}
-
-	// Furniture (Slow)
-	if furnReport, err := h.service.CheckFurniture(ctx, false); err != nil {
-		report["furniture"] = map[string]any{"status": "error", "error": err.Error()}
-	} else {
-		report["furniture"] = furnReport
-	}
-
-	return c.JSON(report)
+	return c.JSON(h.service.RunAll(c.Context(), nil))
 }
 
 // HandleStructureCheck checks and optionally fixes structure.
@@ -93,11 +61,15 @@ func (h *Handler) HandleIntegrityCheck(c *fiber.Ctx) error {
 // @Produce json
 // @Param fix query boolean false "Fix missing folders"
 // @Success 200 {object} map[string]any "Structure Report"
+// @Failure 403 {object} map[string]string "Fix Requires integrity:fix Scope"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/structure [get]
 func (h *Handler) HandleStructureCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 	fix := c.Query("fix") == "true"
+	if fix && !auth.Permits(c, auth.ScopeIntegrityFix) {
+		return auth.Forbidden(c, auth.ScopeIntegrityFix)
+	}
 
 	missing, err := h.service.CheckStructure(c.Context())
 	if err != nil {
@@ -138,11 +110,15 @@ func (h *Handler) HandleStructureCheck(c *fiber.Ctx) error {
 // @Produce json
 // @Param fix query boolean false "Fix missing folders"
 // @Success 200 {object} map[string]any "Bundle Report"
+// @Failure 403 {object} map[string]string "Fix Requires integrity:fix Scope"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/bundled [get]
 func (h *Handler) HandleBundleCheck(c *fiber.Ctx) error {
 	l := logger.WithRayID(h.service.logger, c)
 	fix := c.Query("fix") == "true"
+	if fix && !auth.Permits(c, auth.ScopeIntegrityFix) {
+		return auth.Forbidden(c, auth.ScopeIntegrityFix)
+	}
 
 	missing, err := h.service.CheckBundled(c.Context())
 	if err != nil {
@@ -207,6 +183,7 @@ func (h *Handler) HandleGameDataCheck(c *fiber.Ctx) error {
 // @Produce json
 // @Param db query boolean false "Check Database Integrity too"
 // @Success 200 {object} map[string]any "Furniture Report"
+// @Failure 503 {object} map[string]string "Database Unavailable (db=true)"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/furniture [get]
 func (h *Handler) HandleFurnitureCheck(c *fiber.Ctx) error {
@@ -214,8 +191,11 @@ func (h *Handler) HandleFurnitureCheck(c *fiber.Ctx) error {
 	l.Info("Starting furniture integrity check")
 
 	checkDB := c.Query("db") == "true"
-	report, err := h.service.CheckFurniture(c.Context(), checkDB)
+	report, err := h.service.CheckFurniture(rayid.Context(c), checkDB)
 	if err != nil {
+		if errors.Is(err, database.ErrUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
 		l.Error("Furniture check failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -236,6 +216,7 @@ func (h *Handler) HandleFurnitureCheck(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Success 200 {object} checks.ServerReport "Server Check Report"
+// @Failure 503 {object} map[string]string "Database Unavailable"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /integrity/server [get]
 func (h *Handler) HandleServerCheck(c *fiber.Ctx) error {
@@ -244,6 +225,9 @@ func (h *Handler) HandleServerCheck(c *fiber.Ctx) error {
 
 	report, err := h.service.CheckServer()
 	if err != nil {
+		if errors.Is(err, database.ErrUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		}
 		l.Error("Server schema check failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
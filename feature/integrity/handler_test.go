@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"asset-manager/core/middleware/auth"
 	"asset-manager/core/storage/mocks"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -18,6 +19,7 @@ import (
 
 func setupTestApp(t *testing.T) (*fiber.App, *mocks.Client, sqlmock.Sqlmock) {
 	app := fiber.New()
+	app.Use(auth.WithKey(auth.Key{Name: "test", Scopes: []string{auth.ScopeAll}}))
 	mockClient := new(mocks.Client)
 	db, sqlMock := setupMockDB(t)
 	logger := zap.NewNop()
@@ -133,3 +135,19 @@ func TestHandleFurnitureCheck(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 500, resp.StatusCode)
 }
+
+func TestHandleStructureCheck_FixScope(t *testing.T) {
+	app := fiber.New()
+	app.Use(auth.New(auth.Config{Keys: []auth.Key{
+		{Name: "reader", Secret: "r", Scopes: []string{auth.ScopeRead}},
+	}}))
+	svc := NewService(new(mocks.Client), "test-bucket", zap.NewNop(), nil, "arcturus")
+	NewHandler(svc).RegisterRoutes(app)
+
+	// Fixing requires integrity:fix; the storage is never touched
+	req := httptest.NewRequest("GET", "/integrity/structure?fix=true", nil)
+	req.Header.Set(auth.HeaderKey, "r")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
@@ -1,6 +1,11 @@
 package integrity
 
 import (
+	"context"
+
+	"asset-manager/core/events"
+	"asset-manager/core/jobs"
+	"asset-manager/core/reconcile"
 	"asset-manager/core/storage"
 
 	"github.com/gofiber/fiber/v2"
@@ -37,3 +42,30 @@ func (f *Feature) Load(app fiber.Router) error {
 	f.handler.RegisterRoutes(app)
 	return nil
 }
+
+// SetDB swaps in the database connection (see Service.SetDB).
+func (f *Feature) SetDB(db *gorm.DB) {
+	f.service.SetDB(db)
+}
+
+// SetEvents publishes the progress of furniture checks to the hub.
+func (f *Feature) SetEvents(hub *events.Hub) {
+	f.service.SetEvents(hub)
+}
+
+// RegisterJobs registers the integrity job types with the job manager.
+func (f *Feature) RegisterJobs(m *jobs.Manager) {
+	m.Register("integrity", func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		result := f.service.RunAll(ctx, report)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+	m.Register("integrity_furniture", func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		ctx = reconcile.WithProgress(ctx, func(phase reconcile.Phase, processed, total int) {
+			report(processed, total, string(phase))
+		})
+		return f.service.CheckFurniture(ctx, params["db"] == "true")
+	})
+}
@@ -1,6 +1,7 @@
 package integrity
 
 import (
+	"asset-manager/core/jobs"
 	"asset-manager/core/storage/mocks"
 	"testing"
 
@@ -22,3 +23,12 @@ func TestLoader(t *testing.T) {
 	err := feature.Load(app)
 	assert.NoError(t, err)
 }
+
+func TestLoader_RegisterJobs(t *testing.T) {
+	feature := NewFeature(new(mocks.Client), "test-bucket", zap.NewNop(), nil, "")
+	manager := jobs.NewManager(jobs.Config{}, jobs.NewMemoryStore(0), zap.NewNop())
+
+	feature.RegisterJobs(manager)
+
+	assert.ElementsMatch(t, []string{"integrity", "integrity_furniture"}, manager.Types())
+}
@@ -2,7 +2,10 @@ package integrity
 
 import (
 	"context"
+	"sync"
 
+	"asset-manager/core/database"
+	"asset-manager/core/events"
 	"asset-manager/core/storage"
 	furnitureIntegrity "asset-manager/feature/furniture/integrity"
 	"asset-manager/feature/furniture/models"
@@ -17,8 +20,14 @@ type Service struct {
 	client   storage.Client
 	bucket   string
 	logger   *zap.Logger
-	db       *gorm.DB
 	emulator string
+
+	// db is swapped in by SetDB once the database connects
+	dbMu sync.RWMutex
+	db   *gorm.DB
+
+	// events receives live progress of furniture checks (optional)
+	events *events.Hub
 }
 
 // NewService creates a new integrity service.
@@ -32,6 +41,20 @@ func NewService(client storage.Client, bucket string, logger *zap.Logger, db *go
 	}
 }
 
+// SetDB swaps in the database connection, e.g. once database.Manager reconnects.
+func (s *Service) SetDB(db *gorm.DB) {
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+	s.db = db
+}
+
+// database returns the database connection, or nil while it is unavailable.
+func (s *Service) database() *gorm.DB {
+	s.dbMu.RLock()
+	defer s.dbMu.RUnlock()
+	return s.db
+}
+
 // CheckStructure returns a list of missing folders.
 func (s *Service) CheckStructure(ctx context.Context) ([]string, error) {
 	return checks.CheckStructure(ctx, s.client, s.bucket)
@@ -57,19 +80,88 @@ func (s *Service) FixBundled(ctx context.Context, missing []string) error {
 	return checks.FixBundled(ctx, s.client, s.bucket, s.logger, missing)
 }
 
+// SetEvents publishes the progress of furniture checks to the hub.
+func (s *Service) SetEvents(hub *events.Hub) {
+	s.events = hub
+}
+
 // CheckFurniture performs an integrity check on furniture assets.
-func (s *Service) CheckFurniture(ctx context.Context, checkDB bool) (*models.Report, error) {
+// With checkDB, it returns database.ErrUnavailable while the database is not connected.
+func (s *Service) CheckFurniture(ctx context.Context, checkDB bool) (_ *models.Report, err error) {
+	ctx = s.events.Observe(ctx, "integrity_furniture")
+	defer func() { s.events.Done(ctx, "integrity_furniture", err) }()
+
 	var db *gorm.DB
 	if checkDB {
-		db = s.db
+		if db = s.database(); db == nil {
+			return nil, database.ErrUnavailable
+		}
 	}
 	return furnitureIntegrity.CheckIntegrity(ctx, s.client, s.bucket, db, s.emulator)
 }
 
 // CheckServer performs an integrity check on the emulator database schema.
+// It returns database.ErrUnavailable while the database is not connected.
 func (s *Service) CheckServer() (*checks.ServerReport, error) {
-	if s.db == nil {
-		return nil, nil // Or specific error? "Database not connected"
+	db := s.database()
+	if db == nil {
+		return nil, database.ErrUnavailable
+	}
+	return checks.CheckServerIntegrity(db, s.emulator)
+}
+
+// RunAll performs every integrity check and returns the combined report keyed by check name.
+// Failed checks are reported inline rather than aborting the run. If progress is not nil,
+// it is called before each check.
+func (s *Service) RunAll(ctx context.Context, progress func(done, total int, step string)) map[string]any {
+	const total = 5
+	report := make(map[string]any)
+	step := func(done int, name string) {
+		if progress != nil {
+			progress(done, total, name)
+		}
 	}
-	return checks.CheckServerIntegrity(s.db, s.emulator)
+
+	// Structure
+	step(0, "structure")
+	if missing, err := s.CheckStructure(ctx); err != nil {
+		report["structure"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["structure"] = map[string]any{"status": "ok", "missing": missing}
+	}
+
+	// Bundled
+	step(1, "bundled")
+	if missing, err := s.CheckBundled(ctx); err != nil {
+		report["bundled"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["bundled"] = map[string]any{"status": "ok", "missing": missing}
+	}
+
+	// GameData
+	step(2, "gamedata")
+	if missing, err := s.CheckGameData(ctx); err != nil {
+		report["gamedata"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["gamedata"] = map[string]any{"status": "ok", "missing": missing}
+	}
+
+	// Server
+	step(3, "server")
+	if srvReport, err := s.CheckServer(); err != nil {
+		report["server"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["server"] = srvReport
+	}
+
+	// Furniture (Slow)
+	step(4, "furniture")
+	if furnReport, err := s.CheckFurniture(ctx, false); err != nil {
+		report["furniture"] = map[string]any{"status": "error", "error": err.Error()}
+	} else {
+		report["furniture"] = furnReport
+	}
+	step(total, "done")
+
+	return report
 }
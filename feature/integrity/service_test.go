@@ -6,6 +6,7 @@ import (
 	"io"
 	"testing"
 
+	"asset-manager/core/database"
 	"asset-manager/core/storage/mocks"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -145,3 +146,21 @@ func TestService_Furniture(t *testing.T) {
 		assert.NotNil(t, report)
 	})
 }
+
+func TestService_SetDB(t *testing.T) {
+	svc := NewService(new(mocks.Client), "test-bucket", zap.NewNop(), nil, "arcturus")
+
+	// Without a database, DB-backed checks report it as unavailable
+	_, err := svc.CheckServer()
+	assert.ErrorIs(t, err, database.ErrUnavailable)
+
+	db, sqlMock := setupMockDB(t)
+	sqlMock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	sqlMock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	sqlMock.ExpectQuery(".*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	svc.SetDB(db)
+
+	report, err := svc.CheckServer()
+	assert.NoError(t, err)
+	assert.NotNil(t, report)
+}
@@ -0,0 +1,24 @@
+// Package jobs exposes the background job system over HTTP.
+//
+// Long-running operations such as full integrity checks and furniture reconciles
+// time out when executed inside a request behind a proxy. Instead, clients submit
+// a job, poll its status and progress, and fetch the result once it finishes.
+//
+// # Job Types
+//
+// Job types are registered by other features (see integrity.Feature.RegisterJobs and
+// furniture.Feature.RegisterJobs):
+//   - integrity: Runs all integrity checks (same report as GET /integrity).
+//   - integrity_furniture: Runs the furniture integrity check (param "db": "true" to include DB checks).
+//   - reconcile_furniture: Builds a dry-run furniture reconcile plan and returns its summary.
+//
+// Reconcile-based jobs report the engine phase (load_db, load_gamedata, load_storage)
+// as the progress message.
+//
+// # HTTP Endpoints
+//
+//   - POST /jobs : Submit a job ({"type": "...", "params": {...}}), returns 202 with the job.
+//   - GET /jobs : List recent jobs.
+//   - GET /jobs/:id : Get status, progress and result of a job.
+//   - DELETE /jobs/:id : Cancel a pending or running job.
+package jobs
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"errors"
+
+	"asset-manager/core/jobs"
+	"asset-manager/core/logger"
+	"asset-manager/core/middleware/auth"
+	"asset-manager/core/middleware/rayid"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// defaultListLimit is the number of jobs returned by GET /jobs without a limit.
+const defaultListLimit = 50
+
+// SubmitRequest is the body of POST /jobs.
+type SubmitRequest struct {
+	// Type is the registered job type (e.g. "integrity").
+	Type string `json:"type"`
+	// Params are optional job options.
+	Params map[string]string `json:"params"`
+}
+
+// Handler handles HTTP requests for background jobs.
+type Handler struct {
+	manager *jobs.Manager
+	logger  *zap.Logger
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(manager *jobs.Manager, logger *zap.Logger) *Handler {
+	return &Handler{manager: manager, logger: logger}
+}
+
+// RegisterRoutes registers the job routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	read := auth.Require(auth.ScopeRead)
+	write := auth.Require(auth.ScopeJobsWrite)
+
+	group := app.Group("/jobs")
+	group.Post("/", write, h.HandleSubmit)
+	group.Get("/", read, h.HandleList)
+	group.Get("/:id", read, h.HandleGet)
+	group.Delete("/:id", write, h.HandleCancel)
+}
+
+// HandleSubmit starts a job in the background.
+// @Summary Submit Job
+// @Description Queues a long-running operation (integrity, integrity_furniture, reconcile_furniture) and returns immediately.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param request body SubmitRequest true "Job type and params"
+// @Success 202 {object} jobs.Job "Queued Job"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Requires jobs:write Scope"
+// @Failure 503 {object} map[string]string "Queue Full"
+// @Router /jobs [post]
+func (h *Handler) HandleSubmit(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.logger, c)
+
+	var req SubmitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Type == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "type is required",
+			"types": h.manager.Types(),
+		})
+	}
+
+	job, err := h.manager.Submit(rayid.Context(c), req.Type, req.Params)
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrUnknownType):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+				"types": h.manager.Types(),
+			})
+		case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, jobs.ErrShuttingDown):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+		default:
+			l.Error("Job submission failed", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	l.Info("Job submitted", zap.String("job_id", job.ID), zap.String("type", job.Type))
+	c.Location("/jobs/" + job.ID)
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// HandleList lists recent jobs.
+// @Summary List Jobs
+// @Description Lists the most recent jobs, newest first.
+// @Tags jobs
+// @Produce json
+// @Param limit query int false "Maximum number of jobs (default 50)"
+// @Success 200 {array} jobs.Job "Jobs"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /jobs [get]
+func (h *Handler) HandleList(c *fiber.Ctx) error {
+	list, err := h.manager.List(c.QueryInt("limit", defaultListLimit))
+	if err != nil {
+		logger.WithRayID(h.logger, c).Error("Failed to list jobs", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+// HandleGet returns the status, progress and result of a job.
+// @Summary Get Job
+// @Description Returns the status, progress and (once finished) the result of a job.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Job"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /jobs/{id} [get]
+func (h *Handler) HandleGet(c *fiber.Ctx) error {
+	job, err := h.manager.Get(c.Params("id"))
+	if err != nil {
+		return h.jobError(c, err)
+	}
+	return c.JSON(job)
+}
+
+// HandleCancel cancels a pending or running job.
+// @Summary Cancel Job
+// @Description Cancels a pending or running job. Running jobs stop at their next cancellation check.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Job"
+// @Failure 403 {object} map[string]string "Requires jobs:write Scope"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Already Finished"
+// @Router /jobs/{id} [delete]
+func (h *Handler) HandleCancel(c *fiber.Ctx) error {
+	job, err := h.manager.Cancel(c.Params("id"))
+	if err != nil {
+		return h.jobError(c, err)
+	}
+	logger.WithRayID(h.logger, c).Info("Job cancelled", zap.String("job_id", job.ID))
+	return c.JSON(job)
+}
+
+// jobError maps job lookup errors to HTTP responses.
+func (h *Handler) jobError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, jobs.ErrFinished):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		logger.WithRayID(h.logger, c).Error("Job lookup failed", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
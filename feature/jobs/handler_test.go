@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/jobs"
+	"asset-manager/core/middleware/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupTestApp(t *testing.T, run jobs.RunFunc) (*fiber.App, *jobs.Manager) {
+	manager := jobs.NewManager(jobs.Config{Workers: 1, QueueSize: 2}, jobs.NewMemoryStore(10), zap.NewNop())
+	manager.Register("test", run)
+	manager.Start()
+	t.Cleanup(func() { _ = manager.Shutdown(context.Background()) })
+
+	app := fiber.New()
+	app.Use(auth.WithKey(auth.Key{Name: "test", Scopes: []string{auth.ScopeAll}}))
+	require.NoError(t, NewFeature(manager, zap.NewNop()).Load(app))
+	return app, manager
+}
+
+func TestHandler_SubmitAndGet(t *testing.T) {
+	app, manager := setupTestApp(t, func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		return map[string]int{"total": 3}, nil
+	})
+
+	req := httptest.NewRequest("POST", "/jobs", strings.NewReader(`{"type":"test","params":{"db":"true"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+	var submitted jobs.Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+	assert.Equal(t, "/jobs/"+submitted.ID, resp.Header.Get("Location"))
+	assert.Equal(t, map[string]string{"db": "true"}, submitted.Params)
+
+	require.Eventually(t, func() bool {
+		job, err := manager.Get(submitted.ID)
+		return err == nil && job.Status == jobs.StatusSucceeded
+	}, 2*time.Second, 5*time.Millisecond)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/jobs/"+submitted.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var job jobs.Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	assert.Equal(t, jobs.StatusSucceeded, job.Status)
+	assert.JSONEq(t, `{"total":3}`, string(job.Result))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/jobs", nil))
+	require.NoError(t, err)
+	var list []jobs.Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.Len(t, list, 1)
+
+	// Cancelling a finished job conflicts
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/jobs/"+submitted.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	app, _ := setupTestApp(t, func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		return nil, nil
+	})
+
+	req := httptest.NewRequest("POST", "/jobs", strings.NewReader(`{"type":"unknown"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/jobs/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/jobs/missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	app, manager := setupTestApp(t, func(ctx context.Context, params map[string]string, report jobs.ProgressFunc) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	job, err := manager.Submit(context.Background(), "test", nil)
+	require.NoError(t, err)
+	<-started
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/jobs/"+job.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.Eventually(t, func() bool {
+		current, err := manager.Get(job.ID)
+		return err == nil && current.Status == jobs.StatusCancelled
+	}, 2*time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,21 @@
+// Package notify receives S3/MinIO bucket notifications so cached reconcile indices
+// follow the bucket without waiting for their TTL.
+//
+// Configure a webhook target on the bucket pointing at the endpoint, with the auth token
+// set to STORAGE_NOTIFY_TOKEN, e.g.:
+//
+//	mc admin config set myminio notify_webhook:assets endpoint="http://asset-manager:8080/storage/events" auth_token="<token>"
+//	mc event add myminio/assets arn:minio:sqs::assets:webhook --event put,delete
+//
+// Object creations and removals of the configured bucket are passed to every Sink (e.g.
+// the furniture feature), which patches the storage set of its cached indices and drops
+// them when the gamedata changes.
+//
+// # HTTP Endpoints
+//
+//   - POST /storage/events : Standard S3 event JSON. Requires the token in the
+//     Authorization header, with or without the "Bearer " prefix.
+//
+// MinIO cannot send the API key header, so the endpoint is registered before the API
+// key middleware and checks its own token. It is disabled while no token is configured.
+package notify
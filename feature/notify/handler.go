@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"asset-manager/core/logger"
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Sink receives the object events of the configured bucket.
+type Sink interface {
+	// HandleStorageEvents updates state derived from the bucket after objects changed.
+	HandleStorageEvents(events []storage.ObjectEvent)
+}
+
+// Handler handles bucket notification webhooks.
+type Handler struct {
+	bucket string
+	token  string
+	logger *zap.Logger
+	sinks  []Sink
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(bucket, token string, logger *zap.Logger, sinks ...Sink) *Handler {
+	return &Handler{bucket: bucket, token: token, logger: logger, sinks: sinks}
+}
+
+// RegisterRoutes registers the notify routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Post("/storage/events", h.HandleEvents)
+}
+
+// HandleEvents applies a bucket notification to the cached indices.
+// @Summary Bucket Notification Webhook
+// @Description Receives S3/MinIO bucket notifications (standard event JSON). Created and removed objects of the configured bucket update the cached reconcile indices; a FurnitureData.json change drops them. Authenticated with STORAGE_NOTIFY_TOKEN in the Authorization header instead of the API key.
+// @Tags storage
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Webhook token"
+// @Success 200 {object} map[string]int "Accepted event count"
+// @Failure 400 {object} map[string]string "Invalid Notification"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /storage/events [post]
+func (h *Handler) HandleEvents(c *fiber.Ctx) error {
+	l := logger.WithRayID(h.logger, c)
+
+	token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid or missing webhook token"})
+	}
+
+	received, err := storage.ParseNotification(c.Body())
+	if err != nil {
+		l.Warn("Invalid bucket notification", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Only the configured bucket backs the indices
+	events := make([]storage.ObjectEvent, 0, len(received))
+	for _, event := range received {
+		if event.Bucket == h.bucket {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) > 0 {
+		l.Debug("Bucket notification received", zap.Int("events", len(events)))
+		for _, sink := range h.sinks {
+			sink.HandleStorageEvents(events)
+		}
+	}
+
+	return c.JSON(fiber.Map{"accepted": len(events)})
+}
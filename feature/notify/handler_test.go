@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingSink records the events it receives.
+type recordingSink struct {
+	events []storage.ObjectEvent
+}
+
+func (s *recordingSink) HandleStorageEvents(events []storage.ObjectEvent) {
+	s.events = append(s.events, events...)
+}
+
+const notification = `{"Records": [
+	{"eventName": "s3:ObjectCreated:Put", "s3": {"bucket": {"name": "assets"}, "object": {"key": "bundled/furniture/chair.nitro"}}},
+	{"eventName": "s3:ObjectRemoved:Delete", "s3": {"bucket": {"name": "other"}, "object": {"key": "bundled/furniture/sofa.nitro"}}}
+]}`
+
+func TestHandler_HandleEvents(t *testing.T) {
+	sink := &recordingSink{}
+	feature := NewFeature(storage.Config{Bucket: "assets", NotifyToken: "secret"}, zap.NewNop(), sink)
+	require.True(t, feature.IsEnabled())
+
+	app := fiber.New()
+	require.NoError(t, feature.Load(app))
+
+	post := func(token, body string) int {
+		req := httptest.NewRequest("POST", "/storage/events", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Token is required, with or without the Bearer prefix
+	assert.Equal(t, 401, post("", notification))
+	assert.Equal(t, 401, post("wrong", notification))
+	assert.Empty(t, sink.events)
+
+	assert.Equal(t, 200, post("secret", notification))
+	assert.Equal(t, 200, post("Bearer secret", notification))
+	assert.Equal(t, 400, post("secret", "not json"))
+
+	// Events of other buckets are dropped
+	require.Len(t, sink.events, 2)
+	assert.Equal(t, "bundled/furniture/chair.nitro", sink.events[0].Key)
+	assert.False(t, sink.events[0].Removed)
+}
+
+func TestHandler_HandleEvents_Response(t *testing.T) {
+	app := fiber.New()
+	require.NoError(t, NewFeature(storage.Config{Bucket: "assets", NotifyToken: "secret"}, zap.NewNop()).Load(app))
+
+	req := httptest.NewRequest("POST", "/storage/events", strings.NewReader(notification))
+	req.Header.Set("Authorization", "secret")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var body map[string]int
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 1, body["accepted"])
+
+	// Disabled without a token
+	assert.False(t, NewFeature(storage.Config{}, zap.NewNop()).IsEnabled())
+}
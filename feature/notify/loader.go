@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"asset-manager/core/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Feature implements the loader.Feature interface.
+type Feature struct {
+	handler *Handler
+	token   string
+}
+
+// NewFeature creates a new Notify feature passing the object events of the configured
+// bucket to the sinks.
+func NewFeature(cfg storage.Config, logger *zap.Logger, sinks ...Sink) *Feature {
+	return &Feature{
+		handler: NewHandler(cfg.Bucket, cfg.NotifyToken, logger, sinks...),
+		token:   cfg.NotifyToken,
+	}
+}
+
+// Name returns the name of the feature.
+func (f *Feature) Name() string {
+	return "notify"
+}
+
+// IsEnabled checks if the feature is enabled, i.e. a webhook token is configured.
+func (f *Feature) IsEnabled() bool {
+	return f.token != ""
+}
+
+// Load registers the feature's routes.
+func (f *Feature) Load(app fiber.Router) error {
+	f.handler.RegisterRoutes(app)
+	return nil
+}
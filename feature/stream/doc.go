@@ -0,0 +1,14 @@
+// Package stream exposes live integrity and reconcile progress as Server-Sent Events.
+//
+// The admin panel subscribes once and receives events from every run in the process:
+// phase changes, processed/total counts and the outcome of each action applied by
+// ApplyPlan. Every event carries the Ray ID of the request (or job submission) that
+// started the run, so clients can follow a single operation.
+//
+// # HTTP Endpoints
+//
+//   - GET /reconcile/stream : Event stream (text/event-stream). Optional filters:
+//     ?ray_id=<id> to follow one run, ?source=<name> (e.g. reconcile_furniture).
+//
+// The endpoint sits behind the API key middleware like every other route.
+package stream
@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"asset-manager/core/events"
+	"asset-manager/core/logger"
+	"asset-manager/core/middleware/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// keepAliveInterval is how often a comment is sent on idle streams so proxies keep them open.
+const keepAliveInterval = 15 * time.Second
+
+// Handler handles the Server-Sent Events stream.
+type Handler struct {
+	hub       *events.Hub
+	logger    *zap.Logger
+	keepAlive time.Duration
+}
+
+// NewHandler creates a new HTTP handler.
+func NewHandler(hub *events.Hub, logger *zap.Logger) *Handler {
+	return &Handler{hub: hub, logger: logger, keepAlive: keepAliveInterval}
+}
+
+// RegisterRoutes registers the stream routes.
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	app.Get("/reconcile/stream", auth.Require(auth.ScopeRead), h.HandleStream)
+}
+
+// HandleStream streams live integrity and reconcile events.
+// @Summary Stream Reconcile Progress
+// @Description Server-Sent Events stream of phase changes, progress counts and applied action results of running integrity checks and reconciles. Each event carries the Ray ID of the run.
+// @Tags reconcile
+// @Produce text/event-stream
+// @Param ray_id query string false "Only events of the run started by this Ray ID"
+// @Param source query string false "Only events of this source (e.g. reconcile_furniture)"
+// @Success 200 {object} events.Event "Event stream"
+// @Router /reconcile/stream [get]
+func (h *Handler) HandleStream(c *fiber.Ctx) error {
+	rid := c.Query("ray_id")
+	source := c.Query("source")
+
+	l := logger.WithRayID(h.logger, c)
+	l.Info("Event stream opened", zap.String("filter_ray_id", rid), zap.String("filter_source", source))
+
+	ch, unsubscribe := h.hub.Subscribe()
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(h.keepAlive)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					return
+				}
+				if (rid != "" && e.RayID != rid) || (source != "" && e.Source != source) {
+					continue
+				}
+				data, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+
+			// A failed flush means the client disconnected
+			if err := w.Flush(); err != nil {
+				l.Info("Event stream closed")
+				return
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"asset-manager/core/events"
+	"asset-manager/core/middleware/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler_HandleStream(t *testing.T) {
+	hub := events.NewHub(16)
+	app := fiber.New()
+	app.Use(auth.WithKey(auth.Key{Name: "test", Scopes: []string{auth.ScopeAll}}))
+	require.NoError(t, NewFeature(hub, zap.NewNop()).Load(app))
+
+	// Publish once the stream is subscribed, then end it
+	go func() {
+		for hub.Subscribers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		hub.Publish(events.Event{Type: events.TypeProgress, Source: "reconcile_furniture", RayID: "other", Processed: 1})
+		hub.Publish(events.Event{Type: events.TypeAction, Source: "reconcile_furniture", RayID: "ray-1",
+			Action: &events.ActionResult{Type: "delete_db", Key: "7"}})
+		time.Sleep(10 * time.Millisecond)
+		hub.Close()
+	}()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reconcile/stream?ray_id=ray-1", nil), 2000)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(bufio.NewReader(resp.Body))
+	require.NoError(t, err)
+
+	stream := string(body)
+	assert.True(t, strings.HasPrefix(stream, ": connected\n\n"))
+	assert.Contains(t, stream, "event: action\ndata: {")
+	assert.Contains(t, stream, `"ray_id":"ray-1"`)
+	assert.NotContains(t, stream, `"ray_id":"other"`, "Events of other runs are filtered out")
+}